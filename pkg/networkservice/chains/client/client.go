@@ -1,5 +1,7 @@
 // Copyright (c) 2020 Cisco Systems, Inc.
 //
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
 // SPDX-License-Identifier: Apache-2.0
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
@@ -20,6 +22,7 @@ package client
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
@@ -27,6 +30,7 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/authorize"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/heal"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanismtranslation"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/metrics"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/refresh"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/serialize"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatepath"
@@ -34,6 +38,7 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injectpeer"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/scheduler"
 	"github.com/networkservicemesh/sdk/pkg/tools/token"
 )
 
@@ -53,20 +58,53 @@ import (
 //             - cc - grpc.ClientConnInterface for the endpoint to which this client should connect
 //             - additionalFunctionality - any additional NetworkServiceClient chain elements to be included in the chain
 func NewClient(ctx context.Context, name string, onHeal *networkservice.NetworkServiceClient, tokenGenerator token.GeneratorFunc, cc grpc.ClientConnInterface, additionalFunctionality ...networkservice.NetworkServiceClient) networkservice.NetworkServiceClient {
+	return NewClientWithOptions(ctx, tokenGenerator, cc,
+		WithName(name),
+		WithHeal(onHeal),
+		WithAdditionalFunctionality(additionalFunctionality...),
+	)
+}
+
+// NewClientWithOptions - same as NewClient, but configured with options
+//             - ctx    - context for the lifecycle of the *Client* itself.  Cancel when discarding the client.
+//             - tokenGenerator - token.GeneratorFunc - generates tokens for use in Path
+//             - cc - grpc.ClientConnInterface for the endpoint to which this client should connect
+//             - options - WithName, WithHeal, WithHealOptions, WithRefreshOptions, WithAuthorizeClient, WithAdditionalFunctionality, WithMetrics
+func NewClientWithOptions(ctx context.Context, tokenGenerator token.GeneratorFunc, cc grpc.ClientConnInterface, options ...Option) networkservice.NetworkServiceClient {
+	opts := &clientOptions{
+		name:            "client-" + uuid.New().String(),
+		authorizeClient: authorize.NewClient(),
+	}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	// All the client connection timers share the same scheduler
+	ctx = scheduler.WithScheduler(ctx, scheduler.FromContext(ctx))
+
 	var rv networkservice.NetworkServiceClient
+	onHeal := opts.onHeal
 	if onHeal == nil {
 		onHeal = &rv
 	}
+
+	clients := []networkservice.NetworkServiceClient{
+		opts.authorizeClient,
+		updatepath.NewClient(opts.name),
+		serialize.NewClient(),
+		heal.NewClient(ctx, networkservice.NewMonitorConnectionClient(cc), onHeal, opts.healOptions...),
+		refresh.NewClient(ctx, opts.refreshOptions...),
+	}
+	if opts.withMetrics {
+		// `metrics` goes right after the `refresh` to count the refresh Requests as well.
+		clients = append(clients, metrics.NewClient(
+			append([]metrics.Option{metrics.WithChainName(opts.name)}, opts.metricsOptions...)...))
+	}
+	clients = append(clients, metadata.NewClient())
+
 	rv = chain.NewNetworkServiceClient(
 		append(
-			append([]networkservice.NetworkServiceClient{
-				authorize.NewClient(),
-				updatepath.NewClient(name),
-				serialize.NewClient(),
-				heal.NewClient(ctx, networkservice.NewMonitorConnectionClient(cc), onHeal),
-				refresh.NewClient(ctx),
-				metadata.NewClient(),
-			}, additionalFunctionality...),
+			append(clients, opts.additionalFunctionality...),
 			injectpeer.NewClient(),
 			updatetoken.NewClient(tokenGenerator),
 			networkservice.NewNetworkServiceClient(cc),
@@ -76,17 +114,35 @@ func NewClient(ctx context.Context, name string, onHeal *networkservice.NetworkS
 
 // NewCrossConnectClientFactory - returns a (2.) case func(cc grpc.ClientConnInterface) NSM client factory.
 func NewCrossConnectClientFactory(name string, onHeal *networkservice.NetworkServiceClient, tokenGenerator token.GeneratorFunc, additionalFunctionality ...networkservice.NetworkServiceClient) func(ctx context.Context, cc grpc.ClientConnInterface) networkservice.NetworkServiceClient {
+	return NewCrossConnectClientFactoryWithOptions(tokenGenerator,
+		WithName(name),
+		WithHeal(onHeal),
+		WithAdditionalFunctionality(additionalFunctionality...),
+	)
+}
+
+// NewCrossConnectClientFactoryWithOptions - same as NewCrossConnectClientFactory, but configured with options
+func NewCrossConnectClientFactoryWithOptions(tokenGenerator token.GeneratorFunc, options ...Option) func(ctx context.Context, cc grpc.ClientConnInterface) networkservice.NetworkServiceClient {
 	return func(ctx context.Context, cc grpc.ClientConnInterface) networkservice.NetworkServiceClient {
 		return chain.NewNetworkServiceClient(
 			mechanismtranslation.NewClient(),
-			NewClient(ctx, name, onHeal, tokenGenerator, cc, additionalFunctionality...),
+			NewClientWithOptions(ctx, tokenGenerator, cc, options...),
 		)
 	}
 }
 
 // NewClientFactory - returns a (3.) case func(cc grpc.ClientConnInterface) NSM client factory.
 func NewClientFactory(name string, onHeal *networkservice.NetworkServiceClient, tokenGenerator token.GeneratorFunc, additionalFunctionality ...networkservice.NetworkServiceClient) func(ctx context.Context, cc grpc.ClientConnInterface) networkservice.NetworkServiceClient {
+	return NewClientFactoryWithOptions(tokenGenerator,
+		WithName(name),
+		WithHeal(onHeal),
+		WithAdditionalFunctionality(additionalFunctionality...),
+	)
+}
+
+// NewClientFactoryWithOptions - same as NewClientFactory, but configured with options
+func NewClientFactoryWithOptions(tokenGenerator token.GeneratorFunc, options ...Option) func(ctx context.Context, cc grpc.ClientConnInterface) networkservice.NetworkServiceClient {
 	return func(ctx context.Context, cc grpc.ClientConnInterface) networkservice.NetworkServiceClient {
-		return NewClient(ctx, name, onHeal, tokenGenerator, cc, additionalFunctionality...)
+		return NewClientWithOptions(ctx, tokenGenerator, cc, options...)
 	}
 }
@@ -0,0 +1,102 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/heal"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/metrics"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/refresh"
+)
+
+type clientOptions struct {
+	name                    string
+	onHeal                  *networkservice.NetworkServiceClient
+	healOptions             []heal.Option
+	refreshOptions          []refresh.Option
+	authorizeClient         networkservice.NetworkServiceClient
+	additionalFunctionality []networkservice.NetworkServiceClient
+	metricsOptions          []metrics.Option
+	withMetrics             bool
+}
+
+// Option modifies client option value
+type Option func(o *clientOptions)
+
+// WithName sets name of the NetworkServiceMeshClient
+func WithName(name string) Option {
+	return func(o *clientOptions) {
+		o.name = name
+	}
+}
+
+// WithHeal sets onHeal - *networkservice.NetworkServiceClient.  Since networkservice.NetworkServiceClient is an
+// interface (and thus a pointer) *networkservice.NetworkServiceClient is a double pointer.  Meaning it points to a
+// place that points to a place that implements networkservice.NetworkServiceClient.
+// This is done because when we use heal.NewClient as part of a chain, we may not *have* a pointer to this client used
+// 'onHeal'.  If we detect we need to heal, onHeal.Request is used to heal.
+// If we are part of a larger chain or a server, we should pass the resulting chain into this constructor before we
+// actually have a pointer to it.
+// If onHeal is not set, onHeal will be pointed to the returned networkservice.NetworkServiceClient
+func WithHeal(onHeal *networkservice.NetworkServiceClient) Option {
+	return func(o *clientOptions) {
+		o.onHeal = onHeal
+	}
+}
+
+// WithHealOptions sets the heal chain element options, e.g. heal.WithReselect() to heal the connection with a new
+// NSE selection
+func WithHealOptions(healOptions ...heal.Option) Option {
+	return func(o *clientOptions) {
+		o.healOptions = healOptions
+	}
+}
+
+// WithRefreshOptions sets the refresh chain element options, e.g. refresh.WithOnFailure(...) to get notified about the
+// connections failed to be refreshed before they expire
+func WithRefreshOptions(refreshOptions ...refresh.Option) Option {
+	return func(o *clientOptions) {
+		o.refreshOptions = refreshOptions
+	}
+}
+
+// WithAuthorizeClient sets authorization client chain element
+func WithAuthorizeClient(authorizeClient networkservice.NetworkServiceClient) Option {
+	if authorizeClient == nil {
+		panic("Authorize client cannot be nil")
+	}
+	return func(o *clientOptions) {
+		o.authorizeClient = authorizeClient
+	}
+}
+
+// WithAdditionalFunctionality sets additional NetworkServiceClient chain elements to be included in the chain
+func WithAdditionalFunctionality(additionalFunctionality ...networkservice.NetworkServiceClient) Option {
+	return func(o *clientOptions) {
+		o.additionalFunctionality = additionalFunctionality
+	}
+}
+
+// WithMetrics enables metrics collection for the NetworkServiceMeshClient. Metrics are labeled with the client name
+// as the chain name by default.
+func WithMetrics(metricsOptions ...metrics.Option) Option {
+	return func(o *clientOptions) {
+		o.withMetrics = true
+		o.metricsOptions = metricsOptions
+	}
+}
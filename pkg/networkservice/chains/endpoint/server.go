@@ -1,6 +1,6 @@
 // Copyright (c) 2020 Cisco Systems, Inc.
 //
-// Copyright (c) 2020 Doc.ai and/or its affiliates.
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -23,14 +23,19 @@ import (
 	"context"
 	"net/url"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/authorize"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/drain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/metrics"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/serialize"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatetoken"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
 	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/scheduler"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/monitor"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/timeout"
@@ -42,9 +47,11 @@ import (
 // Endpoint - aggregates the APIs:
 //            - networkservice.NetworkServiceServer
 //            - networkservice.MonitorConnectionServer
+//            - drain.Drainer
 type Endpoint interface {
 	networkservice.NetworkServiceServer
 	networkservice.MonitorConnectionServer
+	drain.Drainer
 	// Register - register the endpoint with *grpc.Server s
 	Register(s *grpc.Server)
 }
@@ -52,6 +59,51 @@ type Endpoint interface {
 type endpoint struct {
 	networkservice.NetworkServiceServer
 	networkservice.MonitorConnectionServer
+	drain.Drainer
+}
+
+type serverOptions struct {
+	name                    string
+	authorizeServer         networkservice.NetworkServiceServer
+	additionalFunctionality []networkservice.NetworkServiceServer
+	metricsOptions          []metrics.Option
+	withMetrics             bool
+}
+
+// Option modifies server option value
+type Option func(o *serverOptions)
+
+// WithName sets name of the NetworkServiceServer
+func WithName(name string) Option {
+	return func(o *serverOptions) {
+		o.name = name
+	}
+}
+
+// WithAuthorizeServer sets authorization server chain element
+func WithAuthorizeServer(authorizeServer networkservice.NetworkServiceServer) Option {
+	if authorizeServer == nil {
+		panic("Authorize server cannot be nil")
+	}
+	return func(o *serverOptions) {
+		o.authorizeServer = authorizeServer
+	}
+}
+
+// WithAdditionalFunctionality sets additional NetworkServiceServer chain elements to be included in the chain
+func WithAdditionalFunctionality(additionalFunctionality ...networkservice.NetworkServiceServer) Option {
+	return func(o *serverOptions) {
+		o.additionalFunctionality = additionalFunctionality
+	}
+}
+
+// WithMetrics enables metrics collection for the NetworkServiceServer. Metrics are labeled with the server name as
+// the chain name by default.
+func WithMetrics(metricsOptions ...metrics.Option) Option {
+	return func(o *serverOptions) {
+		o.withMetrics = true
+		o.metricsOptions = metricsOptions
+	}
 }
 
 // NewServer - returns a NetworkServiceMesh client as a chain of the standard Client pieces plus whatever
@@ -61,21 +113,55 @@ type endpoint struct {
 //             - tokenGenerator - token.GeneratorFunc - generates tokens for use in Path
 //             - additionalFunctionality - any additional NetworkServiceServer chain elements to be included in the chain
 func NewServer(ctx context.Context, name string, authzServer networkservice.NetworkServiceServer, tokenGenerator token.GeneratorFunc, additionalFunctionality ...networkservice.NetworkServiceServer) Endpoint {
+	return NewServerWithOptions(ctx, tokenGenerator,
+		WithName(name),
+		WithAuthorizeServer(authzServer),
+		WithAdditionalFunctionality(additionalFunctionality...),
+	)
+}
+
+// NewServerWithOptions - same as NewServer, but configured with options
+//             - tokenGenerator - token.GeneratorFunc - generates tokens for use in Path
+//             - options - WithName, WithAuthorizeServer, WithAdditionalFunctionality, WithMetrics
+func NewServerWithOptions(ctx context.Context, tokenGenerator token.GeneratorFunc, options ...Option) Endpoint {
+	opts := &serverOptions{
+		name:            "endpoint-" + uuid.New().String(),
+		authorizeServer: authorize.NewServer(),
+	}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	// All the endpoint connection timers share the same scheduler
+	ctx = scheduler.WithScheduler(ctx, scheduler.FromContext(ctx))
+
 	rv := &endpoint{}
+	servers := []networkservice.NetworkServiceServer{
+		opts.authorizeServer,
+		updatepath.NewServer(opts.name),
+		serialize.NewServer(),
+		// `drain` goes before the `timeout` to not arm timers for the rejected Requests and to close the
+		// connections with the timers on Drain. It forgets the connections on the same expiration the `timeout`
+		// closes them.
+		drain.NewServer(ctx, &rv.Drainer),
+		// `timeout` uses ctx as a context for the timeout Close and it closes only the subsequent chain, so
+		// chain elements before the `timeout` in chain shouldn't make any updates to the Close context and
+		// shouldn't be closed on Connection Close.
+		timeout.NewServer(ctx),
+	}
+	if opts.withMetrics {
+		// `metrics` goes right after the `timeout` to count the timeout Close as well.
+		servers = append(servers, metrics.NewServer(
+			append([]metrics.Option{metrics.WithChainName(opts.name)}, opts.metricsOptions...)...))
+	}
+	servers = append(servers,
+		metadata.NewServer(),
+		monitor.NewServer(ctx, &rv.MonitorConnectionServer),
+		updatetoken.NewServer(tokenGenerator),
+	)
 	rv.NetworkServiceServer = chain.NewNamedNetworkServiceServer(
-		name,
-		append([]networkservice.NetworkServiceServer{
-			authzServer,
-			updatepath.NewServer(name),
-			serialize.NewServer(),
-			// `timeout` uses ctx as a context for the timeout Close and it closes only the subsequent chain, so
-			// chain elements before the `timeout` in chain shouldn't make any updates to the Close context and
-			// shouldn't be closed on Connection Close.
-			timeout.NewServer(ctx),
-			metadata.NewServer(),
-			monitor.NewServer(ctx, &rv.MonitorConnectionServer),
-			updatetoken.NewServer(tokenGenerator),
-		}, additionalFunctionality...)...)
+		opts.name,
+		append(servers, opts.additionalFunctionality...)...)
 	return rv
 }
 
@@ -0,0 +1,88 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsmgr
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/nsmgr/peertracker"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/netnsgc"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/persist"
+)
+
+type serverOptions struct {
+	dialOptions    []grpc.DialOption
+	stateStore     persist.Store
+	netnsGC        bool
+	netnsGCOptions []netnsgc.Option
+	explain        bool
+	reaperService  bool
+	peerOptions    []peertracker.Option
+}
+
+// Option modifies default Nsmgr server values
+type Option func(o *serverOptions)
+
+// WithDialOptions sets gRPC dial options for the connections to the forwarders, endpoints and remote Nsmgrs
+func WithDialOptions(dialOptions ...grpc.DialOption) Option {
+	return func(o *serverOptions) {
+		o.dialOptions = dialOptions
+	}
+}
+
+// WithStateStore sets the connection state store. Nsmgr saves the established connections into the store and
+// restores them from it on start, so the clients healing against the restarted Nsmgr find their existing connections.
+func WithStateStore(stateStore persist.Store) Option {
+	return func(o *serverOptions) {
+		o.stateStore = stateStore
+	}
+}
+
+// WithNetNSGC enables closing the kernel mechanism connections whose client network namespace has disappeared. With
+// the default liveness check Nsmgr should see the client processes in /proc.
+func WithNetNSGC(options ...netnsgc.Option) Option {
+	return func(o *serverOptions) {
+		o.netnsGC = true
+		o.netnsGCOptions = options
+	}
+}
+
+// WithExplain enables the explain.ExplainService debug gRPC service explaining the endpoint selection. Explain
+// requests are authorized with the Nsmgr authorization server the same way as the Requests.
+func WithExplain() Option {
+	return func(o *serverOptions) {
+		o.explain = true
+	}
+}
+
+// WithReaperService enables the reaper.ReaperService debug gRPC service listing the connections failed to Close. Only the
+// connections the caller is authorized to Close with the Nsmgr authorization server are listed.
+func WithReaperService() Option {
+	return func(o *serverOptions) {
+		o.reaperService = true
+	}
+}
+
+// WithPeerGracePeriod sets the time for the disconnected remote (non unix socket) clients to reconnect and to refresh
+// their connections before Nsmgr closes them. Default is 1m.
+func WithPeerGracePeriod(gracePeriod time.Duration) Option {
+	return func(o *serverOptions) {
+		o.peerOptions = append(o.peerOptions, peertracker.WithGracePeriod(gracePeriod))
+	}
+}
@@ -1,5 +1,7 @@
 // Copyright (c) 2020 Cisco and/or its affiliates.
 //
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
 // SPDX-License-Identifier: Apache-2.0
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
@@ -14,99 +16,242 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package peertracker provides a wrapper for a Nsmgr that tracks connections received from local Clients
-// Its designed to be used in a DevicePlugin to allow us to properly Close connections on re-Allocate
+// Package peertracker provides a NetworkServiceServer chain element tracking the connections requested by the gRPC
+// clients (peers), so the connections of the local Client are closed as soon as its process dies instead of waiting
+// for the timeout. Remote peers can reconnect after the network failure, so their connections are closed only if they
+// are not refreshed during the grace period after the disconnect.
 package peertracker
 
 import (
 	"context"
-	"net/url"
+	"net"
+	"sync"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
-	"github.com/networkservicemesh/api/pkg/api/networkservice"
-	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/stats"
 
-	"github.com/edwarnicke/serialize"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
 
-	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/nsmgr"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+	"github.com/networkservicemesh/sdk/pkg/tools/scheduler"
 )
 
-const (
-	unixScheme = "unix"
-)
+const defaultGracePeriod = time.Minute
+
+type peerKey struct{}
+
+// peerInfo identifies the gRPC client connection. Unix socket clients usually have no address, so the pointer itself
+// is used as a key.
+type peerInfo struct {
+	remoteAddr net.Addr
+}
+
+// isLocal returns true for the unix socket peers, they can't reconnect if their process has died
+func (p *peerInfo) isLocal() bool {
+	_, ok := p.remoteAddr.(*net.UnixAddr)
+	return ok || p.remoteAddr == nil
+}
+
+type trackedConn struct {
+	peer   *peerInfo
+	conn   *networkservice.Connection
+	server networkservice.NetworkServiceServer
+	task   *scheduler.Task
+	// closeTask is set for the disconnected remote peer connections, it closes the connection after the grace period
+	closeTask *scheduler.Task
+}
 
 type peerTrackerServer struct {
-	nsmgr.Nsmgr
-	executor serialize.Executor
-	// Outer map is peer url.URL.String(), inner map key is Connection.Id
-	connections map[string]map[string]*networkservice.Connection
+	ctx         context.Context
+	clock       clock.Clock
+	scheduler   *scheduler.Scheduler
+	gracePeriod time.Duration
+	conns       map[string]*trackedConn
+	mu          sync.Mutex
+}
+
+// Option is an option pattern for NewServer
+type Option func(s *peerTrackerServer)
+
+// WithGracePeriod sets the time for the remote (non unix socket) peer to reconnect and to refresh its connections
+// before they are closed. Default is 1m.
+func WithGracePeriod(gracePeriod time.Duration) Option {
+	return func(s *peerTrackerServer) {
+		if gracePeriod > 0 {
+			s.gracePeriod = gracePeriod
+		}
+	}
 }
 
-// NewServer - Creates a new peer tracker Server
-//           inner - Nsmgr being wrapped
-//           closeAll - pointer to memory location to which you should write a pointer to the function to be called
-//                      to close all connections for the provided url (presuming a unix URL)
-func NewServer(inner nsmgr.Nsmgr, closeAll *func(ctx context.Context, u *url.URL)) nsmgr.Nsmgr {
+// NewServer - creates a new NetworkServiceServer chain element tracking the connections per gRPC client connection
+//             (peer). When the unix socket peer disconnects, all its connections are closed with the subsequent
+//             chain, same as if the peer has closed them. Connections of the other peers are closed the same way
+//             only if they are not requested again during the grace period after the disconnect.
+//             - ctx - context for the Close of the disconnected peer connections, clock.Clock and
+//                     scheduler.Scheduler from ctx are used for the grace period and to forget the expired
+//                     connections
+//             - statsHandlerPtr - *stats.Handler. Same as the monitor.NewServer, it is a double pointer to get back
+//                     the gRPC stats handler detecting the peer disconnects. gRPC server should be created with
+//                     grpc.StatsHandler(*statsHandlerPtr), connections requested through the other gRPC servers are
+//                     not tracked.
+//             - options - WithGracePeriod
+func NewServer(ctx context.Context, statsHandlerPtr *stats.Handler, options ...Option) networkservice.NetworkServiceServer {
 	rv := &peerTrackerServer{
-		connections: make(map[string]map[string]*networkservice.Connection),
-		Nsmgr:       inner,
+		ctx:         ctx,
+		clock:       clock.FromContext(ctx),
+		scheduler:   scheduler.FromContext(ctx),
+		gracePeriod: defaultGracePeriod,
+		conns:       make(map[string]*trackedConn),
+	}
+	for _, opt := range options {
+		opt(rv)
 	}
-	*closeAll = rv.closeAllConnectionsForPeer
+	*statsHandlerPtr = rv
 	return rv
 }
 
-func (p *peerTrackerServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
-	conn, err := p.Nsmgr.Request(ctx, request)
+func (s *peerTrackerServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	conn, err := next.Server(ctx).Request(ctx, request)
 	if err != nil {
 		return nil, err
 	}
-	mypeer, ok := peer.FromContext(ctx)
-	if ok {
-		if mypeer.Addr.Network() == unixScheme {
-			u := &url.URL{
-				Scheme: mypeer.Addr.Network(),
-				Path:   mypeer.Addr.String(),
-			}
-			p.executor.AsyncExec(func() {
-				_, ok := p.connections[u.String()]
-				if !ok {
-					p.connections[u.String()] = make(map[string]*networkservice.Connection)
-				}
-				p.connections[u.String()][conn.GetId()] = conn
-			})
+
+	if p, ok := ctx.Value(peerKey{}).(*peerInfo); ok {
+		s.track(ctx, p, conn)
+	} else {
+		// Connection is requested through the untracked gRPC server, so it doesn't belong to the tracked peer anymore
+		s.mu.Lock()
+		if t, ok := s.conns[conn.GetId()]; ok {
+			s.stop(t)
 		}
+		s.mu.Unlock()
 	}
+
 	return conn, nil
 }
 
-func (p *peerTrackerServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
-	_, err := p.Nsmgr.Close(ctx, conn)
-	if err != nil {
-		return nil, err
+func (s *peerTrackerServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.mu.Lock()
+	if t, ok := s.conns[conn.GetId()]; ok {
+		s.stop(t)
 	}
-	mypeer, ok := peer.FromContext(ctx)
-	if ok {
-		if mypeer.Addr.Network() == unixScheme {
-			u := &url.URL{
-				Scheme: mypeer.Addr.Network(),
-				Path:   mypeer.Addr.String(),
+	s.mu.Unlock()
+
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func (s *peerTrackerServer) track(ctx context.Context, p *peerInfo, conn *networkservice.Connection) {
+	t := &trackedConn{
+		peer:   p,
+		conn:   conn.Clone(),
+		server: next.Server(ctx),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.conns[conn.GetId()]; ok {
+		s.stop(current)
+	}
+
+	// Connection is closed by the timeout after the expiration, so there is no need to track it anymore
+	if expireTime, err := ptypes.Timestamp(conn.GetCurrentPathSegment().GetExpires()); err == nil {
+		t.task = s.scheduler.AfterFunc(s.clock.Until(expireTime), func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			if s.conns[t.conn.GetId()] == t {
+				delete(s.conns, t.conn.GetId())
 			}
-			p.executor.AsyncExec(func() {
-				delete(p.connections[u.String()], conn.GetId())
-			})
-		}
+		})
 	}
-	return &empty.Empty{}, nil
+
+	s.conns[conn.GetId()] = t
 }
 
-func (p *peerTrackerServer) closeAllConnectionsForPeer(ctx context.Context, u *url.URL) {
-	finishedChan := make(chan struct{})
-	<-p.executor.AsyncExec(func() {
-		if connMap, ok := p.connections[u.String()]; ok {
-			for _, conn := range connMap {
-				_, _ = p.Close(ctx, conn)
-			}
+// stop should be called under the s.mu lock
+func (s *peerTrackerServer) stop(t *trackedConn) {
+	if t.task != nil {
+		t.task.Stop()
+	}
+	if t.closeTask != nil {
+		t.closeTask.Stop()
+	}
+	delete(s.conns, t.conn.GetId())
+}
+
+func (s *peerTrackerServer) closeAllConnectionsForPeer(p *peerInfo) {
+	logEntry := logger.Log(s.ctx).WithField("peerTrackerServer", "closeAllConnectionsForPeer")
+
+	// All the peers disconnect when the server is stopping, but their connections are still alive
+	if s.ctx.Err() != nil {
+		return
+	}
+
+	var conns []*trackedConn
+
+	s.mu.Lock()
+	for _, t := range s.conns {
+		if t.peer != p {
+			continue
 		}
-		close(finishedChan)
+		if p.isLocal() {
+			s.stop(t)
+			conns = append(conns, t)
+			continue
+		}
+		logEntry.Infof("peer %v has disconnected, closing the connection after %v: %v", p.remoteAddr, s.gracePeriod, t.conn.GetId())
+		t := t
+		t.closeTask = s.scheduler.AfterFunc(s.gracePeriod, func() {
+			s.mu.Lock()
+			if s.conns[t.conn.GetId()] != t {
+				s.mu.Unlock()
+				return
+			}
+			s.stop(t)
+			s.mu.Unlock()
+
+			logEntry.Infof("peer %v has not reconnected, closing the connection: %v", p.remoteAddr, t.conn.GetId())
+			s.close(t)
+		})
+	}
+	s.mu.Unlock()
+
+	for _, t := range conns {
+		logEntry.Infof("peer %v has disconnected, closing the connection: %v", p.remoteAddr, t.conn.GetId())
+		s.close(t)
+	}
+}
+
+func (s *peerTrackerServer) close(t *trackedConn) {
+	if _, err := t.server.Close(s.ctx, t.conn.Clone()); err != nil {
+		logger.Log(s.ctx).WithField("peerTrackerServer", "close").
+			Errorf("failed to close the connection: %v %+v", t.conn.GetId(), err)
+	}
+}
+
+func (s *peerTrackerServer) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (s *peerTrackerServer) HandleRPC(_ context.Context, _ stats.RPCStats) {}
+
+func (s *peerTrackerServer) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return context.WithValue(ctx, peerKey{}, &peerInfo{
+		remoteAddr: info.RemoteAddr,
 	})
 }
+
+func (s *peerTrackerServer) HandleConn(ctx context.Context, connStats stats.ConnStats) {
+	if _, ok := connStats.(*stats.ConnEnd); !ok {
+		return
+	}
+	if p, ok := ctx.Value(peerKey{}).(*peerInfo); ok {
+		// gRPC calls HandleConn on the transport closing, the connections Close shouldn't block it
+		go s.closeAllConnectionsForPeer(p)
+	}
+}
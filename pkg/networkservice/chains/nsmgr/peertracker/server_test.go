@@ -0,0 +1,234 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package peertracker_test
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/stats"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/nsmgr/peertracker"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+)
+
+const (
+	tokenTimeout = time.Hour
+	gracePeriod  = time.Minute
+	waitFor      = time.Second
+	tick         = 10 * time.Millisecond
+)
+
+type closeCounterServer struct {
+	closes int32
+}
+
+func (s *closeCounterServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *closeCounterServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	atomic.AddInt32(&s.closes, 1)
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func newServer(ctx context.Context, counter *closeCounterServer) *grpc.Server {
+	var statsHandler stats.Handler
+	server := chain.NewNetworkServiceServer(
+		peertracker.NewServer(ctx, &statsHandler, peertracker.WithGracePeriod(gracePeriod)),
+		counter,
+	)
+
+	grpcServer := grpc.NewServer(grpc.StatsHandler(statsHandler))
+	networkservice.RegisterNetworkServiceServer(grpcServer, server)
+
+	return grpcServer
+}
+
+func startServer(ctx context.Context, t *testing.T, counter *closeCounterServer) *url.URL {
+	grpcServer := newServer(ctx, counter)
+
+	u := &url.URL{Scheme: "unix", Path: filepath.Join(t.TempDir(), "nsm.io.sock")}
+	select {
+	case err := <-grpcutils.ListenAndServe(ctx, u, grpcServer):
+		require.NoError(t, err)
+	default:
+	}
+	return u
+}
+
+func startTCPServer(ctx context.Context, t *testing.T, counter *closeCounterServer) *url.URL {
+	grpcServer := newServer(ctx, counter)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		_ = grpcServer.Serve(ln)
+	}()
+	go func() {
+		<-ctx.Done()
+		grpcServer.Stop()
+	}()
+
+	return &url.URL{Scheme: "tcp", Host: ln.Addr().String()}
+}
+
+func dial(ctx context.Context, t *testing.T, u *url.URL) *grpc.ClientConn {
+	cc, err := grpc.DialContext(ctx, grpcutils.URLToTarget(u), grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	return cc
+}
+
+func request(ctx context.Context, t *testing.T, cc *grpc.ClientConn, connID string) {
+	expires, err := ptypes.TimestampProto(clock.FromContext(ctx).Now().Add(tokenTimeout))
+	require.NoError(t, err)
+
+	_, err = networkservice.NewNetworkServiceClient(cc).Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: connID,
+			Path: &networkservice.Path{
+				PathSegments: []*networkservice.PathSegment{{
+					Id:      connID,
+					Expires: expires,
+				}},
+			},
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestPeerTracker_Disconnect(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(logger.WithLog(context.Background()))
+	defer cancel()
+
+	counter := new(closeCounterServer)
+	u := startServer(ctx, t, counter)
+
+	ccA := dial(ctx, t, u)
+	defer func() { _ = ccA.Close() }()
+	ccB := dial(ctx, t, u)
+	defer func() { _ = ccB.Close() }()
+
+	request(ctx, t, ccA, "conn-a-1")
+	request(ctx, t, ccA, "conn-a-2")
+	request(ctx, t, ccB, "conn-b")
+
+	// Closed connection is not closed on the disconnect
+	_, err := networkservice.NewNetworkServiceClient(ccA).Close(ctx, &networkservice.Connection{Id: "conn-a-2"})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&counter.closes))
+
+	// Only the disconnected peer connections are closed
+	require.NoError(t, ccA.Close())
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&counter.closes) == 2
+	}, waitFor, tick)
+	require.Never(t, func() bool {
+		return atomic.LoadInt32(&counter.closes) > 2
+	}, 10*tick, tick)
+
+	require.NoError(t, ccB.Close())
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&counter.closes) == 3
+	}, waitFor, tick)
+}
+
+func TestPeerTracker_Expire(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(logger.WithLog(context.Background()))
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	counter := new(closeCounterServer)
+	u := startServer(ctx, t, counter)
+
+	cc := dial(ctx, t, u)
+	defer func() { _ = cc.Close() }()
+
+	request(ctx, t, cc, "conn-1")
+
+	// Expired connection is closed by the timeout, so it is not closed on the disconnect
+	clockMock.Add(tokenTimeout)
+
+	require.NoError(t, cc.Close())
+	require.Never(t, func() bool {
+		return atomic.LoadInt32(&counter.closes) > 0
+	}, 10*tick, tick)
+}
+
+func TestPeerTracker_RemoteDisconnect(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(logger.WithLog(context.Background()))
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	counter := new(closeCounterServer)
+	u := startTCPServer(ctx, t, counter)
+
+	ccA := dial(ctx, t, u)
+	defer func() { _ = ccA.Close() }()
+
+	request(ctx, t, ccA, "conn-1")
+	request(ctx, t, ccA, "conn-2")
+
+	// Remote peer connections are not closed right after the disconnect
+	require.NoError(t, ccA.Close())
+	require.Never(t, func() bool {
+		return atomic.LoadInt32(&counter.closes) > 0
+	}, 10*tick, tick)
+
+	// Peer reconnects and refreshes only one of the connections
+	ccB := dial(ctx, t, u)
+	defer func() { _ = ccB.Close() }()
+
+	request(ctx, t, ccB, "conn-1")
+
+	// Not refreshed connection is closed after the grace period
+	require.Eventually(t, func() bool {
+		clockMock.Add(gracePeriod)
+		return atomic.LoadInt32(&counter.closes) == 1
+	}, waitFor, tick)
+	require.Never(t, func() bool {
+		return atomic.LoadInt32(&counter.closes) > 1
+	}, 10*tick, tick)
+}
@@ -1,6 +1,6 @@
 // Copyright (c) 2020 Cisco and/or its affiliates.
 //
-// Copyright (c) 2020 Doc.ai and/or its affiliates.
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -21,8 +21,12 @@ package nsmgr
 
 import (
 	"context"
+	"net/url"
+	"sync"
 	"time"
 
+	"github.com/golang/protobuf/ptypes/empty"
+
 	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/client"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/expire"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/querycache"
@@ -34,7 +38,10 @@ import (
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	registryapi "github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/stats"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/excludedprefixes"
 
@@ -47,39 +54,87 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/registry/core/nextwrap"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/endpoint"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/nsmgr/peertracker"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/connect"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/drain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/explain"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/localbypass"
-	"github.com/networkservicemesh/sdk/pkg/networkservice/common/roundrobin"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/netnsgc"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/null"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/persist"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/reaper"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/selectendpoint"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
 	adapter_registry "github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
 	"github.com/networkservicemesh/sdk/pkg/tools/addressof"
+	"github.com/networkservicemesh/sdk/pkg/tools/circuitbreaker"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+	"github.com/networkservicemesh/sdk/pkg/tools/scheduler"
 	"github.com/networkservicemesh/sdk/pkg/tools/token"
 )
 
 // Nsmgr - A simple combintation of the Endpoint, registry.NetworkServiceRegistryServer, and registry.NetworkServiceDiscoveryServer interfaces
+//         with the explain.ExplainServiceServer and reaper.ReaperServiceServer debug services.
+//         Nsmgr is also a gRPC stats.Handler, it should be served with grpc.StatsHandler(nsmgr) to close the
+//         connections of the disconnected clients.
 type Nsmgr interface {
 	networkservice.NetworkServiceServer
 	networkservice.MonitorConnectionServer
+	drain.Drainer
 	registry.Registry
+	explain.ExplainServiceServer
+	reaper.ReaperServiceServer
+	stats.Handler
 }
 
 type nsmgrServer struct {
 	endpoint.Endpoint
 	registry.Registry
+	explain.ExplainServiceServer
+	reaper.ReaperServiceServer
+	stats.Handler
+	server      networkservice.NetworkServiceServer
+	explain     bool
+	reaper      bool
+	restore     func()
+	restoreOnce sync.Once
 }
 
 var _ Nsmgr = (*nsmgrServer)(nil)
 
+const restoreRequestTimeout = 15 * time.Second
+
 // NewServer - Creates a new Nsmgr
 //           nsmRegistration - Nsmgr registration
 //           authzServer - authorization server chain element
 //           tokenGenerator - authorization token generator
 //           registryCC - client connection to reach the upstream registry, could be nil, in this case only in memory storage will be used.
-// 			 clientDialOptions -  a grpc.DialOption's to be passed to GRPC connections.
+//           clientDialOptions - dial options for the connections to the forwarders, endpoints and remote Nsmgrs
 func NewServer(ctx context.Context, nsmRegistration *registryapi.NetworkServiceEndpoint, authzServer networkservice.NetworkServiceServer, tokenGenerator token.GeneratorFunc, registryCC grpc.ClientConnInterface, clientDialOptions ...grpc.DialOption) Nsmgr {
+	return NewServerWithOptions(ctx, nsmRegistration, authzServer, tokenGenerator, registryCC, WithDialOptions(clientDialOptions...))
+}
+
+// NewServerWithOptions - same as NewServer, but configured with options
+//           options - Nsmgr options: dial options, state store, netns GC, explain and reaper services.
+func NewServerWithOptions(ctx context.Context, nsmRegistration *registryapi.NetworkServiceEndpoint, authzServer networkservice.NetworkServiceServer, tokenGenerator token.GeneratorFunc, registryCC grpc.ClientConnInterface, options ...Option) Nsmgr {
+	opts := new(serverOptions)
+	for _, opt := range options {
+		opt(opts)
+	}
+
 	rv := &nsmgrServer{}
 
+	// All the nsmgr connection and registration timers share the same scheduler
+	ctx = scheduler.WithScheduler(ctx, scheduler.FromContext(ctx))
+
+	var reaperOptions []reaper.Option
+	if opts.reaperService {
+		reaperOptions = append(reaperOptions, reaper.WithAuthorizeServer(authzServer))
+	}
+
 	var urlsRegistryServer registryapi.NetworkServiceEndpointRegistryServer
 	var localbypassRegistryServer registryapi.NetworkServiceEndpointRegistryServer
 
@@ -104,33 +159,57 @@ func NewServer(ctx context.Context, nsmRegistration *registryapi.NetworkServiceE
 	nsClient := adapter_registry.NetworkServiceServerToClient(nsRegistry)
 	var interposeRegistry registryapi.NetworkServiceEndpointRegistryServer
 
+	// Circuit breakers are keyed by the endpoint name and shared with the endpoint selection to skip the endpoints with
+	// the open circuits, even if they are connected through the local bypass. Calls to the forwarders are keyed by the
+	// forwarder URLs, so a broken forwarder doesn't open the endpoint circuits.
+	breakers := circuitbreaker.NewBreakers(circuitbreaker.WithClock(clock.FromContext(ctx)))
+
+	// Endpoint selection is shared with the explain server to explain the selection in the current selector state
+	selectServer := selectendpoint.NewServer(
+		selectendpoint.WithSelector(selectendpoint.NewRoundRobinSelector()),
+		selectendpoint.WithCircuitBreaker(breakers),
+	)
+
 	// Construct Endpoint
 	rv.Endpoint = endpoint.NewServer(ctx,
 		nsmRegistration.Name,
 		authzServer,
 		tokenGenerator,
+		newPersistServer(opts.stateStore),
 		discover.NewServer(nsClient, nseClient),
-		roundrobin.NewServer(),
+		selectServer,
 		localbypass.NewServer(&localbypassRegistryServer),
 		excludedprefixes.NewServer(ctx),
 		newRecvFD(), // Receive any files passed
 		interpose.NewServer(&interposeRegistry),
 		filtermechanisms.NewServer(&urlsRegistryServer),
-		connect.NewServer(ctx,
+		reaper.NewServer(ctx, &rv.ReaperServiceServer, reaperOptions...), // Retry the failed Close of the client connections
+		connect.NewServerWithOptions(ctx,
 			client.NewClientFactory(
 				nsmRegistration.Name,
 				addressof.NetworkServiceClient(adapters.NewServerToClient(rv)),
 				tokenGenerator,
 				newSendFDClient(), // Send passed files.
 			),
-			clientDialOptions...),
+			connect.WithDialOptions(opts.dialOptions...),
+			connect.WithCircuitBreaker(breakers),
+			connect.WithCloseRetry(),
+		),
+	)
+
+	// Peer tracker and netns GC go before the whole Endpoint chain to close the connections the same way as the
+	// clients do
+	rv.server = chain.NewNetworkServiceServer(
+		peertracker.NewServer(ctx, &rv.Handler, opts.peerOptions...),
+		newNetNSGCServer(ctx, opts),
+		rv.Endpoint,
 	)
 
 	nsChain := chain_registry.NewNamedNetworkServiceRegistryServer(nsmRegistration.Name+".NetworkServiceRegistry", nsRegistry)
 
 	nseChain := chain_registry.NewNamedNetworkServiceEndpointRegistryServer(
 		nsmRegistration.Name+".NetworkServiceEndpointRegistry",
-		expire.NewNetworkServiceEndpointRegistryServer(time.Minute),
+		expire.NewNetworkServiceEndpointRegistryServer(ctx, time.Minute),
 		newRecvFDEndpointRegistry(), // Allow to receive a passed files
 		urlsRegistryServer,
 		interposeRegistry,                                                   // Store cross connect NSEs
@@ -140,9 +219,56 @@ func NewServer(ctx context.Context, nsmRegistration *registryapi.NetworkServiceE
 	)
 	rv.Registry = registry.NewServer(nsChain, nseChain)
 
+	rv.explain = opts.explain
+	rv.ExplainServiceServer = new(explain.UnimplementedExplainServiceServer)
+	if opts.explain {
+		rv.ExplainServiceServer = explain.NewServer(nsClient, nseClient,
+			explain.WithPreviewer(selectServer),
+			explain.WithAuthorizeServer(authzServer),
+		)
+	}
+
+	rv.reaper = opts.reaperService
+	if !opts.reaperService {
+		rv.ReaperServiceServer = new(reaper.UnimplementedReaperServiceServer)
+	}
+
+	if opts.stateStore != nil {
+		// Connections can pass the Nsmgr more than once (client -> Nsmgr -> forwarder -> Nsmgr -> endpoint), so the
+		// restore is started on Register and waits for the Nsmgr to be served
+		rv.restore = func() {
+			if err := waitServing(ctx, nsmRegistration.GetUrl(), opts.dialOptions...); err != nil {
+				logger.Log(ctx).WithField("nsmgr", "restore").Warnf("Nsmgr is not served, restoring anyway: %+v", err)
+			}
+			persist.Restore(ctx, opts.stateStore, rv, restoreRequestTimeout)
+		}
+	}
+
 	return rv
 }
 
+func (n *nsmgrServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return n.server.Request(ctx, request)
+}
+
+func (n *nsmgrServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return n.server.Close(ctx, conn)
+}
+
+func newPersistServer(stateStore persist.Store) networkservice.NetworkServiceServer {
+	if stateStore == nil {
+		return null.NewServer()
+	}
+	return persist.NewServer(stateStore)
+}
+
+func newNetNSGCServer(ctx context.Context, opts *serverOptions) networkservice.NetworkServiceServer {
+	if !opts.netnsGC {
+		return null.NewServer()
+	}
+	return netnsgc.NewServer(ctx, opts.netnsGCOptions...)
+}
+
 func newRemoteNSServer(cc grpc.ClientConnInterface) registryapi.NetworkServiceRegistryServer {
 	if cc != nil {
 		return adapter_registry.NetworkServiceClientToServer(
@@ -166,6 +292,37 @@ func (n *nsmgrServer) Register(s *grpc.Server) {
 	networkservice.RegisterMonitorConnectionServer(s, n)
 	registryapi.RegisterNetworkServiceRegistryServer(s, n.Registry.NetworkServiceRegistryServer())
 	registryapi.RegisterNetworkServiceEndpointRegistryServer(s, n.Registry.NetworkServiceEndpointRegistryServer())
+	if n.explain {
+		explain.RegisterExplainServiceServer(s, n)
+	}
+	if n.reaper {
+		reaper.RegisterReaperServiceServer(s, n)
+	}
+	if n.restore != nil {
+		n.restoreOnce.Do(func() {
+			go n.restore()
+		})
+	}
+}
+
+// waitServing waits until the Nsmgr health service responds on the Nsmgr URL
+func waitServing(ctx context.Context, nsmURL string, dialOptions ...grpc.DialOption) error {
+	u, err := url.Parse(nsmURL)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse Nsmgr URL: %s", nsmURL)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, restoreRequestTimeout)
+	defer cancel()
+
+	cc, err := grpc.DialContext(ctx, grpcutils.URLToTarget(u), dialOptions...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to dial Nsmgr: %s", nsmURL)
+	}
+	defer func() { _ = cc.Close() }()
+
+	_, err = grpc_health_v1.NewHealthClient(cc).Check(ctx, new(grpc_health_v1.HealthCheckRequest), grpc.WaitForReady(true))
+	return errors.Wrapf(err, "failed to check Nsmgr health: %s", nsmURL)
 }
 
 var _ Nsmgr = &nsmgrServer{}
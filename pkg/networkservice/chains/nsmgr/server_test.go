@@ -20,30 +20,43 @@ package nsmgr_test
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"path/filepath"
 	"strconv"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/cls"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/common"
 	kernelmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
 	"github.com/networkservicemesh/api/pkg/api/registry"
 
+	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/nsmgr"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/clienturl"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/connect"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/drain"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/netnsgc"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/persist"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
-	"github.com/networkservicemesh/sdk/pkg/tools/opentracing"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
 	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+	"github.com/networkservicemesh/sdk/pkg/tools/token"
+	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
 )
 
 func TestNSMGR_RemoteUsecase_Parallel(t *testing.T) {
@@ -146,6 +159,154 @@ func TestNSMGR_SelectsRestartingEndpoint(t *testing.T) {
 	require.Equal(t, 5, len(conn.Path.PathSegments))
 }
 
+func TestNSMGR_CircuitBreaker(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	domain := sandbox.NewBuilder(t).
+		SetNodesCount(1).
+		SetRegistryProxySupplier(nil).
+		SetContext(ctx).
+		Build()
+	defer domain.Cleanup()
+
+	counter := new(counterServer)
+	_, err := sandbox.NewEndpoint(ctx, &registry.NetworkServiceEndpoint{
+		Name:                "nse-1",
+		NetworkServiceNames: []string{"ns-1"},
+	}, sandbox.GenerateTestToken, domain.Nodes[0].NSMgr, counter)
+	require.NoError(t, err)
+
+	// The unavailable endpoint is served without the endpoint chain, so its Unavailable status is not wrapped like it
+	// happens for the connection failures
+	unavailable := new(unavailableEndpoint)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	networkservice.RegisterNetworkServiceServer(server, unavailable)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	expirationTime, err := ptypes.TimestampProto(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	_, err = domain.Nodes[0].NSMgr.NetworkServiceEndpointRegistryServer().Register(ctx, &registry.NetworkServiceEndpoint{
+		Name:                "nse-unavailable",
+		NetworkServiceNames: []string{"ns-1"},
+		Url:                 grpcutils.AddressToURL(listener.Addr()).String(),
+		ExpirationTime:      expirationTime,
+	})
+	require.NoError(t, err)
+
+	nsc := sandbox.NewClient(ctx, sandbox.GenerateTestToken, domain.Nodes[0].NSMgr.URL)
+
+	request := func(id int) {
+		conn, requestErr := nsc.Request(ctx, &networkservice.NetworkServiceRequest{
+			MechanismPreferences: []*networkservice.Mechanism{
+				{Cls: cls.LOCAL, Type: kernelmech.MECHANISM},
+			},
+			Connection: &networkservice.Connection{
+				Id:             strconv.Itoa(id),
+				NetworkService: "ns-1",
+				Context:        &networkservice.ConnectionContext{},
+			},
+		})
+		require.NoError(t, requestErr)
+		require.Equal(t, "nse-1", conn.GetNetworkServiceEndpointName())
+	}
+
+	for i := 0; i < 10; i++ {
+		request(i)
+	}
+	requests := atomic.LoadInt32(&unavailable.Requests)
+	require.Greater(t, requests, int32(0))
+
+	// The circuit for the unavailable endpoint is open, so it is skipped by the endpoint selection
+	for i := 10; i < 20; i++ {
+		request(i)
+	}
+	require.Equal(t, requests, atomic.LoadInt32(&unavailable.Requests))
+	require.Equal(t, int32(20), atomic.LoadInt32(&counter.Requests))
+}
+
+func TestNSMGR_Restart(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	stateStore, err := persist.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	domain := sandbox.NewBuilder(t).
+		SetNodesCount(1).
+		SetRegistryProxySupplier(nil).
+		SetContext(ctx).
+		SetNSMgrSupplier(func(ctx context.Context, nsmRegistration *registry.NetworkServiceEndpoint, authzServer networkservice.NetworkServiceServer, tokenGenerator token.GeneratorFunc, registryCC grpc.ClientConnInterface, dialOptions ...grpc.DialOption) nsmgr.Nsmgr {
+			return nsmgr.NewServerWithOptions(ctx, nsmRegistration, authzServer, tokenGenerator, registryCC,
+				nsmgr.WithDialOptions(dialOptions...),
+				nsmgr.WithStateStore(stateStore),
+			)
+		}).
+		Build()
+	defer domain.Cleanup()
+
+	counter := new(counterServer)
+	nse, err := sandbox.NewEndpoint(ctx, &registry.NetworkServiceEndpoint{
+		Name:                "final-endpoint",
+		NetworkServiceNames: []string{"my-service"},
+	}, sandbox.GenerateTestToken, domain.Nodes[0].NSMgr, counter)
+	require.NoError(t, err)
+
+	nsc := sandbox.NewClient(ctx, sandbox.GenerateTestToken, domain.Nodes[0].NSMgr.URL)
+
+	request := &networkservice.NetworkServiceRequest{
+		MechanismPreferences: []*networkservice.Mechanism{
+			{Cls: cls.LOCAL, Type: kernelmech.MECHANISM},
+		},
+		Connection: &networkservice.Connection{
+			Id:             "1",
+			NetworkService: "my-service",
+			Context:        &networkservice.ConnectionContext{},
+		},
+	}
+
+	conn, err := nsc.Request(ctx, request.Clone())
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&counter.Requests))
+
+	domain.Nodes[0].RestartNSMgr()
+
+	// The endpoint registers again with the new Nsmgr, e.g. on the registration refresh
+	expirationTime, err := ptypes.TimestampProto(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	_, err = domain.Nodes[0].NSMgr.NetworkServiceEndpointRegistryServer().Register(ctx, &registry.NetworkServiceEndpoint{
+		Name:                "final-endpoint",
+		NetworkServiceNames: []string{"my-service"},
+		Url:                 nse.URL.String(),
+		ExpirationTime:      expirationTime,
+	})
+	require.NoError(t, err)
+
+	// The connection is restored by the new Nsmgr up to the endpoint
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&counter.Requests) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The client keeps using the same connection with the new Nsmgr
+	request.Connection = conn.Clone()
+	conn, err = nsc.Request(ctx, request.Clone())
+	require.NoError(t, err)
+	require.Equal(t, int32(3), atomic.LoadInt32(&counter.Requests))
+
+	_, err = nsc.Close(ctx, conn)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&counter.Closes))
+}
+
 func TestNSMGR_RemoteUsecase_BusyEndpoints(t *testing.T) {
 	t.Skip("https://github.com/networkservicemesh/sdk/issues/619")
 
@@ -359,7 +520,7 @@ func TestNSMGR_PassThroughRemote(t *testing.T) {
 								fmt.Sprintf("my-service-remote-%v", k-1),
 								fmt.Sprintf("endpoint-%v", k-1)),
 							kernel.NewClient()),
-						append(opentracing.WithTracingDial(), grpc.WithBlock(), grpc.WithInsecure())...,
+						append(tracing.WithTracingDial(), grpc.WithBlock(), grpc.WithInsecure())...,
 					),
 				),
 			}
@@ -420,7 +581,7 @@ func TestNSMGR_PassThroughLocal(t *testing.T) {
 								fmt.Sprintf("my-service-remote-%v", k-1),
 								fmt.Sprintf("endpoint-%v", k-1)),
 							kernel.NewClient()),
-						append(opentracing.WithTracingDial(), grpc.WithBlock(), grpc.WithInsecure())...,
+						append(tracing.WithTracingDial(), grpc.WithBlock(), grpc.WithInsecure())...,
 					),
 				),
 			}
@@ -455,6 +616,201 @@ func TestNSMGR_PassThroughLocal(t *testing.T) {
 	require.Equal(t, 5*(nsesCount-1)+5, len(conn.Path.PathSegments))
 }
 
+func TestNSMGR_Drain(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	domain := sandbox.NewBuilder(t).
+		SetNodesCount(1).
+		SetContext(ctx).
+		SetRegistryProxySupplier(nil).
+		Build()
+	defer domain.Cleanup()
+
+	nseReg := &registry.NetworkServiceEndpoint{
+		Name:                "final-endpoint",
+		NetworkServiceNames: []string{"my-service"},
+	}
+
+	counter := &counterServer{}
+	nse, err := sandbox.NewEndpoint(ctx, nseReg, sandbox.GenerateTestToken, domain.Nodes[0].NSMgr, counter)
+	require.NoError(t, err)
+
+	nsc := sandbox.NewClient(ctx, sandbox.GenerateTestToken, domain.Nodes[0].NSMgr.URL)
+
+	newRequest := func(id string) *networkservice.NetworkServiceRequest {
+		return &networkservice.NetworkServiceRequest{
+			MechanismPreferences: []*networkservice.Mechanism{
+				{Cls: cls.LOCAL, Type: kernelmech.MECHANISM},
+			},
+			Connection: &networkservice.Connection{
+				Id:             id,
+				NetworkService: "my-service",
+				Context:        &networkservice.ConnectionContext{},
+			},
+		}
+	}
+
+	conn, err := nsc.Request(ctx, newRequest("1"))
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&counter.Requests))
+
+	// Draining endpoint rejects new connections and refreshes existing ones
+
+	require.NoError(t, nse.Drain(ctx))
+
+	// NSMgr retries to select the endpoint until the request context is done
+	requestCtx, cancelRequest := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancelRequest()
+
+	_, err = nsc.Request(requestCtx, newRequest("2"))
+	require.Error(t, err)
+
+	refreshRequest := newRequest("1")
+	refreshRequest.Connection = conn.Clone()
+
+	conn, err = nsc.Request(ctx, refreshRequest)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&counter.Requests))
+
+	// Draining NSMgr with batches closes existing connections
+
+	require.NoError(t, domain.Nodes[0].NSMgr.Drain(ctx, drain.WithBatch(1, 10*time.Millisecond)))
+	require.Equal(t, int32(1), atomic.LoadInt32(&counter.Closes))
+
+	_, err = nsc.Request(ctx, newRequest("3"))
+	require.Error(t, err)
+	require.True(t, drain.IsDraining(err))
+
+	_, err = nsc.Close(ctx, conn)
+	require.NoError(t, err)
+}
+
+func TestNSMGR_ClientDisconnect(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	domain := sandbox.NewBuilder(t).
+		SetNodesCount(1).
+		SetContext(ctx).
+		SetRegistryProxySupplier(nil).
+		SetNSMgrSupplier(func(ctx context.Context, nsmRegistration *registry.NetworkServiceEndpoint, authzServer networkservice.NetworkServiceServer, tokenGenerator token.GeneratorFunc, registryCC grpc.ClientConnInterface, dialOptions ...grpc.DialOption) nsmgr.Nsmgr {
+			return nsmgr.NewServerWithOptions(ctx, nsmRegistration, authzServer, tokenGenerator, registryCC,
+				nsmgr.WithDialOptions(dialOptions...),
+				nsmgr.WithPeerGracePeriod(100*time.Millisecond),
+			)
+		}).
+		Build()
+	defer domain.Cleanup()
+
+	nseReg := &registry.NetworkServiceEndpoint{
+		Name:                "final-endpoint",
+		NetworkServiceNames: []string{"my-service-remote"},
+	}
+
+	counter := &counterServer{}
+	_, err := sandbox.NewEndpoint(ctx, nseReg, sandbox.GenerateTestToken, domain.Nodes[0].NSMgr, counter)
+	require.NoError(t, err)
+
+	clientCtx, cancelClient := context.WithCancel(ctx)
+	nsc := sandbox.NewClient(clientCtx, sandbox.GenerateTestToken, domain.Nodes[0].NSMgr.URL)
+
+	request := &networkservice.NetworkServiceRequest{
+		MechanismPreferences: []*networkservice.Mechanism{
+			{Cls: cls.LOCAL, Type: kernelmech.MECHANISM},
+		},
+		Connection: &networkservice.Connection{
+			Id:             "1",
+			NetworkService: "my-service-remote",
+			Context:        &networkservice.ConnectionContext{},
+		},
+	}
+
+	_, err = nsc.Request(ctx, request.Clone())
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&counter.Requests))
+
+	// Client disconnects without the Close, sandbox clients are connected over TCP, so the connection is closed after
+	// the grace period
+	cancelClient()
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&counter.Closes) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestNSMGR_NetNSGC(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	var netnsAlive int32 = 1
+	domain := sandbox.NewBuilder(t).
+		SetNodesCount(1).
+		SetContext(ctx).
+		SetRegistryProxySupplier(nil).
+		SetNSMgrSupplier(func(ctx context.Context, nsmRegistration *registry.NetworkServiceEndpoint, authzServer networkservice.NetworkServiceServer, tokenGenerator token.GeneratorFunc, registryCC grpc.ClientConnInterface, dialOptions ...grpc.DialOption) nsmgr.Nsmgr {
+			return nsmgr.NewServerWithOptions(ctx, nsmRegistration, authzServer, tokenGenerator, registryCC,
+				nsmgr.WithDialOptions(dialOptions...),
+				nsmgr.WithNetNSGC(
+					netnsgc.WithCheckInterval(10*time.Millisecond),
+					netnsgc.WithLivenessCheck(func(_ uintptr) bool {
+						return atomic.LoadInt32(&netnsAlive) == 1
+					}),
+				),
+			)
+		}).
+		Build()
+	defer domain.Cleanup()
+
+	nseReg := &registry.NetworkServiceEndpoint{
+		Name:                "final-endpoint",
+		NetworkServiceNames: []string{"my-service-remote"},
+	}
+
+	counter := &counterServer{}
+	_, err := sandbox.NewEndpoint(ctx, nseReg, sandbox.GenerateTestToken, domain.Nodes[0].NSMgr, counter)
+	require.NoError(t, err)
+
+	nsc := sandbox.NewClient(ctx, sandbox.GenerateTestToken, domain.Nodes[0].NSMgr.URL)
+
+	// Any file works as a network namespace with the custom liveness check
+	netnsFile := filepath.Join(t.TempDir(), "net")
+	require.NoError(t, ioutil.WriteFile(netnsFile, nil, 0600))
+
+	// Sandbox forwarder doesn't select the mechanism, so it is preselected by the client
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             "1",
+			NetworkService: "my-service-remote",
+			Context:        &networkservice.ConnectionContext{},
+			Mechanism: &networkservice.Mechanism{
+				Cls:  cls.LOCAL,
+				Type: kernelmech.MECHANISM,
+				Parameters: map[string]string{
+					common.InodeURL: "file://" + netnsFile,
+				},
+			},
+		},
+	}
+
+	_, err = nsc.Request(ctx, request.Clone())
+	require.NoError(t, err)
+
+	require.Never(t, func() bool {
+		return atomic.LoadInt32(&counter.Closes) > 0
+	}, 100*time.Millisecond, 10*time.Millisecond)
+
+	// Client network namespace disappears
+	atomic.StoreInt32(&netnsAlive, 0)
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&counter.Closes) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
 type passThroughClient struct {
 	networkService             string
 	networkServiceEndpointName string
@@ -511,6 +867,19 @@ func (c *restartingEndpoint) Close(ctx context.Context, connection *networkservi
 	return next.Client(ctx).Close(ctx, connection)
 }
 
+type unavailableEndpoint struct {
+	Requests int32
+}
+
+func (c *unavailableEndpoint) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	atomic.AddInt32(&c.Requests, 1)
+	return nil, status.Error(codes.Unavailable, "endpoint is unavailable")
+}
+
+func (c *unavailableEndpoint) Close(ctx context.Context, connection *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, connection)
+}
+
 type busyEndpoint struct{}
 
 func (c *busyEndpoint) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
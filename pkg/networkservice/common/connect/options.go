@@ -0,0 +1,55 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connect
+
+import (
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/circuitbreaker"
+)
+
+// Option is an option pattern for NewServer
+type Option func(s *connectServer)
+
+// WithDialOptions sets the grpc.DialOption's to be used to dial the clientURL
+func WithDialOptions(dialOptions ...grpc.DialOption) Option {
+	return func(s *connectServer) {
+		s.clientDialOptions = dialOptions
+	}
+}
+
+// WithCircuitBreaker sets the circuit breakers keyed by the requested endpoint name, or by the clientURL if the endpoint
+// name is not set. Calls to the peers other than the endpoint are keyed by circuitbreaker.Key(ctx) if it is set, e.g.
+// interpose keys the calls to the cross connect NSEs by their URLs. Requests with the open circuit fail fast without
+// dialing, only a single probe Request is allowed for the half-open circuit. The circuit is opened by the Unavailable
+// and DeadlineExceeded client Request failures. Circuits are deleted on the connection Close unless they are open. The
+// same breakers can be passed to the endpoint selection to skip the broken candidates. Default is no circuit breaker.
+func WithCircuitBreaker(breakers *circuitbreaker.Breakers) Option {
+	return func(s *connectServer) {
+		s.breakers = breakers
+	}
+}
+
+// WithCloseRetry keeps the clientURL of the connections failed to Close, so the Close can be retried by the previous
+// chain element (e.g. reaper) with a new client. The retrying element owns the failed connection lifetime: the
+// connection is forgotten on the successful Close, or on the Close with the reaper.WithGivenUp context meaning that the
+// retries have been given up. Default is to forget the connection on the failed Close.
+func WithCloseRetry() Option {
+	return func(s *connectServer) {
+		s.closeRetry = true
+	}
+}
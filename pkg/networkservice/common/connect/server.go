@@ -28,13 +28,17 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/clienturl"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/reaper"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/tools/circuitbreaker"
 	"github.com/networkservicemesh/sdk/pkg/tools/clientmap"
 	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
 )
@@ -43,13 +47,16 @@ type connectServer struct {
 	ctx               context.Context
 	clientFactory     func(ctx context.Context, cc grpc.ClientConnInterface) networkservice.NetworkServiceClient
 	clientDialOptions []grpc.DialOption
+	breakers          *circuitbreaker.Breakers
+	closeRetry        bool
 	connInfos         connectionInfoMap
 	clients           clientmap.RefcountMap
 }
 
 type connectionInfo struct {
 	clientURL *url.URL
-	client    networkservice.NetworkServiceClient
+	// client is nil for the connections failed to Close
+	client networkservice.NetworkServiceClient
 }
 
 // NewServer - chain element that
@@ -58,15 +65,38 @@ func NewServer(
 	clientFactory func(ctx context.Context, cc grpc.ClientConnInterface) networkservice.NetworkServiceClient,
 	clientDialOptions ...grpc.DialOption,
 ) networkservice.NetworkServiceServer {
-	return &connectServer{
-		ctx:               ctx,
-		clientFactory:     clientFactory,
-		clientDialOptions: clientDialOptions,
+	return NewServerWithOptions(ctx, clientFactory, WithDialOptions(clientDialOptions...))
+}
+
+// NewServerWithOptions - same as NewServer, but configured with options
+func NewServerWithOptions(
+	ctx context.Context,
+	clientFactory func(ctx context.Context, cc grpc.ClientConnInterface) networkservice.NetworkServiceClient,
+	options ...Option,
+) networkservice.NetworkServiceServer {
+	s := &connectServer{
+		ctx:           ctx,
+		clientFactory: clientFactory,
+	}
+	for _, opt := range options {
+		opt(s)
 	}
+	return s
 }
 
 func (s *connectServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	var key string
+	if clientURL := clienturlctx.ClientURL(ctx); s.breakers != nil && clientURL != nil {
+		key = breakerKey(ctx, request.GetConnection(), clientURL)
+		if err := s.breakers.Allow(key); err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+	}
+
 	conn, err := s.client(ctx, request.GetConnection()).Request(ctx, request.Clone())
+	if key != "" {
+		s.breakers.Done(ctx, key, err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -80,8 +110,32 @@ func (s *connectServer) Request(ctx context.Context, request *networkservice.Net
 
 func (s *connectServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
 	var clientErr error
-	if connInfo, ok := s.connInfos.LoadAndDelete(conn.GetId()); ok {
-		_, clientErr = connInfo.client.Close(ctx, conn)
+	if connInfo, ok := s.connInfos.Load(conn.GetId()); ok {
+		client := connInfo.client
+		if client == nil && reaper.IsGivenUp(ctx) {
+			// Close retries have been given up, so the connection should be forgotten
+			s.connInfos.Delete(conn.GetId())
+		} else {
+			if client == nil {
+				// Previous client Close has failed, so there is no client for the connection anymore
+				client = s.clientByURL(connInfo.clientURL)
+			}
+			_, clientErr = client.Close(ctx, conn)
+			switch {
+			case clientErr != nil && s.closeRetry:
+				// Keep the clientURL, so the Close can be retried
+				s.connInfos.Store(conn.GetId(), connectionInfo{
+					clientURL: connInfo.clientURL,
+				})
+			case clientErr != nil:
+				s.connInfos.Delete(conn.GetId())
+			default:
+				s.connInfos.Delete(conn.GetId())
+				if s.breakers != nil {
+					s.breakers.Delete(breakerKey(ctx, conn, connInfo.clientURL))
+				}
+			}
+		}
 	}
 
 	_, err := next.Server(ctx).Close(ctx, conn)
@@ -106,15 +160,29 @@ func (s *connectServer) client(ctx context.Context, conn *networkservice.Connect
 
 	// First check if we have already requested some clientURL with this conn.GetID().
 	if connInfo, ok := s.connInfos.Load(conn.GetId()); ok {
-		if *connInfo.clientURL == *clientURL {
-			return connInfo.client
-		}
-		// For some reason we have changed the clientURL, so we need to close the existing client.
-		if _, clientErr := connInfo.client.Close(ctx, conn); clientErr != nil {
-			logEntry.Warnf("failed to close client: %+v", clientErr)
+		// If the previous client Close has failed, the connection is requested again with a new client.
+		if connInfo.client != nil {
+			if *connInfo.clientURL == *clientURL {
+				return connInfo.client
+			}
+			// For some reason we have changed the clientURL, so we need to close the existing client.
+			if _, clientErr := connInfo.client.Close(ctx, conn); clientErr != nil {
+				logEntry.Warnf("failed to close client: %+v", clientErr)
+			}
 		}
 	}
 
+	client := s.clientByURL(clientURL)
+
+	s.connInfos.Store(conn.GetId(), connectionInfo{
+		clientURL: clientURL,
+		client:    client,
+	})
+
+	return client
+}
+
+func (s *connectServer) clientByURL(clientURL *url.URL) networkservice.NetworkServiceClient {
 	// Fast path if we already have client for the clientURL, use it.
 	client, loaded := s.clients.Load(clientURL.String())
 	if !loaded {
@@ -126,12 +194,6 @@ func (s *connectServer) client(ctx context.Context, conn *networkservice.Connect
 			cancel()
 		}
 	}
-
-	s.connInfos.Store(conn.GetId(), connectionInfo{
-		clientURL: clientURL,
-		client:    client,
-	})
-
 	return client
 }
 
@@ -152,3 +214,15 @@ func (s *connectServer) newClient(clientURL *url.URL) (networkservice.NetworkSer
 
 	return *clientPtr, cancel
 }
+
+// breakerKey returns the circuit breaker key for the connection: the key from the context if the clientURL is not the
+// endpoint one (e.g. a cross connect NSE), the endpoint name or the clientURL if there is no one
+func breakerKey(ctx context.Context, conn *networkservice.Connection, clientURL *url.URL) string {
+	if key := circuitbreaker.Key(ctx); key != "" {
+		return key
+	}
+	if name := conn.GetNetworkServiceEndpointName(); name != "" {
+		return name
+	}
+	return clientURL.String()
+}
@@ -23,14 +23,19 @@ import (
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/networkservicemesh/sdk/pkg/tools/logger"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/cls"
@@ -39,9 +44,12 @@ import (
 	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/vfio"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/connect"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/reaper"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/circuitbreaker"
 	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
 	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
 )
 
@@ -320,3 +328,340 @@ func (s *countServer) Close(ctx context.Context, conn *networkservice.Connection
 	atomic.AddInt32(&s.count, -1)
 	return next.Server(ctx).Close(ctx, conn)
 }
+
+type unavailableServer struct {
+	unavailable int32
+}
+
+func (s *unavailableServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if atomic.LoadInt32(&s.unavailable) == 1 {
+		return nil, status.Error(codes.Unavailable, "server is unavailable")
+	}
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *unavailableServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func TestConnectServer_CircuitBreaker(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 1. Create connectServer with circuit breakers
+
+	clk := clockmock.NewMock()
+	breakers := circuitbreaker.NewBreakers(
+		circuitbreaker.WithFailureThreshold(2),
+		circuitbreaker.WithCooldown(time.Minute),
+		circuitbreaker.WithClock(clk),
+	)
+
+	s := next.NewNetworkServiceServer(
+		connect.NewServerWithOptions(ctx,
+			func(_ context.Context, cc grpc.ClientConnInterface) networkservice.NetworkServiceClient {
+				return networkservice.NewNetworkServiceClient(cc)
+			},
+			connect.WithDialOptions(grpc.WithInsecure(), grpc.WithDefaultCallOptions(grpc.WaitForReady(true))),
+			connect.WithCircuitBreaker(breakers),
+		),
+		new(captureServer),
+	)
+
+	urlA := &url.URL{Scheme: "tcp", Host: "127.0.0.1:10010"}
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             "id",
+			NetworkService: "network-service",
+		},
+	}
+	requestWithTimeout := func(timeout time.Duration) (*networkservice.Connection, error) {
+		requestCtx, cancelRequest := context.WithTimeout(clienturlctx.WithClientURL(logger.WithLog(ctx), urlA), timeout)
+		defer cancelRequest()
+		return s.Request(requestCtx, request.Clone())
+	}
+
+	// 2. Requests failed because of the caller context are neutral
+
+	for i := 0; i < 3; i++ {
+		_, err := requestWithTimeout(50 * time.Millisecond)
+		require.Error(t, err)
+		require.Equal(t, circuitbreaker.Closed, breakers.State(urlA.String()))
+	}
+
+	// 3. Unavailable server opens the circuit
+
+	unavailable := &unavailableServer{}
+	atomic.StoreInt32(&unavailable.unavailable, 1)
+	err := startServer(ctx, urlA, unavailable)
+	require.NoError(t, err)
+
+	_, err = requestWithTimeout(time.Second)
+	require.Error(t, err)
+	require.Equal(t, circuitbreaker.Closed, breakers.State(urlA.String()))
+
+	// The second failure opens the circuit
+	_, err = requestWithTimeout(time.Second)
+	require.Error(t, err)
+	require.Equal(t, circuitbreaker.Open, breakers.State(urlA.String()))
+
+	// 4. Open circuit fails fast even with the server available
+
+	atomic.StoreInt32(&unavailable.unavailable, 0)
+
+	_, err = requestWithTimeout(time.Second)
+	require.Error(t, err)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Contains(t, err.Error(), circuitbreaker.ErrOpen.Error())
+
+	// 5. Half-open probe closes the circuit
+
+	clk.Add(time.Minute)
+	require.Equal(t, circuitbreaker.HalfOpen, breakers.State(urlA.String()))
+
+	conn, err := requestWithTimeout(5 * time.Second)
+	require.NoError(t, err)
+	require.Equal(t, circuitbreaker.Closed, breakers.State(urlA.String()))
+
+	_, err = s.Close(clienturlctx.WithClientURL(logger.WithLog(ctx), urlA), conn)
+	require.NoError(t, err)
+}
+
+type blockServer struct {
+	unavailableServer
+	requests int32
+	release  chan struct{}
+}
+
+func (s *blockServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if atomic.LoadInt32(&s.unavailable) == 1 {
+		return nil, status.Error(codes.Unavailable, "server is unavailable")
+	}
+	atomic.AddInt32(&s.requests, 1)
+	select {
+	case <-s.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *blockServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func TestConnectServer_CircuitBreakerSingleProbe(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 1. Create connectServer with circuit breakers and open the circuit
+
+	clk := clockmock.NewMock()
+	breakers := circuitbreaker.NewBreakers(
+		circuitbreaker.WithFailureThreshold(1),
+		circuitbreaker.WithCooldown(time.Minute),
+		circuitbreaker.WithClock(clk),
+	)
+
+	s := next.NewNetworkServiceServer(
+		connect.NewServerWithOptions(ctx,
+			func(_ context.Context, cc grpc.ClientConnInterface) networkservice.NetworkServiceClient {
+				return networkservice.NewNetworkServiceClient(cc)
+			},
+			connect.WithDialOptions(grpc.WithInsecure(), grpc.WithDefaultCallOptions(grpc.WaitForReady(true))),
+			connect.WithCircuitBreaker(breakers),
+		),
+		new(captureServer),
+	)
+
+	urlA := &url.URL{Scheme: "tcp", Host: "127.0.0.1:10030"}
+	requestCtx := clienturlctx.WithClientURL(logger.WithLog(ctx), urlA)
+	request := func(id string) (*networkservice.Connection, error) {
+		return s.Request(requestCtx, &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{
+				Id:                         id,
+				NetworkService:             "network-service",
+				NetworkServiceEndpointName: "nse-1",
+			},
+		})
+	}
+
+	block := &blockServer{release: make(chan struct{})}
+	atomic.StoreInt32(&block.unavailable, 1)
+	err := startServer(ctx, urlA, block)
+	require.NoError(t, err)
+
+	_, err = request("id-0")
+	require.Error(t, err)
+	require.Equal(t, circuitbreaker.Open, breakers.State("nse-1"))
+
+	// 2. Only a single probe is allowed for the half-open circuit
+
+	atomic.StoreInt32(&block.unavailable, 0)
+
+	clk.Add(time.Minute)
+	require.Equal(t, circuitbreaker.HalfOpen, breakers.State("nse-1"))
+
+	probeCh := make(chan error, 1)
+	go func() {
+		_, probeErr := request("id-1")
+		probeCh <- probeErr
+	}()
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&block.requests) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err = request("id-2")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), circuitbreaker.ErrOpen.Error())
+	require.Equal(t, int32(1), atomic.LoadInt32(&block.requests))
+
+	// 3. Successful probe closes the circuit
+
+	close(block.release)
+	require.NoError(t, <-probeCh)
+	require.Equal(t, circuitbreaker.Closed, breakers.State("nse-1"))
+
+	_, err = request("id-2")
+	require.NoError(t, err)
+}
+
+func TestConnectServer_CircuitBreakerKey(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	breakers := circuitbreaker.NewBreakers(circuitbreaker.WithFailureThreshold(1))
+
+	s := next.NewNetworkServiceServer(
+		connect.NewServerWithOptions(ctx,
+			func(_ context.Context, cc grpc.ClientConnInterface) networkservice.NetworkServiceClient {
+				return networkservice.NewNetworkServiceClient(cc)
+			},
+			connect.WithDialOptions(grpc.WithInsecure(), grpc.WithDefaultCallOptions(grpc.WaitForReady(true))),
+			connect.WithCircuitBreaker(breakers),
+		),
+		new(captureServer),
+	)
+
+	unavailable := &unavailableServer{}
+	atomic.StoreInt32(&unavailable.unavailable, 1)
+	forwarderURL := &url.URL{Scheme: "tcp", Host: "127.0.0.1:10031"}
+	err := startServer(ctx, forwarderURL, unavailable)
+	require.NoError(t, err)
+
+	// The forwarder failure opens the forwarder circuit, not the endpoint one
+	forwarderCtx := circuitbreaker.WithKey(clienturlctx.WithClientURL(logger.WithLog(ctx), forwarderURL), forwarderURL.String())
+	_, err = s.Request(forwarderCtx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:                         "id",
+			NetworkService:             "network-service",
+			NetworkServiceEndpointName: "nse-1",
+		},
+	})
+	require.Error(t, err)
+	require.Equal(t, circuitbreaker.Open, breakers.State(forwarderURL.String()))
+	require.Equal(t, circuitbreaker.Closed, breakers.State("nse-1"))
+}
+
+type failCloseServer struct {
+	failures, closes int32
+}
+
+func (s *failCloseServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *failCloseServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	if atomic.AddInt32(&s.closes, 1) <= atomic.LoadInt32(&s.failures) {
+		return nil, errors.New("close failed")
+	}
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func TestConnectServer_CloseFailed(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(logger.WithLog(context.Background()))
+	defer cancel()
+
+	// 1. Create connectServer and the remote server failing the first Close
+
+	s := next.NewNetworkServiceServer(
+		connect.NewServerWithOptions(ctx,
+			func(_ context.Context, cc grpc.ClientConnInterface) networkservice.NetworkServiceClient {
+				return networkservice.NewNetworkServiceClient(cc)
+			},
+			connect.WithDialOptions(grpc.WithInsecure(), grpc.WithDefaultCallOptions(grpc.WaitForReady(true))),
+			connect.WithCloseRetry(),
+		),
+		new(captureServer),
+	)
+
+	urlA := &url.URL{Scheme: "tcp", Host: "127.0.0.1:10020"}
+	remoteServer := &failCloseServer{failures: 1}
+	err := startServer(ctx, urlA, remoteServer)
+	require.NoError(t, err)
+
+	expires, err := ptypes.TimestampProto(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             "id",
+			NetworkService: "network-service",
+			Path: &networkservice.Path{
+				PathSegments: []*networkservice.PathSegment{{
+					Id:      "id",
+					Expires: expires,
+				}},
+			},
+		},
+	}
+
+	requestCtx, cancelRequest := context.WithTimeout(clienturlctx.WithClientURL(ctx, urlA), 5*time.Second)
+	defer cancelRequest()
+
+	conn, err := s.Request(requestCtx, request.Clone())
+	require.NoError(t, err)
+
+	// 2. Close fails on the remote server
+
+	_, err = s.Close(ctx, conn.Clone())
+	require.Error(t, err)
+
+	// 3. Close is retried with the remembered clientURL
+
+	_, err = s.Close(ctx, conn.Clone())
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&remoteServer.closes))
+
+	// 4. Connection is forgotten after the successful Close
+
+	_, err = s.Close(ctx, conn.Clone())
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&remoteServer.closes))
+
+	// 5. Connection is forgotten without the client Close after the retries have been given up
+
+	conn, err = s.Request(requestCtx, request.Clone())
+	require.NoError(t, err)
+
+	atomic.StoreInt32(&remoteServer.failures, 3)
+	_, err = s.Close(ctx, conn.Clone())
+	require.Error(t, err)
+	require.Equal(t, int32(3), atomic.LoadInt32(&remoteServer.closes))
+
+	_, err = s.Close(reaper.WithGivenUp(ctx), conn.Clone())
+	require.NoError(t, err)
+	require.Equal(t, int32(3), atomic.LoadInt32(&remoteServer.closes))
+
+	_, err = s.Close(ctx, conn.Clone())
+	require.NoError(t, err)
+	require.Equal(t, int32(3), atomic.LoadInt32(&remoteServer.closes))
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discover
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/labelselector"
+)
+
+// Explanation describes how the discover chain element finds the candidates for the request
+type Explanation struct {
+	// NetworkService is the requested network service
+	NetworkService *registry.NetworkService
+	// Endpoints are all the registered endpoints providing the network service
+	Endpoints []*registry.NetworkServiceEndpoint
+	// Matches are the network service matches evaluated in order, the last one is the matching one if it is Matched
+	Matches []*MatchExplanation
+	// Candidates are the endpoints selected by the matching match or all the Endpoints if no match is matching
+	Candidates []*registry.NetworkServiceEndpoint
+}
+
+// MatchExplanation describes a network service match evaluation
+type MatchExplanation struct {
+	// Index is the match index in the network service matches
+	Index int
+	// SourceSelector is the match source selector with the templates processed
+	SourceSelector labelselector.Selector
+	// Unmatched are the source selector requirements the request labels don't meet
+	Unmatched labelselector.Selector
+	// Matched is true if the request labels meet the source selector
+	Matched bool
+	// Routes are the match routes evaluated, only for the matching match
+	Routes []*RouteExplanation
+}
+
+// RouteExplanation describes a network service match route evaluation
+type RouteExplanation struct {
+	// DestinationSelector is the route destination selector with the templates processed
+	DestinationSelector labelselector.Selector
+	// Candidates are the endpoints meeting the destination selector
+	Candidates []*registry.NetworkServiceEndpoint
+	// Rejected are the endpoints not meeting the destination selector
+	Rejected []*RejectedEndpoint
+}
+
+// RejectedEndpoint is an endpoint rejected by the route destination selector
+type RejectedEndpoint struct {
+	Endpoint *registry.NetworkServiceEndpoint
+	// Unmatched are the destination selector requirements the endpoint labels don't meet
+	Unmatched labelselector.Selector
+}
+
+// Explain finds the candidates for the request the same way NewServer(nsClient, nseClient) does and explains the
+// decisions made. Unlike the chain element it doesn't wait for the network service or the endpoints to be registered.
+// If the evaluation fails, the returned Explanation still contains everything evaluated before the error.
+func Explain(ctx context.Context, nsClient registry.NetworkServiceRegistryClient, nseClient registry.NetworkServiceEndpointRegistryClient,
+	request *networkservice.NetworkServiceRequest) (*Explanation, error) {
+	nsStream, err := nsClient.Find(ctx, &registry.NetworkServiceQuery{
+		NetworkService: &registry.NetworkService{
+			Name:    request.GetConnection().GetNetworkService(),
+			Payload: request.GetConnection().GetPayload(),
+		},
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	nsList := registry.ReadNetworkServiceList(nsStream)
+	if len(nsList) == 0 {
+		return nil, errors.Errorf("ns:\"%v\" with payload:\"%v\" is not found",
+			request.GetConnection().GetNetworkService(), request.GetConnection().GetPayload())
+	}
+
+	explanation := &Explanation{
+		NetworkService: nsList[0],
+	}
+
+	query := &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{
+			NetworkServiceNames: []string{explanation.NetworkService.GetName()},
+		},
+	}
+	// The requested endpoint is used as is without the matches evaluation
+	nseName := request.GetConnection().GetNetworkServiceEndpointName()
+	if nseName != "" {
+		query.NetworkServiceEndpoint = &registry.NetworkServiceEndpoint{
+			Name: nseName,
+		}
+	}
+	nseStream, err := nseClient.Find(ctx, query)
+	if err != nil {
+		return explanation, errors.WithStack(err)
+	}
+	explanation.Endpoints = registry.ReadNetworkServiceEndpointList(nseStream)
+	if nseName != "" {
+		if len(explanation.Endpoints) == 0 {
+			return explanation, errors.Errorf("nse: %+v is not found", query.NetworkServiceEndpoint)
+		}
+		explanation.Candidates = explanation.Endpoints[:1]
+		return explanation, nil
+	}
+
+	explanation.Matches, explanation.Candidates, err = explainMatches(request.GetConnection().GetLabels(), explanation.NetworkService, explanation.Endpoints...)
+	return explanation, err
+}
@@ -18,65 +18,102 @@ package discover
 
 import (
 	"bytes"
+	"strings"
 	"text/template"
 
 	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/labelselector"
 )
 
-// isSubset checks if B is a subset of A. TODO: reconsider this as a part of "tools"
-func isSubset(a, b, nsLabels map[string]string) bool {
-	if len(a) < len(b) {
-		return false
-	}
-	for k, v := range b {
-		if a[k] != v {
-			result := ProcessLabels(v, nsLabels)
-			if a[k] != result {
-				return false
-			}
+// parseSelector processes the selector values templates with nsLabels and parses the result
+func parseSelector(selector, nsLabels map[string]string) (labelselector.Selector, error) {
+	processed := make(map[string]string, len(selector))
+	for k, v := range selector {
+		result, err := ProcessLabels(v, nsLabels)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid label selector template %s: %s", k, v)
 		}
+		processed[k] = result
+	}
+	rv, err := labelselector.Parse(processed)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid label selector")
 	}
-	return true
+	return rv, nil
 }
 
-func matchEndpoint(nsLabels map[string]string, ns *registry.NetworkService, networkServiceEndpoints ...*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+func matchEndpoint(nsLabels map[string]string, ns *registry.NetworkService, networkServiceEndpoints ...*registry.NetworkServiceEndpoint) ([]*registry.NetworkServiceEndpoint, error) {
+	_, nseCandidates, err := explainMatches(nsLabels, ns, networkServiceEndpoints...)
+	return nseCandidates, err
+}
+
+// explainMatches evaluates the ns matches in order until the first one with the matching source selector and returns
+// the evaluated matches with the candidates of the matching one
+func explainMatches(nsLabels map[string]string, ns *registry.NetworkService, networkServiceEndpoints ...*registry.NetworkServiceEndpoint) ([]*MatchExplanation, []*registry.NetworkServiceEndpoint, error) {
+	var matches []*MatchExplanation
 	// Iterate through the matches
-	for _, match := range ns.GetMatches() {
+	for i, match := range ns.GetMatches() {
 		// All match source selector labels should be present in the requested labels map
-		if !isSubset(nsLabels, match.GetSourceSelector(), nsLabels) {
+		sourceSelector, err := parseSelector(match.GetSourceSelector(), nsLabels)
+		if err != nil {
+			return matches, nil, errors.Wrapf(err, "network service %s source selector", ns.GetName())
+		}
+		matchExplanation := &MatchExplanation{
+			Index:          i,
+			SourceSelector: sourceSelector,
+			Unmatched:      sourceSelector.Unmatched(nsLabels),
+		}
+		matches = append(matches, matchExplanation)
+		if len(matchExplanation.Unmatched) != 0 {
 			continue
 		}
+		matchExplanation.Matched = true
+
 		nseCandidates := make([]*registry.NetworkServiceEndpoint, 0)
 		// Check all Destinations in that match
 		for _, destination := range match.GetRoutes() {
+			selector, err := parseSelector(destination.GetDestinationSelector(), nsLabels)
+			if err != nil {
+				return matches, nil, errors.Wrapf(err, "network service %s destination selector", ns.GetName())
+			}
+			routeExplanation := &RouteExplanation{
+				DestinationSelector: selector,
+			}
+			matchExplanation.Routes = append(matchExplanation.Routes, routeExplanation)
 			// Each NSE should be matched against that destination
 			for _, nse := range networkServiceEndpoints {
-				if isSubset(nse.GetNetworkServiceLabels()[ns.Name].Labels, destination.GetDestinationSelector(), nsLabels) {
-					nseCandidates = append(nseCandidates, nse)
+				if unmatched := selector.Unmatched(nse.GetNetworkServiceLabels()[ns.GetName()].GetLabels()); len(unmatched) != 0 {
+					routeExplanation.Rejected = append(routeExplanation.Rejected, &RejectedEndpoint{
+						Endpoint:  nse,
+						Unmatched: unmatched,
+					})
+					continue
 				}
+				routeExplanation.Candidates = append(routeExplanation.Candidates, nse)
+				nseCandidates = append(nseCandidates, nse)
 			}
 		}
-		return nseCandidates
+		return matches, nseCandidates, nil
 	}
-	return networkServiceEndpoints
+	return matches, networkServiceEndpoints, nil
 }
 
 // ProcessLabels generates matches based on destination label selectors that specify templating.
-func ProcessLabels(str string, vars interface{}) string {
-	tmpl, err := template.New("tmpl").Parse(str)
+func ProcessLabels(str string, vars interface{}) (string, error) {
+	if !strings.Contains(str, "{{") {
+		return str, nil
+	}
 
+	tmpl, err := template.New("tmpl").Parse(str)
 	if err != nil {
-		panic(err)
+		return "", errors.WithStack(err)
 	}
-	return process(tmpl, vars)
-}
 
-func process(t *template.Template, vars interface{}) string {
 	var tmplBytes bytes.Buffer
-
-	err := t.Execute(&tmplBytes, vars)
-	if err != nil {
-		panic(err)
+	if err := tmpl.Execute(&tmplBytes, vars); err != nil {
+		return "", errors.WithStack(err)
 	}
-	return tmplBytes.String()
+	return tmplBytes.String(), nil
 }
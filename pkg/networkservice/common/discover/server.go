@@ -138,7 +138,10 @@ func (d *discoverCandidatesServer) discoverNetworkServiceEndpoints(ctx context.C
 	}
 	nseList := registry.ReadNetworkServiceEndpointList(nseStream)
 
-	nseList = matchEndpoint(labels, ns, nseList...)
+	nseList, err = matchEndpoint(labels, ns, nseList...)
+	if err != nil {
+		return nil, err
+	}
 
 	if len(nseList) != 0 {
 		return nseList, nil
@@ -159,7 +162,10 @@ func (d *discoverCandidatesServer) discoverNetworkServiceEndpoints(ctx context.C
 			return nil, errors.Wrapf(ctx.Err(), "nse: %+v is not found", query.NetworkServiceEndpoint)
 		case nse, ok := <-nseCh:
 			if ok {
-				result := matchEndpoint(labels, ns, nse)
+				result, err := matchEndpoint(labels, ns, nse)
+				if err != nil {
+					return nil, err
+				}
 				if len(result) != 0 {
 					return result, nil
 				}
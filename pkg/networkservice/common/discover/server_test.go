@@ -377,3 +377,98 @@ func TestNoMatchServiceEndpointFound(t *testing.T) {
 	_, err = server.Request(ctx, request)
 	require.Error(t, err)
 }
+
+func TestMatchSetBasedSelectors(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	nsName := networkServiceName()
+	nsServer := memory.NewNetworkServiceRegistryServer()
+	_, err := nsServer.Register(context.Background(), &registry.NetworkService{
+		Name: nsName,
+		Matches: []*registry.Match{
+			{
+				SourceSelector: map[string]string{
+					"app":     "in(firewall, some-middle-app)",
+					"debug":   "doesnotexist()",
+					"version": "exists()",
+				},
+				Routes: []*registry.Destination{
+					{
+						DestinationSelector: map[string]string{
+							"app": "notin({{.app}}, vpn-gateway)",
+						},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	nseServer := registrynext.NewNetworkServiceEndpointRegistryServer(setid.NewNetworkServiceEndpointRegistryServer(), memory.NewNetworkServiceEndpointRegistryServer())
+	for _, nse := range endpoints() {
+		_, err = nseServer.Register(context.Background(), nse)
+		require.NoError(t, err)
+	}
+
+	server := next.NewNetworkServiceServer(
+		discover.NewServer(adapters.NetworkServiceServerToClient(nsServer), adapters.NetworkServiceEndpointServerToClient(nseServer)),
+		checkcontext.NewServer(t, func(t *testing.T, ctx context.Context) {
+			nses := discover.Candidates(ctx).Endpoints
+			require.Len(t, nses, 1)
+			require.Equal(t, labels(nsName, map[string]string{"app": "some-middle-app"}), nses[0].NetworkServiceLabels)
+		}),
+	)
+
+	_, err = server.Request(context.Background(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			NetworkService: nsName,
+			Labels: map[string]string{
+				"app":     "firewall",
+				"version": "1",
+			},
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestMatchInvalidSelectors(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	for name, selector := range map[string]map[string]string{
+		"bad template":   {"app": "{{.app"},
+		"template error": {"app": "{{.app.name}}"},
+		"empty value":    {"app": "notin(firewall,,vpn)"},
+		"empty in":       {"app": "in()"},
+	} {
+		nsName := networkServiceName()
+		nsServer := memory.NewNetworkServiceRegistryServer()
+		_, err := nsServer.Register(context.Background(), &registry.NetworkService{
+			Name: nsName,
+			Matches: []*registry.Match{
+				{
+					Routes: []*registry.Destination{
+						{DestinationSelector: selector},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		nseServer := memory.NewNetworkServiceEndpointRegistryServer()
+		_, err = nseServer.Register(context.Background(), endpoints()[0])
+		require.NoError(t, err)
+
+		server := discover.NewServer(adapters.NetworkServiceServerToClient(nsServer), adapters.NetworkServiceEndpointServerToClient(nseServer))
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		_, err = server.Request(ctx, &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{
+				NetworkService: nsName,
+				Labels:         map[string]string{"app": "firewall"},
+			},
+		})
+		cancel()
+		require.Error(t, err, name)
+		require.NotContains(t, err.Error(), context.DeadlineExceeded.Error(), name)
+	}
+}
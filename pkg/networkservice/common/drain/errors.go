@@ -0,0 +1,80 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drain
+
+import (
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DrainingReason is the errdetails.ErrorInfo reason of the errors returned by the draining server
+const DrainingReason = "DRAINING"
+
+type stackTracer interface {
+	StackTrace() errors.StackTrace
+}
+
+// drainingError is a gRPC status error with a stack trace, so the trace chain elements don't wrap it and the status
+// code is sent to the client
+type drainingError struct {
+	status *status.Status
+	stack  stackTracer
+}
+
+func newDrainingError(format string, args ...interface{}) error {
+	s := status.Newf(codes.FailedPrecondition, format, args...)
+	if withDetails, err := s.WithDetails(&errdetails.ErrorInfo{Reason: DrainingReason}); err == nil {
+		s = withDetails
+	}
+	return &drainingError{
+		status: s,
+		stack:  errors.WithStack(s.Err()).(stackTracer),
+	}
+}
+
+func (e *drainingError) Error() string {
+	return e.status.Err().Error()
+}
+
+func (e *drainingError) Cause() error {
+	return e.status.Err()
+}
+
+func (e *drainingError) GRPCStatus() *status.Status {
+	return e.status
+}
+
+func (e *drainingError) StackTrace() errors.StackTrace {
+	return e.stack.StackTrace()
+}
+
+// IsDraining returns true if err is returned by the draining server: it has the errdetails.ErrorInfo with the
+// DrainingReason
+func IsDraining(err error) bool {
+	s, ok := status.FromError(errors.Cause(err))
+	if !ok {
+		return false
+	}
+	for _, detail := range s.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetReason() == DrainingReason {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drain
+
+import (
+	"time"
+
+	registryapi "github.com/networkservicemesh/api/pkg/api/registry"
+)
+
+type drainOptions struct {
+	batchSize      int
+	batchInterval  time.Duration
+	registryClient registryapi.NetworkServiceEndpointRegistryClient
+	nse            *registryapi.NetworkServiceEndpoint
+}
+
+// Option is an option pattern for Drain
+type Option func(o *drainOptions)
+
+// WithBatch sets Drain to close the existing connections by batchSize connections every batchInterval, so the
+// clients get monitor DELETE events and heal elsewhere at a controlled rate
+func WithBatch(batchSize int, batchInterval time.Duration) Option {
+	return func(o *drainOptions) {
+		o.batchSize = batchSize
+		o.batchInterval = batchInterval
+	}
+}
+
+// WithUnregister sets Drain to unregister nse with registryClient before draining
+func WithUnregister(registryClient registryapi.NetworkServiceEndpointRegistryClient, nse *registryapi.NetworkServiceEndpoint) Option {
+	return func(o *drainOptions) {
+		o.registryClient = registryClient
+		o.nse = nse
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package drain provides a NetworkServiceServer chain element to take the server out of service: it stops accepting
+// new Requests and can move the existing connections out of the server
+package drain
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/serialize"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+	"github.com/networkservicemesh/sdk/pkg/tools/scheduler"
+)
+
+// Drainer is a server able to be drained
+type Drainer interface {
+	// Drain - stops accepting new Requests with the codes.FailedPrecondition status, the existing connections are
+	//         still refreshed. Drain returns after the WithBatch closes all the existing connections or ctx is done.
+	//         options - WithUnregister, WithBatch
+	Drain(ctx context.Context, options ...Option) error
+}
+
+type connectionInfo struct {
+	connID   string
+	conn     *networkservice.Connection
+	executor serialize.Executor
+	server   networkservice.NetworkServiceServer
+	task     *scheduler.Task
+}
+
+type drainServer struct {
+	ctx         context.Context
+	clock       clock.Clock
+	scheduler   *scheduler.Scheduler
+	draining    int32
+	connections map[string]*connectionInfo
+	mu          sync.Mutex
+}
+
+// NewServer - creates a new NetworkServiceServer chain element that can drain the subsequent chain. It should go
+//             after the `serialize` and before the `timeout` in chain.
+//             - ctx - context for the drain Close of the existing connections, clock.Clock from ctx is used for
+//                     the batch intervals, clock.Clock and scheduler.Scheduler from ctx are used to forget the
+//                     connections expired and closed by the `timeout`
+//             - drainerPtr - *Drainer. Same as the monitor.NewServer, it is a double pointer to get back the Drainer
+//                     while preserving the chain element constructor signature.
+func NewServer(ctx context.Context, drainerPtr *Drainer) networkservice.NetworkServiceServer {
+	rv := &drainServer{
+		ctx:         ctx,
+		clock:       clock.FromContext(ctx),
+		scheduler:   scheduler.FromContext(ctx),
+		connections: make(map[string]*connectionInfo),
+	}
+	*drainerPtr = rv
+	return rv
+}
+
+func (s *drainServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	connID := request.GetConnection().GetId()
+
+	if atomic.LoadInt32(&s.draining) == 1 && s.load(connID) == nil {
+		return nil, newDrainingError("server is draining, new connection is rejected: %v", connID)
+	}
+
+	executor := serialize.GetExecutor(ctx)
+	if executor == nil {
+		return nil, errors.New("no executor provided")
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &connectionInfo{
+		connID:   connID,
+		conn:     conn.Clone(),
+		executor: executor,
+		server:   next.Server(ctx),
+	}
+
+	// Connection is closed by the `timeout` after the expiration, so there is no need to drain it anymore
+	if expireTime, err := ptypes.Timestamp(conn.GetPrevPathSegment().GetExpires()); err == nil {
+		info.task = s.scheduler.AfterFunc(s.clock.Until(expireTime), func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			if s.connections[connID] == info {
+				delete(s.connections, connID)
+			}
+		})
+	}
+
+	s.mu.Lock()
+	if current, ok := s.connections[connID]; ok {
+		s.stop(current)
+	}
+	s.connections[connID] = info
+	s.mu.Unlock()
+
+	return conn, nil
+}
+
+func (s *drainServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.mu.Lock()
+	if info, ok := s.connections[conn.GetId()]; ok {
+		s.stop(info)
+	}
+	s.mu.Unlock()
+
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func (s *drainServer) Drain(ctx context.Context, options ...Option) error {
+	opts := new(drainOptions)
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	if opts.registryClient != nil {
+		if _, err := opts.registryClient.Unregister(ctx, opts.nse); err != nil {
+			return errors.Wrapf(err, "failed to unregister: %v", opts.nse.GetName())
+		}
+	}
+
+	atomic.StoreInt32(&s.draining, 1)
+
+	if opts.batchSize <= 0 {
+		return nil
+	}
+
+	for {
+		batch := s.batch(opts.batchSize)
+		if len(batch) == 0 {
+			return nil
+		}
+		for _, info := range batch {
+			s.closeConnection(info)
+		}
+		if len(batch) < opts.batchSize {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.clock.After(opts.batchInterval):
+		}
+	}
+}
+
+// stop should be called under the s.mu lock
+func (s *drainServer) stop(info *connectionInfo) {
+	if info.task != nil {
+		info.task.Stop()
+	}
+	delete(s.connections, info.connID)
+}
+
+func (s *drainServer) load(connID string) *connectionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.connections[connID]
+}
+
+func (s *drainServer) batch(size int) []*connectionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var batch []*connectionInfo
+	for _, info := range s.connections {
+		if len(batch) == size {
+			break
+		}
+		batch = append(batch, info)
+	}
+	return batch
+}
+
+func (s *drainServer) closeConnection(info *connectionInfo) {
+	<-info.executor.AsyncExec(func() {
+		connID := info.connID
+
+		// Connection can be already closed or re-requested
+		s.mu.Lock()
+		if s.connections[connID] != info {
+			s.mu.Unlock()
+			return
+		}
+		s.stop(info)
+		s.mu.Unlock()
+
+		if _, err := info.server.Close(s.ctx, info.conn); err != nil {
+			logger.Log(s.ctx).WithField("drainServer", "Drain").Errorf("failed to close connection: %v %+v", connID, err)
+		}
+	})
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drain_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	registryapi "github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/drain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/serialize"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/timeout"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatepath"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatetoken"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	adapter_registry "github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+)
+
+const (
+	tokenTimeout  = time.Hour
+	batchInterval = time.Minute
+	waitFor       = time.Second
+	tick          = 10 * time.Millisecond
+)
+
+type counterServer struct {
+	requests, closes int32
+}
+
+func (s *counterServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	atomic.AddInt32(&s.requests, 1)
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *counterServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	atomic.AddInt32(&s.closes, 1)
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func testClient(ctx context.Context, drainerPtr *drain.Drainer, counter *counterServer) networkservice.NetworkServiceClient {
+	return chain.NewNetworkServiceClient(
+		updatepath.NewClient("client"),
+		updatetoken.NewClient(func(_ credentials.AuthInfo) (string, time.Time, error) {
+			return "token", clock.FromContext(ctx).Now().Add(tokenTimeout), nil
+		}),
+		adapters.NewServerToClient(
+			chain.NewNetworkServiceServer(
+				updatepath.NewServer("server"),
+				serialize.NewServer(),
+				drain.NewServer(ctx, drainerPtr),
+				timeout.NewServer(ctx),
+				counter,
+			),
+		),
+	)
+}
+
+func TestDrainServer_RejectNewRequests(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(logger.WithLog(context.Background()))
+	defer cancel()
+
+	var drainer drain.Drainer
+	counter := new(counterServer)
+	client := testClient(ctx, &drainer, counter)
+
+	conn, err := client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "conn-1"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, drainer.Drain(ctx))
+
+	// Existing connection is refreshed
+	conn, err = client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: conn,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&counter.requests))
+
+	// New connection is rejected
+	_, err = client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "conn-2"},
+	})
+	require.Error(t, err)
+	require.True(t, drain.IsDraining(err))
+	require.Equal(t, int32(2), atomic.LoadInt32(&counter.requests))
+
+	_, err = client.Close(ctx, conn)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&counter.closes))
+}
+
+func TestDrainServer_Batch(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(logger.WithLog(context.Background()))
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	var drainer drain.Drainer
+	counter := new(counterServer)
+	client := testClient(ctx, &drainer, counter)
+
+	var conns []*networkservice.Connection
+	for _, id := range []string{"conn-1", "conn-2", "conn-3"} {
+		conn, err := client.Request(ctx, &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{Id: id},
+		})
+		require.NoError(t, err)
+		conns = append(conns, conn)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- drainer.Drain(ctx, drain.WithBatch(2, batchInterval))
+	}()
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&counter.closes) == 2
+	}, waitFor, tick)
+	require.Never(t, func() bool {
+		return atomic.LoadInt32(&counter.closes) > 2
+	}, 10*tick, tick)
+
+	require.Eventually(t, func() bool {
+		clockMock.Add(batchInterval)
+		return atomic.LoadInt32(&counter.closes) == 3
+	}, waitFor, tick)
+	require.NoError(t, <-errCh)
+
+	// Closed connections are rejected
+	_, err := client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: conns[0],
+	})
+	require.True(t, drain.IsDraining(err))
+
+	// Timers are stopped for the closed connections
+	clockMock.Add(2 * tokenTimeout)
+	require.Never(t, func() bool {
+		return atomic.LoadInt32(&counter.closes) > 3
+	}, 10*tick, tick)
+}
+
+func TestDrainServer_Expired(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(logger.WithLog(context.Background()))
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	var drainer drain.Drainer
+	counter := new(counterServer)
+	client := testClient(ctx, &drainer, counter)
+
+	_, err := client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "conn-1"},
+	})
+	require.NoError(t, err)
+
+	// Connection is closed by the timeout
+	clockMock.Add(tokenTimeout)
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&counter.closes) == 1
+	}, waitFor, tick)
+
+	// Expired connection is forgotten, so there is nothing to drain and no need to wait for the next batch
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- drainer.Drain(ctx, drain.WithBatch(1, batchInterval))
+	}()
+
+	select {
+	case err = <-errCh:
+		require.NoError(t, err)
+	case <-time.After(waitFor):
+		clockMock.Add(batchInterval)
+		require.NoError(t, <-errCh)
+		require.FailNow(t, "expired connection is drained")
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&counter.closes))
+}
+
+func TestDrainServer_Unregister(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(logger.WithLog(context.Background()))
+	defer cancel()
+
+	registryClient := adapter_registry.NetworkServiceEndpointServerToClient(memory.NewNetworkServiceEndpointRegistryServer())
+
+	nse, err := registryClient.Register(ctx, &registryapi.NetworkServiceEndpoint{Name: "nse"})
+	require.NoError(t, err)
+
+	var drainer drain.Drainer
+	_ = testClient(ctx, &drainer, new(counterServer))
+
+	require.NoError(t, drainer.Drain(ctx, drain.WithUnregister(registryClient, nse)))
+
+	stream, err := registryClient.Find(ctx, &registryapi.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: &registryapi.NetworkServiceEndpoint{Name: "nse"},
+	})
+	require.NoError(t, err)
+	require.Empty(t, registryapi.ReadNetworkServiceEndpointList(stream))
+}
@@ -0,0 +1,622 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.8.0
+// source: explain.proto
+
+package explain
+
+import (
+	context "context"
+	proto "github.com/golang/protobuf/proto"
+	networkservice "github.com/networkservicemesh/api/pkg/api/networkservice"
+	registry "github.com/networkservicemesh/api/pkg/api/registry"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// Explanation of the endpoint selection for a request
+type Explanation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Requested network service
+	NetworkService *registry.NetworkService `protobuf:"bytes,1,opt,name=network_service,json=networkService,proto3" json:"network_service,omitempty"`
+	// All the registered endpoints providing the network service
+	Endpoints []*registry.NetworkServiceEndpoint `protobuf:"bytes,2,rep,name=endpoints,proto3" json:"endpoints,omitempty"`
+	// Network service matches evaluated in order
+	Matches []*MatchExplanation `protobuf:"bytes,3,rep,name=matches,proto3" json:"matches,omitempty"`
+	// Endpoints selected by the matches
+	Candidates []*registry.NetworkServiceEndpoint `protobuf:"bytes,4,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	// Candidates in the order they would be tried
+	Ordering []*registry.NetworkServiceEndpoint `protobuf:"bytes,5,rep,name=ordering,proto3" json:"ordering,omitempty"`
+	// Error stopped the evaluation
+	Error string `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *Explanation) Reset() {
+	*x = Explanation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_explain_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Explanation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Explanation) ProtoMessage() {}
+
+func (x *Explanation) ProtoReflect() protoreflect.Message {
+	mi := &file_explain_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Explanation.ProtoReflect.Descriptor instead.
+func (*Explanation) Descriptor() ([]byte, []int) {
+	return file_explain_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Explanation) GetNetworkService() *registry.NetworkService {
+	if x != nil {
+		return x.NetworkService
+	}
+	return nil
+}
+
+func (x *Explanation) GetEndpoints() []*registry.NetworkServiceEndpoint {
+	if x != nil {
+		return x.Endpoints
+	}
+	return nil
+}
+
+func (x *Explanation) GetMatches() []*MatchExplanation {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+func (x *Explanation) GetCandidates() []*registry.NetworkServiceEndpoint {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+func (x *Explanation) GetOrdering() []*registry.NetworkServiceEndpoint {
+	if x != nil {
+		return x.Ordering
+	}
+	return nil
+}
+
+func (x *Explanation) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Network service match evaluation
+type MatchExplanation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Match index in the network service matches
+	Index uint32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// Source selector requirements with the templates processed
+	SourceSelector []string `protobuf:"bytes,2,rep,name=source_selector,json=sourceSelector,proto3" json:"source_selector,omitempty"`
+	// Source selector requirements the request labels don't meet
+	Unmatched []string `protobuf:"bytes,3,rep,name=unmatched,proto3" json:"unmatched,omitempty"`
+	// Request labels meet the source selector
+	Matched bool `protobuf:"varint,4,opt,name=matched,proto3" json:"matched,omitempty"`
+	// Match routes evaluation
+	Routes []*RouteExplanation `protobuf:"bytes,5,rep,name=routes,proto3" json:"routes,omitempty"`
+}
+
+func (x *MatchExplanation) Reset() {
+	*x = MatchExplanation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_explain_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MatchExplanation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchExplanation) ProtoMessage() {}
+
+func (x *MatchExplanation) ProtoReflect() protoreflect.Message {
+	mi := &file_explain_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchExplanation.ProtoReflect.Descriptor instead.
+func (*MatchExplanation) Descriptor() ([]byte, []int) {
+	return file_explain_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MatchExplanation) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *MatchExplanation) GetSourceSelector() []string {
+	if x != nil {
+		return x.SourceSelector
+	}
+	return nil
+}
+
+func (x *MatchExplanation) GetUnmatched() []string {
+	if x != nil {
+		return x.Unmatched
+	}
+	return nil
+}
+
+func (x *MatchExplanation) GetMatched() bool {
+	if x != nil {
+		return x.Matched
+	}
+	return false
+}
+
+func (x *MatchExplanation) GetRoutes() []*RouteExplanation {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+// Network service match route evaluation
+type RouteExplanation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Destination selector requirements with the templates processed
+	DestinationSelector []string `protobuf:"bytes,1,rep,name=destination_selector,json=destinationSelector,proto3" json:"destination_selector,omitempty"`
+	// Endpoints meeting the destination selector
+	Candidates []*registry.NetworkServiceEndpoint `protobuf:"bytes,2,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	// Endpoints not meeting the destination selector
+	Rejected []*RejectedEndpoint `protobuf:"bytes,3,rep,name=rejected,proto3" json:"rejected,omitempty"`
+}
+
+func (x *RouteExplanation) Reset() {
+	*x = RouteExplanation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_explain_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RouteExplanation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteExplanation) ProtoMessage() {}
+
+func (x *RouteExplanation) ProtoReflect() protoreflect.Message {
+	mi := &file_explain_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteExplanation.ProtoReflect.Descriptor instead.
+func (*RouteExplanation) Descriptor() ([]byte, []int) {
+	return file_explain_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RouteExplanation) GetDestinationSelector() []string {
+	if x != nil {
+		return x.DestinationSelector
+	}
+	return nil
+}
+
+func (x *RouteExplanation) GetCandidates() []*registry.NetworkServiceEndpoint {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+func (x *RouteExplanation) GetRejected() []*RejectedEndpoint {
+	if x != nil {
+		return x.Rejected
+	}
+	return nil
+}
+
+// Endpoint rejected by the route destination selector
+type RejectedEndpoint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Endpoint *registry.NetworkServiceEndpoint `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	// Destination selector requirements the endpoint labels don't meet
+	Unmatched []string `protobuf:"bytes,2,rep,name=unmatched,proto3" json:"unmatched,omitempty"`
+}
+
+func (x *RejectedEndpoint) Reset() {
+	*x = RejectedEndpoint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_explain_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RejectedEndpoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectedEndpoint) ProtoMessage() {}
+
+func (x *RejectedEndpoint) ProtoReflect() protoreflect.Message {
+	mi := &file_explain_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectedEndpoint.ProtoReflect.Descriptor instead.
+func (*RejectedEndpoint) Descriptor() ([]byte, []int) {
+	return file_explain_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RejectedEndpoint) GetEndpoint() *registry.NetworkServiceEndpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+func (x *RejectedEndpoint) GetUnmatched() []string {
+	if x != nil {
+		return x.Unmatched
+	}
+	return nil
+}
+
+var File_explain_proto protoreflect.FileDescriptor
+
+var file_explain_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x65, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x07, 0x65, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x1a, 0x14, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72,
+	0x6b, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0e,
+	0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xdb,
+	0x02, 0x0a, 0x0b, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x41,
+	0x0a, 0x0f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x72, 0x79, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x52, 0x0e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x3e, 0x0a, 0x09, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e,
+	0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x45, 0x6e,
+	0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x09, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x73, 0x12, 0x33, 0x0a, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x65, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x2e, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x40, 0x0a, 0x0a, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64,
+	0x61, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x72, 0x65, 0x67,
+	0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x0a, 0x63, 0x61,
+	0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x3c, 0x0a, 0x08, 0x6f, 0x72, 0x64, 0x65,
+	0x72, 0x69, 0x6e, 0x67, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x72, 0x65, 0x67,
+	0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x08, 0x6f, 0x72,
+	0x64, 0x65, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0xbc, 0x01, 0x0a,
+	0x10, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x27, 0x0a, 0x0f, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x5f, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x12, 0x1c, 0x0a, 0x09, 0x75, 0x6e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x09, 0x75, 0x6e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x12, 0x18,
+	0x0a, 0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x12, 0x31, 0x0a, 0x06, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x65, 0x78, 0x70, 0x6c, 0x61,
+	0x69, 0x6e, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x06, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x22, 0xbe, 0x01, 0x0a, 0x10,
+	0x52, 0x6f, 0x75, 0x74, 0x65, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x31, 0x0a, 0x14, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x13,
+	0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x6c, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x12, 0x40, 0x0a, 0x0a, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x72, 0x79, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x0a, 0x63, 0x61, 0x6e, 0x64, 0x69,
+	0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x35, 0x0a, 0x08, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x65, 0x78, 0x70, 0x6c, 0x61, 0x69,
+	0x6e, 0x2e, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x52, 0x08, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x22, 0x6e, 0x0a, 0x10,
+	0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x12, 0x3c, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x20, 0x2e, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x4e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x45, 0x6e, 0x64, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x75, 0x6e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x09, 0x75, 0x6e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x32, 0x58, 0x0a, 0x0e,
+	0x45, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x46,
+	0x0a, 0x07, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x12, 0x25, 0x2e, 0x6e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x14, 0x2e, 0x65, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x2e, 0x45, 0x78, 0x70, 0x6c, 0x61,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x45, 0x5a, 0x43, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x6d, 0x65, 0x73, 0x68, 0x2f, 0x73, 0x64, 0x6b, 0x2f, 0x70, 0x6b, 0x67, 0x2f,
+	0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x63,
+	0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x65, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_explain_proto_rawDescOnce sync.Once
+	file_explain_proto_rawDescData = file_explain_proto_rawDesc
+)
+
+func file_explain_proto_rawDescGZIP() []byte {
+	file_explain_proto_rawDescOnce.Do(func() {
+		file_explain_proto_rawDescData = protoimpl.X.CompressGZIP(file_explain_proto_rawDescData)
+	})
+	return file_explain_proto_rawDescData
+}
+
+var file_explain_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_explain_proto_goTypes = []interface{}{
+	(*Explanation)(nil),                          // 0: explain.Explanation
+	(*MatchExplanation)(nil),                     // 1: explain.MatchExplanation
+	(*RouteExplanation)(nil),                     // 2: explain.RouteExplanation
+	(*RejectedEndpoint)(nil),                     // 3: explain.RejectedEndpoint
+	(*registry.NetworkService)(nil),              // 4: registry.NetworkService
+	(*registry.NetworkServiceEndpoint)(nil),      // 5: registry.NetworkServiceEndpoint
+	(*networkservice.NetworkServiceRequest)(nil), // 6: networkservice.NetworkServiceRequest
+}
+var file_explain_proto_depIdxs = []int32{
+	4,  // 0: explain.Explanation.network_service:type_name -> registry.NetworkService
+	5,  // 1: explain.Explanation.endpoints:type_name -> registry.NetworkServiceEndpoint
+	1,  // 2: explain.Explanation.matches:type_name -> explain.MatchExplanation
+	5,  // 3: explain.Explanation.candidates:type_name -> registry.NetworkServiceEndpoint
+	5,  // 4: explain.Explanation.ordering:type_name -> registry.NetworkServiceEndpoint
+	2,  // 5: explain.MatchExplanation.routes:type_name -> explain.RouteExplanation
+	5,  // 6: explain.RouteExplanation.candidates:type_name -> registry.NetworkServiceEndpoint
+	3,  // 7: explain.RouteExplanation.rejected:type_name -> explain.RejectedEndpoint
+	5,  // 8: explain.RejectedEndpoint.endpoint:type_name -> registry.NetworkServiceEndpoint
+	6,  // 9: explain.ExplainService.Explain:input_type -> networkservice.NetworkServiceRequest
+	0,  // 10: explain.ExplainService.Explain:output_type -> explain.Explanation
+	10, // [10:11] is the sub-list for method output_type
+	9,  // [9:10] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_explain_proto_init() }
+func file_explain_proto_init() {
+	if File_explain_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_explain_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Explanation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_explain_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MatchExplanation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_explain_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RouteExplanation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_explain_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RejectedEndpoint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_explain_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_explain_proto_goTypes,
+		DependencyIndexes: file_explain_proto_depIdxs,
+		MessageInfos:      file_explain_proto_msgTypes,
+	}.Build()
+	File_explain_proto = out.File
+	file_explain_proto_rawDesc = nil
+	file_explain_proto_goTypes = nil
+	file_explain_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// ExplainServiceClient is the client API for ExplainService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ExplainServiceClient interface {
+	// Explain returns the endpoint selection explanation for the request without connecting to any endpoint
+	Explain(ctx context.Context, in *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*Explanation, error)
+}
+
+type explainServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExplainServiceClient(cc grpc.ClientConnInterface) ExplainServiceClient {
+	return &explainServiceClient{cc}
+}
+
+func (c *explainServiceClient) Explain(ctx context.Context, in *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*Explanation, error) {
+	out := new(Explanation)
+	err := c.cc.Invoke(ctx, "/explain.ExplainService/Explain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExplainServiceServer is the server API for ExplainService service.
+type ExplainServiceServer interface {
+	// Explain returns the endpoint selection explanation for the request without connecting to any endpoint
+	Explain(context.Context, *networkservice.NetworkServiceRequest) (*Explanation, error)
+}
+
+// UnimplementedExplainServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedExplainServiceServer struct {
+}
+
+func (*UnimplementedExplainServiceServer) Explain(context.Context, *networkservice.NetworkServiceRequest) (*Explanation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Explain not implemented")
+}
+
+func RegisterExplainServiceServer(s *grpc.Server, srv ExplainServiceServer) {
+	s.RegisterService(&_ExplainService_serviceDesc, srv)
+}
+
+func _ExplainService_Explain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(networkservice.NetworkServiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExplainServiceServer).Explain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/explain.ExplainService/Explain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExplainServiceServer).Explain(ctx, req.(*networkservice.NetworkServiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ExplainService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "explain.ExplainService",
+	HandlerType: (*ExplainServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Explain",
+			Handler:    _ExplainService_Explain_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "explain.proto",
+}
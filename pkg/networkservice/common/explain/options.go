@@ -0,0 +1,50 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package explain
+
+import (
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/selectendpoint"
+)
+
+type serverOptions struct {
+	previewer       selectendpoint.Previewer
+	authorizeServer networkservice.NetworkServiceServer
+}
+
+// Option is an option pattern for NewServer
+type Option func(o *serverOptions)
+
+// WithPreviewer sets the selectendpoint chain element to preview the selection with. It should be the same instance
+// the NetworkServiceServer chain uses. Default is selectendpoint.NewServer().
+func WithPreviewer(previewer selectendpoint.Previewer) Option {
+	return func(o *serverOptions) {
+		o.previewer = previewer
+	}
+}
+
+// WithAuthorizeServer sets authorization server chain element. It should be the same one the NetworkServiceServer
+// chain uses. Default is authorize.NewServer().
+func WithAuthorizeServer(authorizeServer networkservice.NetworkServiceServer) Option {
+	if authorizeServer == nil {
+		panic("Authorize server cannot be nil")
+	}
+	return func(o *serverOptions) {
+		o.authorizeServer = authorizeServer
+	}
+}
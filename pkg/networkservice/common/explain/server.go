@@ -0,0 +1,133 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package explain provides a debug gRPC service explaining why the endpoints are selected or rejected for a
+// NetworkServiceRequest by the discover and selectendpoint chain elements, without connecting to any of them
+package explain
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/authorize"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/selectendpoint"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/labelselector"
+)
+
+type explainServer struct {
+	nsClient        registry.NetworkServiceRegistryClient
+	nseClient       registry.NetworkServiceEndpointRegistryClient
+	previewer       selectendpoint.Previewer
+	authorizeServer networkservice.NetworkServiceServer
+}
+
+// NewServer returns a new ExplainServiceServer explaining the endpoint selection of the discover.NewServer(nsClient,
+// nseClient) and selectendpoint.NewServer(...) chain elements. Requests are authorized the same way as the
+// NetworkServiceServer Requests before the explanation.
+func NewServer(nsClient registry.NetworkServiceRegistryClient, nseClient registry.NetworkServiceEndpointRegistryClient, options ...Option) ExplainServiceServer {
+	o := &serverOptions{
+		previewer:       selectendpoint.NewServer(),
+		authorizeServer: authorize.NewServer(),
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+	return &explainServer{
+		nsClient:        nsClient,
+		nseClient:       nseClient,
+		previewer:       o.previewer,
+		authorizeServer: next.NewNetworkServiceServer(o.authorizeServer),
+	}
+}
+
+func (s *explainServer) Explain(ctx context.Context, request *networkservice.NetworkServiceRequest) (*Explanation, error) {
+	if _, err := s.authorizeServer.Request(ctx, request.Clone()); err != nil {
+		return nil, err
+	}
+	return Explain(ctx, s.nsClient, s.nseClient, request, s.previewer)
+}
+
+// Explain explains the endpoint selection for the request by the discover.NewServer(nsClient, nseClient) and
+// selectendpoint chain elements without connecting to any endpoint. Previewer should be the selectendpoint chain element
+// itself to get its current state into account, its state is not changed. If the matches evaluation fails, the error
+// is returned in the Explanation with everything evaluated before.
+func Explain(ctx context.Context, nsClient registry.NetworkServiceRegistryClient, nseClient registry.NetworkServiceEndpointRegistryClient,
+	request *networkservice.NetworkServiceRequest, previewer selectendpoint.Previewer) (*Explanation, error) {
+	discoverExplanation, err := discover.Explain(ctx, nsClient, nseClient, request)
+	if discoverExplanation == nil {
+		return nil, err
+	}
+
+	explanation := &Explanation{
+		NetworkService: discoverExplanation.NetworkService,
+		Endpoints:      discoverExplanation.Endpoints,
+		Candidates:     discoverExplanation.Candidates,
+	}
+	for _, match := range discoverExplanation.Matches {
+		explanation.Matches = append(explanation.Matches, matchExplanation(match))
+	}
+	if err != nil {
+		explanation.Error = err.Error()
+		return explanation, nil
+	}
+
+	// The requested endpoint is used as is without the selection
+	if request.GetConnection().GetNetworkServiceEndpointName() != "" {
+		explanation.Ordering = explanation.Candidates
+		return explanation, nil
+	}
+
+	explanation.Ordering = previewer.Preview(ctx, request, &discover.NetworkServiceCandidates{
+		NetworkService: discoverExplanation.NetworkService,
+		Endpoints:      discoverExplanation.Candidates,
+	})
+	return explanation, nil
+}
+
+func matchExplanation(match *discover.MatchExplanation) *MatchExplanation {
+	rv := &MatchExplanation{
+		Index:          uint32(match.Index),
+		SourceSelector: selectorStrings(match.SourceSelector),
+		Unmatched:      selectorStrings(match.Unmatched),
+		Matched:        match.Matched,
+	}
+	for _, route := range match.Routes {
+		routeExplanation := &RouteExplanation{
+			DestinationSelector: selectorStrings(route.DestinationSelector),
+			Candidates:          route.Candidates,
+		}
+		for _, rejected := range route.Rejected {
+			routeExplanation.Rejected = append(routeExplanation.Rejected, &RejectedEndpoint{
+				Endpoint:  rejected.Endpoint,
+				Unmatched: selectorStrings(rejected.Unmatched),
+			})
+		}
+		rv.Routes = append(rv.Routes, routeExplanation)
+	}
+	return rv
+}
+
+func selectorStrings(selector labelselector.Selector) []string {
+	var rv []string
+	for _, requirement := range selector {
+		rv = append(rv, requirement.String())
+	}
+	return rv
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package explain_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/explain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/selectendpoint"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+)
+
+const (
+	nsName = "ns"
+)
+
+func testRegistry(t *testing.T, matches ...*registry.Match) (registry.NetworkServiceRegistryClient, registry.NetworkServiceEndpointRegistryClient) {
+	nsServer := memory.NewNetworkServiceRegistryServer()
+	_, err := nsServer.Register(context.Background(), &registry.NetworkService{
+		Name:    nsName,
+		Matches: matches,
+	})
+	require.NoError(t, err)
+
+	nseServer := memory.NewNetworkServiceEndpointRegistryServer()
+	for name, labels := range map[string]map[string]string{
+		"nse-1": {"zone": "us"},
+		"nse-2": {"zone": "eu", "gpu": "true"},
+		"nse-3": {"zone": "eu"},
+	} {
+		_, err = nseServer.Register(context.Background(), &registry.NetworkServiceEndpoint{
+			Name:                name,
+			NetworkServiceNames: []string{nsName},
+			NetworkServiceLabels: map[string]*registry.NetworkServiceLabels{
+				nsName: {Labels: labels},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	return adapters.NetworkServiceServerToClient(nsServer), adapters.NetworkServiceEndpointServerToClient(nseServer)
+}
+
+func names(nses []*registry.NetworkServiceEndpoint) (rv []string) {
+	for _, nse := range nses {
+		rv = append(rv, nse.Name)
+	}
+	return rv
+}
+
+func TestExplain(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	nsClient, nseClient := testRegistry(t,
+		&registry.Match{
+			SourceSelector: map[string]string{"app": "vpn"},
+			Routes: []*registry.Destination{{
+				DestinationSelector: map[string]string{"zone": "eu"},
+			}},
+		},
+		&registry.Match{
+			SourceSelector: map[string]string{"app": "in(firewall, proxy)"},
+			Routes: []*registry.Destination{
+				{DestinationSelector: map[string]string{"zone": "{{.zone}}"}},
+				{DestinationSelector: map[string]string{"gpu": "exists()"}},
+			},
+		},
+	)
+
+	explanation, err := explain.Explain(context.Background(), nsClient, nseClient, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			NetworkService: nsName,
+			Labels:         map[string]string{"app": "firewall", "zone": "us"},
+		},
+	}, selectendpoint.NewServer())
+	require.NoError(t, err)
+	require.Empty(t, explanation.Error)
+
+	require.Equal(t, nsName, explanation.NetworkService.Name)
+	require.ElementsMatch(t, []string{"nse-1", "nse-2", "nse-3"}, names(explanation.Endpoints))
+
+	require.Len(t, explanation.Matches, 2)
+	require.False(t, explanation.Matches[0].Matched)
+	require.Equal(t, []string{"app: vpn"}, explanation.Matches[0].Unmatched)
+	require.Empty(t, explanation.Matches[0].Routes)
+
+	require.True(t, explanation.Matches[1].Matched)
+	require.Equal(t, uint32(1), explanation.Matches[1].Index)
+	require.Len(t, explanation.Matches[1].Routes, 2)
+
+	route := explanation.Matches[1].Routes[0]
+	require.Equal(t, []string{"zone: us"}, route.DestinationSelector)
+	require.Equal(t, []string{"nse-1"}, names(route.Candidates))
+	require.Len(t, route.Rejected, 2)
+	for _, rejected := range route.Rejected {
+		require.Equal(t, []string{"zone: us"}, rejected.Unmatched)
+	}
+
+	route = explanation.Matches[1].Routes[1]
+	require.Equal(t, []string{"gpu: exists()"}, route.DestinationSelector)
+	require.Equal(t, []string{"nse-2"}, names(route.Candidates))
+
+	require.Equal(t, []string{"nse-1", "nse-2"}, names(explanation.Candidates))
+	require.Equal(t, []string{"nse-1", "nse-2"}, names(explanation.Ordering))
+}
+
+func TestExplain_InvalidSelector(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	nsClient, nseClient := testRegistry(t,
+		&registry.Match{
+			SourceSelector: map[string]string{"app": "firewall"},
+		},
+		&registry.Match{
+			SourceSelector: map[string]string{"app": "in()"},
+		},
+	)
+
+	explanation, err := explain.Explain(context.Background(), nsClient, nseClient, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			NetworkService: nsName,
+			Labels:         map[string]string{"app": "vpn"},
+		},
+	}, selectendpoint.NewServer())
+	require.NoError(t, err)
+	require.Contains(t, explanation.Error, "in requires at least one value")
+	require.Len(t, explanation.Matches, 1)
+	require.Empty(t, explanation.Ordering)
+}
+
+func TestExplainServer_GRPC(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nsClient, nseClient := testRegistry(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	explain.RegisterExplainServiceServer(server, explain.NewServer(nsClient, nseClient))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	cc, err := grpc.DialContext(ctx, listener.Addr().String(), grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() { _ = cc.Close() }()
+
+	explanation, err := explain.NewExplainServiceClient(cc).Explain(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			NetworkService: nsName,
+		},
+	})
+	require.NoError(t, err)
+	require.Empty(t, explanation.Matches)
+	require.ElementsMatch(t, []string{"nse-1", "nse-2", "nse-3"}, names(explanation.Candidates))
+	require.ElementsMatch(t, []string{"nse-1", "nse-2", "nse-3"}, names(explanation.Ordering))
+
+	_, err = explain.NewExplainServiceClient(cc).Explain(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			NetworkService: "unknown",
+		},
+	})
+	require.Error(t, err)
+}
+
+type denyServer struct{}
+
+func (denyServer) Request(context.Context, *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return nil, errors.New("denied")
+}
+
+func (denyServer) Close(context.Context, *networkservice.Connection) (*empty.Empty, error) {
+	return nil, errors.New("denied")
+}
+
+func TestExplainServer_Unauthorized(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	nsClient, nseClient := testRegistry(t)
+
+	server := explain.NewServer(nsClient, nseClient, explain.WithAuthorizeServer(denyServer{}))
+
+	_, err := server.Explain(context.Background(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			NetworkService: nsName,
+		},
+	})
+	require.EqualError(t, err, "denied")
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heal
+
+import (
+	"math/rand"
+	"time"
+)
+
+type backoff struct {
+	initial time.Duration
+	max     time.Duration
+	jitter  float64
+	next    time.Duration
+	maxed   bool
+}
+
+func newBackoff(o *healOptions) *backoff {
+	return &backoff{
+		initial: o.initialBackoff,
+		max:     o.maxBackoff,
+		jitter:  o.jitter,
+		next:    o.initialBackoff,
+	}
+}
+
+// delay returns the next delay and doubles it
+func (b *backoff) delay() time.Duration {
+	delay := b.next
+	b.maxed = delay >= b.max
+	if b.next *= 2; b.next > b.max {
+		b.next = b.max
+	}
+	if b.jitter > 0 {
+		delay += time.Duration(b.jitter * (2*rand.Float64() - 1) * float64(delay)) //nolint:gosec
+	}
+	return delay
+}
+
+// exhausted returns true if the max delay has already been returned, so the retries can be given up
+func (b *backoff) exhausted() bool {
+	return b.maxed
+}
+
+func (b *backoff) reset() {
+	b.next = b.initial
+	b.maxed = false
+}
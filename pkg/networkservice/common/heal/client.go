@@ -21,7 +21,8 @@ package heal
 
 import (
 	"context"
-	"runtime"
+	"sync"
+	"time"
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
@@ -33,20 +34,29 @@ import (
 	"github.com/edwarnicke/serialize"
 
 	"github.com/networkservicemesh/sdk/pkg/tools/addressof"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
 )
 
+const (
+	healOperation = "heal.Request"
+)
+
 type healClient struct {
 	ctx                   context.Context
+	clock                 clock.Clock
 	client                networkservice.MonitorConnectionClient
 	onHeal                *networkservice.NetworkServiceClient
+	options               *healOptions
 	cancelHealMap         map[string]func() <-chan error
 	cancelHealMapExecutor serialize.Executor
 }
 
 // NewClient - creates a new networkservice.NetworkServiceClient chain element that implements the healing algorithm
 //             - ctx    - context for the lifecycle of the *Client* itself.  Cancel when discarding the client.
+//                        Timers are driven by the clock.Clock from ctx.
 //             - client - networkservice.MonitorConnectionClient that can be used to call MonitorConnection against the endpoint
 //             - onHeal - *networkservice.NetworkServiceClient.  Since networkservice.NetworkServiceClient is an interface
 //                        (and thus a pointer) *networkservice.NetworkServiceClient is a double pointer.  Meaning it
@@ -58,11 +68,26 @@ type healClient struct {
 //                        If we are part of a larger chain or a server, we should pass the resulting chain into
 //                        this constructor before we actually have a pointer to it.
 //                        If onHeal nil, onHeal will be pointed to the returned networkservice.NetworkServiceClient
-func NewClient(ctx context.Context, client networkservice.MonitorConnectionClient, onHeal *networkservice.NetworkServiceClient) networkservice.NetworkServiceClient {
+//             - options - heal mode, backoff, events and liveness check options
+func NewClient(ctx context.Context, client networkservice.MonitorConnectionClient, onHeal *networkservice.NetworkServiceClient, options ...Option) networkservice.NetworkServiceClient {
+	o := &healOptions{
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+		jitter:         defaultJitter,
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+	if err := o.validate(); err != nil {
+		panic(err.Error())
+	}
+
 	rv := &healClient{
 		ctx:           ctx,
+		clock:         clock.FromContext(ctx),
 		client:        client,
 		onHeal:        onHeal,
+		options:       o,
 		cancelHealMap: make(map[string]func() <-chan error),
 	}
 
@@ -78,7 +103,7 @@ func (f *healClient) Request(ctx context.Context, request *networkservice.Networ
 	if err != nil {
 		return nil, err
 	}
-	err = f.startHeal(request.Clone().SetRequestConnection(conn.Clone()), opts...)
+	err = f.startHeal(tracing.LinkFromContext(ctx), request.Clone().SetRequestConnection(conn.Clone()), opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -99,13 +124,17 @@ func (f *healClient) stopHeal(conn *networkservice.Connection) {
 	<-f.cancelHealMapExecutor.AsyncExec(func() {
 		cancelHeal = f.cancelHealMap[conn.GetId()]
 	})
-	<-cancelHeal()
+	// Close can come without the Request, when it is retried with a new client after the failed Close
+	if cancelHeal != nil {
+		<-cancelHeal()
+	}
 }
 
 // startHeal - start a healAsNeeded using the request as the request for re-request if healing is needed.
-func (f *healClient) startHeal(request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) error {
+//             link - tracing.Link to the span of the Request, heal spans are linked to it
+func (f *healClient) startHeal(link tracing.Link, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) error {
 	errCh := make(chan error, 1)
-	go f.healAsNeeded(request, errCh, opts...)
+	go f.healAsNeeded(link, request, errCh, opts...)
 	return <-errCh
 }
 
@@ -116,7 +145,7 @@ func (f *healClient) startHeal(request *networkservice.NetworkServiceRequest, op
 // healAsNeeded will then continue to monitor the servers opinions about the state of the connection until either
 // expireTime has passed or stopHeal is called (as in Close) or a different pathSegment is found via monitoring
 // indicating that a later Request has occurred and in doing so created its own healAsNeeded and so we can stop this one
-func (f *healClient) healAsNeeded(request *networkservice.NetworkServiceRequest, errCh chan error, opts ...grpc.CallOption) {
+func (f *healClient) healAsNeeded(link tracing.Link, request *networkservice.NetworkServiceRequest, errCh chan error, opts ...grpc.CallOption) {
 	// When we are done, close the errCh
 	defer close(errCh)
 
@@ -130,7 +159,7 @@ func (f *healClient) healAsNeeded(request *networkservice.NetworkServiceRequest,
 	}
 
 	// Set the ctx Deadline to expireTime based on the heal servers context
-	ctx, cancel := context.WithDeadline(f.ctx, expireTime)
+	ctx, cancel := f.clock.WithDeadline(f.ctx, expireTime)
 	defer cancel()
 	id := request.GetConnection().GetId()
 	f.cancelHealMapExecutor.AsyncExec(func() {
@@ -154,7 +183,24 @@ func (f *healClient) healAsNeeded(request *networkservice.NetworkServiceRequest,
 	// Tell the caller all is well by sending them a nil err so the call can continue
 	errCh <- nil
 
+	// healMutex doesn't let the liveness check and the monitor events heal the connection at the same time
+	healMutex := new(sync.Mutex)
+	if f.options.livenessCheck != nil {
+		var wg sync.WaitGroup
+		defer wg.Wait()
+
+		checkCtx, cancelCheck := context.WithCancel(ctx)
+		defer cancelCheck()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f.checkLiveness(checkCtx, link, request, healMutex, opts...)
+		}()
+	}
+
 	// Start looping over events
+	monitorBackoff := newBackoff(f.options)
 	for {
 		select {
 		case <-ctx.Done():
@@ -163,8 +209,8 @@ func (f *healClient) healAsNeeded(request *networkservice.NetworkServiceRequest,
 		}
 		event, err := recv.Recv()
 		if err != nil {
-			// If we get an error, try to get a new recv ... if that fails, loop around and try again until
-			// we succeed or the ctx is canceled or expires
+			// If we get an error, try to get a new recv ... if that fails, wait for the backoff delay, loop around
+			// and try again until we succeed or the ctx is canceled or expires
 			newRecv, newRecvErr := f.client.MonitorConnections(ctx, &networkservice.MonitorScopeSelector{
 				PathSegments: []*networkservice.PathSegment{
 					pathSegment,
@@ -172,19 +218,65 @@ func (f *healClient) healAsNeeded(request *networkservice.NetworkServiceRequest,
 			})
 			if newRecvErr == nil {
 				recv = newRecv
+				continue
+			}
+			if f.options.reselect && monitorBackoff.exhausted() {
+				// The server is unreachable for the whole backoff, so the connection should be healed with some
+				// other one
+				healMutex.Lock()
+				f.heal(ctx, link, request, opts...)
+				healMutex.Unlock()
+				return
+			}
+			if !f.wait(ctx, monitorBackoff.delay()) {
+				return
 			}
-			runtime.Gosched()
 			continue
 		}
+		monitorBackoff.reset()
 		select {
 		case <-ctx.Done():
 			return
 		default:
 		}
-		if err := f.processEvent(ctx, request, event, opts...); err != nil {
-			if err != nil {
+		healMutex.Lock()
+		err = f.processEvent(ctx, link, request, event, opts...)
+		healMutex.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// checkLiveness - runs the liveness check for the request connection every check interval, heals the connection on
+// the first failed check
+func (f *healClient) checkLiveness(ctx context.Context, link tracing.Link, request *networkservice.NetworkServiceRequest, healMutex sync.Locker, opts ...grpc.CallOption) {
+	ticker := f.clock.Ticker(f.options.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+		if err := f.options.livenessCheck.Check(ctx, request.GetConnection()); err != nil {
+			healMutex.Lock()
+			defer healMutex.Unlock()
+
+			// The connection can be already healed by the monitor event or closed while we were checking it
+			if ctx.Err() != nil {
 				return
 			}
+			if f.options.onEvent != nil {
+				f.options.onEvent(&Event{
+					Type:       EventLivenessCheckFailed,
+					Connection: request.GetConnection(),
+					Err:        err,
+				})
+			}
+			f.heal(ctx, link, request, opts...)
+			return
 		}
 	}
 }
@@ -225,7 +317,7 @@ func (f *healClient) initialMonitorSegment(ctx context.Context, pathSegment *net
 
 // processEvent - process event, calling (*f.OnHeal).Request(ctx,request,opts...) if the server does not have our connection.
 // returns a non-nil error if the event is such that we should no longer to continue to attempt to heal.
-func (f *healClient) processEvent(ctx context.Context, request *networkservice.NetworkServiceRequest, event *networkservice.ConnectionEvent, opts ...grpc.CallOption) error {
+func (f *healClient) processEvent(ctx context.Context, link tracing.Link, request *networkservice.NetworkServiceRequest, event *networkservice.ConnectionEvent, opts ...grpc.CallOption) error {
 	pathSegment := request.GetConnection().GetNextPathSegment()
 	switch event.GetType() {
 	case networkservice.ConnectionEventType_UPDATE:
@@ -246,25 +338,76 @@ func (f *healClient) processEvent(ctx context.Context, request *networkservice.N
 		fallthrough
 	case networkservice.ConnectionEventType_DELETE:
 		if event.Connections != nil && event.Connections[pathSegment.Id] != nil && pathSegment.Equal(event.GetConnections()[pathSegment.GetId()].GetCurrentPathSegment()) {
-			_, err := (*f.onHeal).Request(ctx, request, opts...)
-			for err != nil {
-				// Note: ctx here has deadline set to the expireTime of the pathSegment... so there is a finite stop point
-				// to trying to heal.  Additionally, a Close on the connection will trigger a cancel on ctx and
-				// wait for errCh to finish *before* calling Close down the line... so we won't accidentally
-				// recreate a closed connection.
-				runtime.Gosched()
-				select {
-				case <-ctx.Done():
-					return nil
-				default:
-				}
-				_, err := (*f.onHeal).Request(ctx, request, opts...)
-				if err != nil {
-					return err
-				}
-			}
+			f.heal(ctx, link, request, opts...)
 			return nil
 		}
 	}
 	return nil
 }
+
+// heal - re-requests the connection with (*f.onHeal).Request(ctx,request,opts...) until it succeeds or ctx is done
+func (f *healClient) heal(ctx context.Context, link tracing.Link, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) {
+	healCtx, span := tracing.StartLinkedSpan(ctx, healOperation, link)
+	defer span.Finish()
+
+	healRequest := request.Clone()
+	if f.options.reselect {
+		clearSelection(healRequest.GetConnection())
+	}
+
+	healBackoff := newBackoff(f.options)
+	var err error
+	for attempt := 1; ; attempt++ {
+		f.emit(span, &Event{
+			Type:       EventAttempt,
+			Attempt:    attempt,
+			Connection: healRequest.GetConnection(),
+			Err:        err,
+		})
+		var conn *networkservice.Connection
+		if conn, err = (*f.onHeal).Request(healCtx, healRequest.Clone(), opts...); err == nil {
+			f.emit(span, &Event{
+				Type:       EventSuccess,
+				Attempt:    attempt,
+				Connection: conn,
+			})
+			return
+		}
+		// Note: ctx here has deadline set to the expireTime of the pathSegment... so there is a finite stop point
+		// to trying to heal.  Additionally, a Close on the connection will trigger a cancel on ctx and
+		// wait for errCh to finish *before* calling Close down the line... so we won't accidentally
+		// recreate a closed connection.
+		if !f.wait(ctx, healBackoff.delay()) {
+			return
+		}
+	}
+}
+
+func (f *healClient) emit(span tracing.Span, event *Event) {
+	span.LogKV("event", event.Type.String(), "attempt", event.Attempt)
+	if f.options.onEvent != nil {
+		f.options.onEvent(event)
+	}
+}
+
+// clearSelection clears the NSE and the downstream path segments of the conn, so the NSE and the forwarder are
+// selected again on Request
+func clearSelection(conn *networkservice.Connection) {
+	conn.NetworkServiceEndpointName = ""
+	if path := conn.GetPath(); path != nil && int(path.GetIndex()) < len(path.GetPathSegments()) {
+		path.PathSegments = path.PathSegments[:path.GetIndex()+1]
+	}
+}
+
+// wait waits for the delay, returns false if ctx is done before
+func (f *healClient) wait(ctx context.Context, delay time.Duration) bool {
+	timer := f.clock.Timer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C():
+		return true
+	}
+}
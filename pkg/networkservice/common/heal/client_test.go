@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
@@ -38,6 +39,8 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/eventchannel"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
 	"github.com/networkservicemesh/sdk/pkg/tools/addressof"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
 	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
 )
 
@@ -157,3 +160,115 @@ func TestHealClient_EmptyInit(t *testing.T) {
 	})
 	require.Error(t, err)
 }
+
+func TestHealClient_Reselect(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+	eventCh := make(chan *networkservice.ConnectionEvent, 1)
+	defer close(eventCh)
+
+	healRequestCh := make(chan *networkservice.NetworkServiceRequest, 2)
+	onHeal := &testOnHeal{
+		RequestFunc: func(ctx context.Context, in *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+			healRequestCh <- in
+			if len(healRequestCh) == 1 {
+				return nil, errors.New("failed to heal")
+			}
+			return in.GetConnection(), nil
+		},
+	}
+
+	var healEvents []*heal.Event
+	healEventsCh := make(chan struct{})
+	onEvent := func(event *heal.Event) {
+		healEvents = append(healEvents, event)
+		if event.Type == heal.EventSuccess {
+			close(healEventsCh)
+		}
+	}
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+	monitorServer := eventchannel.NewMonitorServer(eventCh)
+	server := chain.NewNetworkServiceServer(
+		updatepath.NewServer("testServer"),
+		monitor.NewServer(ctx, &monitorServer),
+		updatetoken.NewServer(sandbox.GenerateTestToken),
+	)
+	client := chain.NewNetworkServiceClient(
+		updatepath.NewClient("testClient"),
+		heal.NewClient(ctx, adapters.NewMonitorServerToClient(monitorServer), addressof.NetworkServiceClient(onHeal),
+			heal.WithReselect(),
+			heal.WithBackoff(time.Hour, time.Hour),
+			heal.WithOnEvent(onEvent),
+		),
+		updatetoken.NewClient(sandbox.GenerateTestToken),
+		adapters.NewServerToClient(server),
+	)
+
+	requestCtx, reqCancelFunc := context.WithTimeout(ctx, waitForTimeout)
+	defer reqCancelFunc()
+	conn, err := client.Request(requestCtx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			NetworkService:             "ns-1",
+			NetworkServiceEndpointName: "nse-1",
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, conn.GetPath().GetPathSegments(), 2)
+
+	_, err = server.Close(requestCtx, conn.Clone())
+	require.NoError(t, err)
+
+	// The second heal attempt is sent after the backoff delay passes for the clockMock
+	require.Eventually(t, func() bool {
+		clockMock.Add(10 * time.Minute)
+		select {
+		case <-healEventsCh:
+			return true
+		default:
+			return false
+		}
+	}, waitHealTimeout, 10*time.Millisecond)
+
+	// Both heal attempts are sent without the NSE and the server path segment
+	require.Len(t, healRequestCh, 2)
+	for i := 0; i < 2; i++ {
+		healRequest := <-healRequestCh
+		require.Empty(t, healRequest.GetConnection().GetNetworkServiceEndpointName())
+		require.Len(t, healRequest.GetConnection().GetPath().GetPathSegments(), 1)
+		require.Equal(t, "testClient", healRequest.GetConnection().GetPath().GetPathSegments()[0].GetName())
+	}
+
+	require.Len(t, healEvents, 3)
+	require.Equal(t, heal.EventAttempt, healEvents[0].Type)
+	require.Equal(t, 1, healEvents[0].Attempt)
+	require.NoError(t, healEvents[0].Err)
+	require.Equal(t, heal.EventAttempt, healEvents[1].Type)
+	require.Equal(t, 2, healEvents[1].Attempt)
+	require.Error(t, healEvents[1].Err)
+	require.Equal(t, heal.EventSuccess, healEvents[2].Type)
+
+	_, err = client.Close(requestCtx, conn)
+	require.NoError(t, err)
+}
+
+func TestHealClient_InvalidBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, backoff := range [][2]time.Duration{
+		{0, time.Second},
+		{-time.Millisecond, time.Second},
+		{time.Second, time.Millisecond},
+	} {
+		require.Panics(t, func() {
+			heal.NewClient(ctx, nil, nil, heal.WithBackoff(backoff[0], backoff[1]))
+		}, "backoff: %v", backoff)
+	}
+	require.NotPanics(t, func() {
+		heal.NewClient(ctx, nil, nil, heal.WithBackoff(time.Second, time.Second))
+	})
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heal
+
+import (
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+// EventType is a heal event type
+type EventType int
+
+const (
+	// EventAttempt - heal Request is going to be sent
+	EventAttempt EventType = iota
+	// EventSuccess - heal Request has succeeded
+	EventSuccess
+	// EventLivenessCheckFailed - liveness check has failed, heal is going to be started
+	EventLivenessCheckFailed
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventAttempt:
+		return "heal-attempt"
+	case EventSuccess:
+		return "heal-success"
+	case EventLivenessCheckFailed:
+		return "heal-liveness-check-failed"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a heal event
+type Event struct {
+	Type EventType
+	// Attempt is the heal attempt number starting from 1, 0 for EventLivenessCheckFailed
+	Attempt int
+	// Connection is the requested connection for EventAttempt and the healed connection for EventSuccess
+	Connection *networkservice.Connection
+	// Err is the previous attempt error for EventAttempt and the check error for EventLivenessCheckFailed
+	Err error
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heal
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+const (
+	// DefaultUDPEchoPort is the UDP echo service port
+	DefaultUDPEchoPort = 7
+
+	udpEchoPayloadSize = 16
+)
+
+// LivenessCheck checks the connection datapath, a failed check triggers the connection heal
+type LivenessCheck interface {
+	// Check returns an error if the conn datapath doesn't work
+	Check(ctx context.Context, conn *networkservice.Connection) error
+}
+
+// LivenessCheckFunc is a function adapter for the LivenessCheck
+type LivenessCheckFunc func(ctx context.Context, conn *networkservice.Connection) error
+
+// Check calls f(ctx, conn)
+func (f LivenessCheckFunc) Check(ctx context.Context, conn *networkservice.Connection) error {
+	return f(ctx, conn)
+}
+
+type udpEchoLivenessCheck struct {
+	port    int
+	timeout time.Duration
+}
+
+// NewUDPEchoLivenessCheck returns a LivenessCheck sending a random UDP datagram from the connection IpContext source
+// address to the port of the connection IpContext destination address and expecting the same datagram back, as the
+// UDP echo service (RFC 862) does. Check fails if there is no reply in timeout.
+func NewUDPEchoLivenessCheck(port int, timeout time.Duration) LivenessCheck {
+	return &udpEchoLivenessCheck{
+		port:    port,
+		timeout: timeout,
+	}
+}
+
+func (c *udpEchoLivenessCheck) Check(ctx context.Context, conn *networkservice.Connection) error {
+	ipContext := conn.GetContext().GetIpContext()
+
+	dstIP := parseAddr(ipContext.GetDstIpAddr())
+	if dstIP == nil {
+		return errors.Errorf("invalid destination address: %v", ipContext.GetDstIpAddr())
+	}
+	dialer := &net.Dialer{}
+	if srcIP := parseAddr(ipContext.GetSrcIpAddr()); srcIP != nil {
+		dialer.LocalAddr = &net.UDPAddr{IP: srcIP}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	udpConn, err := dialer.DialContext(ctx, "udp", net.JoinHostPort(dstIP.String(), strconv.Itoa(c.port)))
+	if err != nil {
+		return errors.Wrap(err, "failed to dial the destination address")
+	}
+	defer func() { _ = udpConn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = udpConn.SetDeadline(deadline); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	payload := make([]byte, udpEchoPayloadSize)
+	if _, err = rand.Read(payload); err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err = udpConn.Write(payload); err != nil {
+		return errors.Wrap(err, "failed to send the echo request")
+	}
+
+	reply := make([]byte, udpEchoPayloadSize+1)
+	for {
+		n, readErr := udpConn.Read(reply)
+		if readErr != nil {
+			return errors.Wrap(readErr, "failed to receive the echo reply")
+		}
+		// Skip the stale replies to the previous checks
+		if bytes.Equal(reply[:n], payload) {
+			return nil
+		}
+	}
+}
+
+// parseAddr parses the <address>/<prefix> or <address> IP address, returns nil if addr is not valid
+func parseAddr(addr string) net.IP {
+	if ip, _, err := net.ParseCIDR(addr); err == nil {
+		return ip
+	}
+	return net.ParseIP(addr)
+}
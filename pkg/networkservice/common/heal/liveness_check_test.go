@@ -0,0 +1,185 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heal_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/heal"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/monitor"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatepath"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatetoken"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/eventchannel"
+	"github.com/networkservicemesh/sdk/pkg/tools/addressof"
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+)
+
+const (
+	checkTimeout  = 100 * time.Millisecond
+	checkInterval = 10 * time.Millisecond
+)
+
+// startUDPEchoServer starts a local stand-in for the datapath, echoing all received datagrams back
+func startUDPEchoServer(t *testing.T) (port int, stop func()) {
+	udpConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := udpConn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			_, _ = udpConn.WriteTo(buf[:n], addr)
+		}
+	}()
+
+	return udpConn.LocalAddr().(*net.UDPAddr).Port, func() {
+		_ = udpConn.Close()
+		<-done
+	}
+}
+
+func testConnection() *networkservice.Connection {
+	return &networkservice.Connection{
+		NetworkService: "ns-1",
+		Context: &networkservice.ConnectionContext{
+			IpContext: &networkservice.IPContext{
+				SrcIpAddr: "127.0.0.1/32",
+				DstIpAddr: "127.0.0.1/32",
+			},
+		},
+	}
+}
+
+func TestUDPEchoLivenessCheck(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	port, stop := startUDPEchoServer(t)
+
+	check := heal.NewUDPEchoLivenessCheck(port, checkTimeout)
+	require.NoError(t, check.Check(context.Background(), testConnection()))
+
+	stop()
+	require.Error(t, check.Check(context.Background(), testConnection()))
+
+	require.Error(t, check.Check(context.Background(), &networkservice.Connection{}))
+}
+
+func TestHealClient_LivenessCheck(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+	eventCh := make(chan *networkservice.ConnectionEvent, 1)
+	defer close(eventCh)
+
+	port, stop := startUDPEchoServer(t)
+	defer func() {
+		if stop != nil {
+			stop()
+		}
+	}()
+
+	var healCount int32
+	onHealCh := make(chan struct{})
+	onHeal := &testOnHeal{
+		RequestFunc: func(ctx context.Context, in *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+			if atomic.AddInt32(&healCount, 1) == 1 {
+				close(onHealCh)
+			}
+			return in.GetConnection(), nil
+		},
+	}
+
+	var checkErr error
+	onEvent := func(event *heal.Event) {
+		if event.Type == heal.EventLivenessCheckFailed {
+			checkErr = event.Err
+		}
+	}
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	monitorServer := eventchannel.NewMonitorServer(eventCh)
+	server := chain.NewNetworkServiceServer(
+		updatepath.NewServer("testServer"),
+		monitor.NewServer(ctx, &monitorServer),
+		updatetoken.NewServer(sandbox.GenerateTestToken),
+	)
+	client := chain.NewNetworkServiceClient(
+		updatepath.NewClient("testClient"),
+		heal.NewClient(ctx, adapters.NewMonitorServerToClient(monitorServer), addressof.NetworkServiceClient(onHeal),
+			heal.WithLivenessCheck(heal.NewUDPEchoLivenessCheck(port, checkTimeout), checkInterval),
+			heal.WithOnEvent(onEvent),
+		),
+		updatetoken.NewClient(sandbox.GenerateTestToken),
+		adapters.NewServerToClient(server),
+	)
+
+	requestCtx, reqCancelFunc := context.WithTimeout(ctx, waitForTimeout)
+	defer reqCancelFunc()
+	conn, err := client.Request(requestCtx, &networkservice.NetworkServiceRequest{
+		Connection: testConnection(),
+	})
+	require.NoError(t, err)
+
+	// Datapath works, so there should be no heal
+	time.Sleep(10 * checkInterval)
+	require.Equal(t, int32(0), atomic.LoadInt32(&healCount))
+
+	// Datapath is broken, so the connection should be healed
+	stop()
+	stop = nil
+
+	select {
+	case <-time.After(waitHealTimeout):
+		require.FailNow(t, "timeout waiting for heal")
+	case <-onHealCh:
+	}
+	require.Error(t, checkErr)
+
+	_, err = client.Close(requestCtx, conn)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&healCount))
+}
+
+func TestHealClient_InvalidLivenessCheckInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	livenessCheck := heal.LivenessCheckFunc(func(context.Context, *networkservice.Connection) error { return nil })
+
+	require.Panics(t, func() {
+		heal.NewClient(ctx, nil, nil, heal.WithLivenessCheck(livenessCheck, 0))
+	})
+	require.NotPanics(t, func() {
+		heal.NewClient(ctx, nil, nil, heal.WithLivenessCheck(livenessCheck, time.Second))
+	})
+}
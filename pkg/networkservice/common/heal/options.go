@@ -0,0 +1,97 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heal
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 5 * time.Second
+	defaultJitter         = 0.2
+)
+
+type healOptions struct {
+	reselect       bool
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	jitter         float64
+	onEvent        func(event *Event)
+	livenessCheck  LivenessCheck
+	checkInterval  time.Duration
+}
+
+// Option is an option pattern for NewClient
+type Option func(o *healOptions)
+
+// WithReselect enables the heal mode re-requesting the connection without the NSE and the downstream path segments, so
+// the NSE and the forwarder can be selected again and the connection can move to a healthy candidate if the selected
+// one is gone. The connection is healed if the server deletes it, if the liveness check fails or if the monitor can't
+// be re-established after the backoff reaches its max delay, a single failed monitor re-establish doesn't heal it.
+func WithReselect() Option {
+	return func(o *healOptions) {
+		o.reselect = true
+	}
+}
+
+// WithBackoff sets the delay between the failed heal attempts. The delay starts with initialBackoff and doubles after
+// each failed attempt up to maxBackoff. initialBackoff should be positive and not greater than maxBackoff, NewClient
+// panics otherwise. Default is 100ms, 5s.
+func WithBackoff(initialBackoff, maxBackoff time.Duration) Option {
+	return func(o *healOptions) {
+		o.initialBackoff = initialBackoff
+		o.maxBackoff = maxBackoff
+	}
+}
+
+// WithJitter sets the random fraction of the backoff delay to be added or subtracted, so the clients of the same
+// failed NSE don't retry all at once. Default is 0.2.
+func WithJitter(jitter float64) Option {
+	return func(o *healOptions) {
+		o.jitter = jitter
+	}
+}
+
+// WithOnEvent sets the function called on each heal Event
+func WithOnEvent(onEvent func(event *Event)) Option {
+	return func(o *healOptions) {
+		o.onEvent = onEvent
+	}
+}
+
+// WithLivenessCheck sets the datapath check run for each healed connection every checkInterval. A failed check heals
+// the connection the same way as the connection DELETE event does. checkInterval should be positive, NewClient panics
+// otherwise.
+func WithLivenessCheck(livenessCheck LivenessCheck, checkInterval time.Duration) Option {
+	return func(o *healOptions) {
+		o.livenessCheck = livenessCheck
+		o.checkInterval = checkInterval
+	}
+}
+
+func (o *healOptions) validate() error {
+	if o.initialBackoff <= 0 || o.maxBackoff < o.initialBackoff {
+		return errors.Errorf("invalid backoff: initial %v, max %v", o.initialBackoff, o.maxBackoff)
+	}
+	if o.livenessCheck != nil && o.checkInterval <= 0 {
+		return errors.Errorf("invalid liveness check interval: %v", o.checkInterval)
+	}
+	return nil
+}
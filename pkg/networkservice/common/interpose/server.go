@@ -24,6 +24,7 @@ import (
 
 	"github.com/networkservicemesh/sdk/pkg/tools/logger"
 
+	"github.com/networkservicemesh/sdk/pkg/tools/circuitbreaker"
 	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
 	"github.com/networkservicemesh/sdk/pkg/tools/stringurl"
 
@@ -46,6 +47,7 @@ type interposeServer struct {
 
 type connectionInfo struct {
 	clientConnID    string
+	crossConnID     string
 	endpointURL     *url.URL
 	interposeNSEURL *url.URL
 }
@@ -81,8 +83,20 @@ func (l *interposeServer) Request(ctx context.Context, request *networkservice.N
 	clientURL := clienturlctx.ClientURL(ctx)
 
 	connInfo, ok := l.activeConnection.Load(activeConnID)
+	if ok && connID == connInfo.clientConnID && int(ind) == len(conn.GetPath().GetPathSegments())-1 {
+		// The path segments after the current one are cleared (e.g. by heal with reselect), so the cross NSE should
+		// be selected again.
+		l.activeConnection.Delete(activeConnID)
+		if connInfo.crossConnID != "" {
+			l.activeConnection.Delete(connInfo.crossConnID)
+		}
+		ok = false
+	}
 	if ok {
 		if connID != activeConnID {
+			// Store the cross connection ID to clean it up together with the client connection
+			connInfo.crossConnID = connID
+			l.activeConnection.Store(activeConnID, connInfo)
 			l.activeConnection.Store(connID, connInfo)
 		}
 	} else {
@@ -92,7 +106,7 @@ func (l *interposeServer) Request(ctx context.Context, request *networkservice.N
 
 		// Iterate over all cross connect NSEs to check one with passed state.
 		l.endpoints.Range(func(key string, crossNSEURL *url.URL) bool {
-			crossCTX := clienturlctx.WithClientURL(ctx, crossNSEURL)
+			crossCTX := withCrossNSEURL(ctx, crossNSEURL)
 
 			// Store client connection and selected cross connection URL.
 			connInfo, _ = l.activeConnection.LoadOrStore(activeConnID, connectionInfo{
@@ -119,13 +133,13 @@ func (l *interposeServer) Request(ctx context.Context, request *networkservice.N
 
 	var crossCTX context.Context
 	if connID == connInfo.clientConnID {
-		crossCTX = clienturlctx.WithClientURL(ctx, connInfo.interposeNSEURL)
+		crossCTX = withCrossNSEURL(ctx, connInfo.interposeNSEURL)
 	} else {
 		// Go to endpoint URL if it matches one we had on previous step.
 		if clientURL != connInfo.endpointURL && *clientURL != *connInfo.endpointURL {
 			return nil, errors.Errorf("new selected endpoint URL %v doesn't match endpoint URL selected before interpose NSE %v", clientURL, connInfo.endpointURL)
 		}
-		crossCTX = ctx
+		crossCTX = withEndpointURL(ctx)
 	}
 
 	return next.Server(crossCTX).Request(crossCTX, request)
@@ -154,12 +168,27 @@ func (l *interposeServer) Close(ctx context.Context, conn *networkservice.Connec
 
 	var crossCTX context.Context
 	if conn.GetId() == connInfo.clientConnID {
-		crossCTX = clienturlctx.WithClientURL(ctx, connInfo.interposeNSEURL)
+		crossCTX = withCrossNSEURL(ctx, connInfo.interposeNSEURL)
 	} else {
-		crossCTX = ctx
+		crossCTX = withEndpointURL(ctx)
 	}
 
 	l.activeConnection.Delete(conn.GetId())
 
 	return next.Server(crossCTX).Close(crossCTX, conn)
 }
+
+// withCrossNSEURL returns the context for the call to the cross connect NSE, the circuit breakers key the call by the
+// cross connect NSE URL instead of the endpoint, so the broken cross connect NSE doesn't affect the endpoint circuit
+func withCrossNSEURL(ctx context.Context, crossNSEURL *url.URL) context.Context {
+	return circuitbreaker.WithKey(clienturlctx.WithClientURL(ctx, crossNSEURL), crossNSEURL.String())
+}
+
+// withEndpointURL returns the context for the call to the endpoint, it resets the key set for the cross connect NSE
+// call in case the cross connect NSE calls back in-process with the same context
+func withEndpointURL(ctx context.Context) context.Context {
+	if circuitbreaker.Key(ctx) == "" {
+		return ctx
+	}
+	return circuitbreaker.WithKey(ctx, "")
+}
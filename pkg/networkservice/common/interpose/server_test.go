@@ -33,6 +33,7 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/checks/checkcontext"
+	"github.com/networkservicemesh/sdk/pkg/tools/circuitbreaker"
 	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
 )
 
@@ -61,6 +62,7 @@ func TestInterposeServer(t *testing.T) {
 				clientURL := clienturlctx.ClientURL(ctx)
 				require.NotNil(t, clientURL)
 				require.Equal(t, crossNSEURL, *clientURL)
+				require.Equal(t, crossNSEURL.String(), circuitbreaker.Key(ctx))
 			}),
 		)),
 		adapters.NewServerToClient(next.NewNetworkServiceServer(
@@ -74,6 +76,7 @@ func TestInterposeServer(t *testing.T) {
 				clientURL := clienturlctx.ClientURL(ctx)
 				require.NotNil(t, clientURL)
 				require.Equal(t, nseURL, *clientURL)
+				require.Empty(t, circuitbreaker.Key(ctx))
 			}),
 		)),
 		adapters.NewServerToClient(next.NewNetworkServiceServer(
@@ -103,7 +106,20 @@ func TestInterposeServer(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, touchServer.touched)
 
-	// 3. Close
+	// 3. Reselect
+
+	request = request.Clone()
+	request.Connection = conn.Clone()
+	request.Connection.Path.Index = 0
+	request.Connection.Path.PathSegments = request.Connection.Path.PathSegments[:2]
+
+	touchServer.touched = false
+
+	conn, err = client.Request(context.TODO(), request)
+	require.NoError(t, err)
+	require.True(t, touchServer.touched)
+
+	// 4. Close
 
 	conn = conn.Clone()
 
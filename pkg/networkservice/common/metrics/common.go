@@ -0,0 +1,131 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics provides NetworkService chain elements collecting Prometheus/OpenMetrics metrics for the
+// Request/Close calls and the active connections
+package metrics
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/metrics"
+)
+
+const (
+	subsystem = "networkservice"
+
+	serverRole = "server"
+	clientRole = "client"
+
+	requestMethod = "Request"
+	closeMethod   = "Close"
+)
+
+var (
+	callLabelNames       = []string{"chain", "role", "method", "network_service", "network_service_endpoint"}
+	connectionLabelNames = []string{"chain", "role", "network_service", "network_service_endpoint"}
+)
+
+type connectionLabels struct {
+	networkService         string
+	networkServiceEndpoint string
+}
+
+type metricsCollectors struct {
+	duration *prometheus.HistogramVec
+	calls    *prometheus.CounterVec
+	active   *prometheus.GaugeVec
+}
+
+func newMetricsCollectors(registerer prometheus.Registerer) *metricsCollectors {
+	return &metricsCollectors{
+		duration: metrics.Register(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: subsystem,
+			Name:      "call_duration_seconds",
+			Help:      "Duration of the NetworkService Request/Close calls.",
+			Buckets:   prometheus.DefBuckets,
+		}, callLabelNames)).(*prometheus.HistogramVec),
+		calls: metrics.Register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: subsystem,
+			Name:      "calls_total",
+			Help:      "Number of the NetworkService Request/Close calls by the gRPC status code.",
+		}, append(callLabelNames, "code"))).(*prometheus.CounterVec),
+		active: metrics.Register(registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: subsystem,
+			Name:      "active_connections",
+			Help:      "Number of the active NetworkService connections.",
+		}, connectionLabelNames)).(*prometheus.GaugeVec),
+	}
+}
+
+type metricsElement struct {
+	chainName   string
+	role        string
+	collectors  *metricsCollectors
+	connections connectionLabelsMap
+}
+
+func newMetricsElement(role string, options ...Option) *metricsElement {
+	o := &metricsOptions{
+		registerer: prometheus.DefaultRegisterer,
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+	return &metricsElement{
+		chainName:  o.chainName,
+		role:       role,
+		collectors: newMetricsCollectors(o.registerer),
+	}
+}
+
+func (m *metricsElement) observe(method string, labels connectionLabels, startTime time.Time, err error) {
+	m.collectors.duration.
+		WithLabelValues(m.chainName, m.role, method, labels.networkService, labels.networkServiceEndpoint).
+		Observe(time.Since(startTime).Seconds())
+	m.collectors.calls.
+		WithLabelValues(m.chainName, m.role, method, labels.networkService, labels.networkServiceEndpoint, status.Code(errors.Cause(err)).String()).
+		Inc()
+}
+
+func (m *metricsElement) connected(connID string, labels connectionLabels) {
+	if oldLabels, ok := m.connections.Load(connID); ok {
+		if oldLabels == labels {
+			return
+		}
+		// Connection has been moved to another NSE, e.g. on heal
+		m.gauge(oldLabels).Dec()
+	}
+	m.connections.Store(connID, labels)
+	m.gauge(labels).Inc()
+}
+
+func (m *metricsElement) disconnected(connID string) {
+	if labels, ok := m.connections.LoadAndDelete(connID); ok {
+		m.gauge(labels).Dec()
+	}
+}
+
+func (m *metricsElement) gauge(labels connectionLabels) prometheus.Gauge {
+	return m.collectors.active.WithLabelValues(m.chainName, m.role, labels.networkService, labels.networkServiceEndpoint)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type metricsServer struct {
+	*metricsElement
+}
+
+// NewServer - returns a new metrics server chain element. It collects the duration and the gRPC status codes of the
+// Request/Close calls going through the rest of the chain and the number of the active connections.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	return &metricsServer{
+		metricsElement: newMetricsElement(serverRole, options...),
+	}
+}
+
+func (s *metricsServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	startTime := time.Now()
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+
+	labels := connectionLabels{
+		networkService:         request.GetConnection().GetNetworkService(),
+		networkServiceEndpoint: request.GetConnection().GetNetworkServiceEndpointName(),
+	}
+	if err == nil {
+		labels.networkServiceEndpoint = conn.GetNetworkServiceEndpointName()
+		s.connected(conn.GetId(), labels)
+	}
+	s.observe(requestMethod, labels, startTime, err)
+
+	return conn, err
+}
+
+func (s *metricsServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	startTime := time.Now()
+
+	rv, err := next.Server(ctx).Close(ctx, conn)
+
+	s.disconnected(conn.GetId())
+	s.observe(closeMethod, connectionLabels{
+		networkService:         conn.GetNetworkService(),
+		networkServiceEndpoint: conn.GetNetworkServiceEndpointName(),
+	}, startTime, err)
+
+	return rv, err
+}
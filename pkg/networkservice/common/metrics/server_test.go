@@ -0,0 +1,156 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/metrics"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	toolsmetrics "github.com/networkservicemesh/sdk/pkg/tools/metrics"
+)
+
+const (
+	chainName    = "nsmgr"
+	nsName       = "ns"
+	nseName      = "nse"
+	activeName   = "nsm_networkservice_active_connections"
+	callsName    = "nsm_networkservice_calls_total"
+	durationName = "nsm_networkservice_call_duration_seconds"
+)
+
+func request(connID string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:                         connID,
+			NetworkService:             nsName,
+			NetworkServiceEndpointName: nseName,
+		},
+	}
+}
+
+func gather(t *testing.T, registry *prometheus.Registry) map[string]*dto.MetricFamily {
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	rv := make(map[string]*dto.MetricFamily)
+	for _, family := range families {
+		rv[family.GetName()] = family
+	}
+	return rv
+}
+
+func labelValue(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
+
+func TestMetricsServer_RequestClose(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	registry := prometheus.NewRegistry()
+	server := metrics.NewServer(
+		metrics.WithChainName(chainName),
+		metrics.WithRegisterer(registry),
+	)
+
+	conn1, err := server.Request(context.Background(), request("id-1"))
+	require.NoError(t, err)
+
+	// Refresh should not change the number of the active connections
+	_, err = server.Request(context.Background(), request("id-1"))
+	require.NoError(t, err)
+
+	_, err = server.Request(context.Background(), request("id-2"))
+	require.NoError(t, err)
+
+	families := gather(t, registry)
+	require.Len(t, families[activeName].GetMetric(), 1)
+	active := families[activeName].GetMetric()[0]
+	require.Equal(t, chainName, labelValue(active, "chain"))
+	require.Equal(t, nsName, labelValue(active, "network_service"))
+	require.Equal(t, nseName, labelValue(active, "network_service_endpoint"))
+	require.Equal(t, 2., active.GetGauge().GetValue())
+	require.Equal(t, 3., families[callsName].GetMetric()[0].GetCounter().GetValue())
+	require.Equal(t, uint64(3), families[durationName].GetMetric()[0].GetHistogram().GetSampleCount())
+
+	_, err = server.Close(context.Background(), conn1)
+	require.NoError(t, err)
+
+	families = gather(t, registry)
+	require.Equal(t, 1., families[activeName].GetMetric()[0].GetGauge().GetValue())
+}
+
+func TestMetricsServer_ErrorCodes(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	registry := prometheus.NewRegistry()
+	server := next.NewNetworkServiceServer(
+		metrics.NewServer(metrics.WithRegisterer(registry)),
+		injecterror.NewServer(status.Error(codes.Unavailable, "NSE is not available")),
+	)
+
+	_, err := server.Request(context.Background(), request("id"))
+	require.Error(t, err)
+
+	families := gather(t, registry)
+	require.Len(t, families[callsName].GetMetric(), 1)
+	require.Equal(t, codes.Unavailable.String(), labelValue(families[callsName].GetMetric()[0], "code"))
+	require.Empty(t, families[activeName].GetMetric())
+}
+
+func TestMetricsClient_SharedRegisterer(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	registry := prometheus.NewRegistry()
+	client1 := metrics.NewClient(metrics.WithChainName("client-1"), metrics.WithRegisterer(registry))
+	client2 := metrics.NewClient(metrics.WithChainName("client-2"), metrics.WithRegisterer(registry))
+
+	_, err := client1.Request(context.Background(), request("id-1"))
+	require.NoError(t, err)
+	_, err = client2.Request(context.Background(), request("id-2"))
+	require.NoError(t, err)
+
+	require.Len(t, gather(t, registry)[activeName].GetMetric(), 2)
+
+	s := httptest.NewServer(toolsmetrics.Handler(registry))
+	defer s.Close()
+
+	resp, err := s.Client().Get(s.URL)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `nsm_networkservice_active_connections{chain="client-1",network_service="ns",network_service_endpoint="nse",role="client"} 1`)
+}
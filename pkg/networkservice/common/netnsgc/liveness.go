@@ -0,0 +1,60 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netnsgc
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/fs"
+)
+
+const procDir = "/proc"
+
+// LivenessCheck returns false if the network namespace with the inode doesn't exist anymore
+type LivenessCheck func(inode uintptr) bool
+
+// procNetNSInodes returns the inodes of the network namespaces of all the processes visible in /proc. Network
+// namespace without any process is kept alive only by the open files (like the ones received by recvfd), so it is
+// considered vanished.
+func procNetNSInodes() (map[uintptr]struct{}, error) {
+	dir, err := os.Open(procDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open %s", procDir)
+	}
+	defer func() { _ = dir.Close() }()
+
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", procDir)
+	}
+
+	inodes := make(map[uintptr]struct{})
+	for _, name := range names {
+		if _, atoiErr := strconv.Atoi(name); atoiErr != nil {
+			continue
+		}
+		// Process can exit during the scan, so the errors are skipped
+		if inode, inodeErr := fs.GetInode(filepath.Join(procDir, name, "ns", "net")); inodeErr == nil {
+			inodes[inode] = struct{}{}
+		}
+	}
+	return inodes, nil
+}
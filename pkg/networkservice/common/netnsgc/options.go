@@ -0,0 +1,45 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netnsgc
+
+import "time"
+
+const defaultCheckInterval = 10 * time.Second
+
+type gcOptions struct {
+	checkInterval time.Duration
+	livenessCheck LivenessCheck
+}
+
+// Option is an option pattern for NewServer
+type Option func(o *gcOptions)
+
+// WithCheckInterval sets how often the network namespaces of the connections are checked. Default is 10s, checkInterval
+// should be positive, NewServer panics otherwise.
+func WithCheckInterval(checkInterval time.Duration) Option {
+	return func(o *gcOptions) {
+		o.checkInterval = checkInterval
+	}
+}
+
+// WithLivenessCheck sets the custom network namespace liveness check. Default check looks in /proc for a process
+// running in the network namespace.
+func WithLivenessCheck(livenessCheck LivenessCheck) Option {
+	return func(o *gcOptions) {
+		o.livenessCheck = livenessCheck
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package netnsgc provides a NetworkServiceServer chain element closing the kernel mechanism connections whose client
+// network namespace has disappeared, so the connections of the deleted client pod don't live until the timeout
+package netnsgc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/edwarnicke/grpcfd"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/common"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/fs"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+)
+
+type connectionInfo struct {
+	inode  uintptr
+	conn   *networkservice.Connection
+	server networkservice.NetworkServiceServer
+}
+
+type netnsGCServer struct {
+	ctx         context.Context
+	options     *gcOptions
+	connections map[string]*connectionInfo
+	mu          sync.Mutex
+}
+
+// NewServer - creates a new NetworkServiceServer chain element recording the client network namespace inode of the
+//             kernel mechanism connections and periodically checking if the network namespace still exists. The
+//             connections with the vanished network namespace are closed with the subsequent chain.
+//             - ctx - context for the Close of the connections and the checks lifetime, clock.Clock from ctx is
+//                     used for the check interval
+//             - options - WithCheckInterval, WithLivenessCheck
+func NewServer(ctx context.Context, options ...Option) networkservice.NetworkServiceServer {
+	o := &gcOptions{
+		checkInterval: defaultCheckInterval,
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+	if o.checkInterval <= 0 {
+		panic(fmt.Sprintf("invalid check interval: %v", o.checkInterval))
+	}
+
+	rv := &netnsGCServer{
+		ctx:         ctx,
+		options:     o,
+		connections: make(map[string]*connectionInfo),
+	}
+
+	ticker := clock.FromContext(ctx).Ticker(o.checkInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				rv.collect()
+			}
+		}
+	}()
+
+	return rv
+}
+
+func (s *netnsGCServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	logEntry := logger.Log(ctx).WithField("netnsGCServer", "Request")
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	inode, ok, err := netnsInode(conn.GetMechanism())
+	if err != nil {
+		logEntry.Warnf("failed to get network namespace inode, connection is not checked: %v %+v", conn.GetId(), err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ok {
+		s.connections[conn.GetId()] = &connectionInfo{
+			inode:  inode,
+			conn:   conn.Clone(),
+			server: next.Server(ctx),
+		}
+	} else {
+		delete(s.connections, conn.GetId())
+	}
+
+	return conn, nil
+}
+
+func (s *netnsGCServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.mu.Lock()
+	delete(s.connections, conn.GetId())
+	s.mu.Unlock()
+
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func (s *netnsGCServer) collect() {
+	logEntry := logger.Log(s.ctx).WithField("netnsGCServer", "collect")
+
+	isAlive := s.options.livenessCheck
+	if isAlive == nil {
+		inodes, err := procNetNSInodes()
+		if err != nil {
+			logEntry.Warnf("failed to list network namespaces: %+v", err)
+			return
+		}
+		isAlive = func(inode uintptr) bool {
+			_, ok := inodes[inode]
+			return ok
+		}
+	}
+
+	s.mu.Lock()
+	infos := make([]*connectionInfo, 0, len(s.connections))
+	for _, info := range s.connections {
+		infos = append(infos, info)
+	}
+	s.mu.Unlock()
+
+	for _, info := range infos {
+		if isAlive(info.inode) {
+			continue
+		}
+
+		// Connection can be already closed or re-requested
+		s.mu.Lock()
+		current := s.connections[info.conn.GetId()]
+		if current == info {
+			delete(s.connections, info.conn.GetId())
+		}
+		s.mu.Unlock()
+		if current != info {
+			continue
+		}
+
+		logEntry.Infof("network namespace %v has disappeared, closing the connection: %v", info.inode, info.conn.GetId())
+		if _, err := info.server.Close(s.ctx, info.conn.Clone()); err != nil {
+			logEntry.Errorf("failed to close the connection: %v %+v", info.conn.GetId(), err)
+		}
+	}
+}
+
+// netnsInode returns the inode of the kernel mechanism network namespace. The network namespace is passed either as
+// a file URL or as an inode URL if it has been sent with sendfd.
+func netnsInode(mechanism *networkservice.Mechanism) (inode uintptr, ok bool, err error) {
+	if mechanism.GetType() != kernel.MECHANISM {
+		return 0, false, nil
+	}
+	netnsURLStr, ok := mechanism.GetParameters()[common.InodeURL]
+	if !ok {
+		return 0, false, nil
+	}
+
+	netnsURL, err := url.Parse(netnsURLStr)
+	if err != nil {
+		return 0, false, errors.WithStack(err)
+	}
+
+	switch netnsURL.Scheme {
+	case "file":
+		if inode, err = fs.GetInode(netnsURL.Path); err != nil {
+			return 0, false, err
+		}
+		return inode, true, nil
+	case "inode":
+		var ino uint64
+		if _, ino, err = grpcfd.URLStringToDevIno(netnsURLStr); err != nil {
+			return 0, false, errors.WithStack(err)
+		}
+		return uintptr(ino), true, nil
+	default:
+		return 0, false, errors.Errorf("unsupported network namespace URL: %v", netnsURLStr)
+	}
+}
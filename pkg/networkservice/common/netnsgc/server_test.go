@@ -0,0 +1,184 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package netnsgc_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/common"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/netnsgc"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/fs"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+)
+
+const (
+	checkInterval = time.Second
+	waitFor       = time.Second
+	tick          = 10 * time.Millisecond
+)
+
+type closeCounterServer struct {
+	closes sync.Map
+}
+
+func (s *closeCounterServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *closeCounterServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.closes.Store(conn.GetId(), true)
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func (s *closeCounterServer) isClosed(connID string) bool {
+	_, ok := s.closes.Load(connID)
+	return ok
+}
+
+func kernelRequest(connID, netnsURL string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: connID,
+			Mechanism: &networkservice.Mechanism{
+				Cls:  "LOCAL",
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					common.InodeURL: netnsURL,
+				},
+			},
+		},
+	}
+}
+
+func TestNetNSGCServer_LivenessCheck(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(logger.WithLog(context.Background()))
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	netnsFile := filepath.Join(t.TempDir(), "net")
+	require.NoError(t, ioutil.WriteFile(netnsFile, nil, 0600))
+	fileInode, err := fs.GetInode(netnsFile)
+	require.NoError(t, err)
+
+	var deadInodes sync.Map
+	counter := new(closeCounterServer)
+	server := chain.NewNetworkServiceServer(
+		netnsgc.NewServer(ctx,
+			netnsgc.WithCheckInterval(checkInterval),
+			netnsgc.WithLivenessCheck(func(inode uintptr) bool {
+				_, dead := deadInodes.Load(inode)
+				return !dead
+			}),
+		),
+		counter,
+	)
+
+	_, err = server.Request(ctx, kernelRequest("file-conn", "file://"+netnsFile))
+	require.NoError(t, err)
+	_, err = server.Request(ctx, kernelRequest("inode-conn", "inode://4/42"))
+	require.NoError(t, err)
+	_, err = server.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "no-mechanism-conn"},
+	})
+	require.NoError(t, err)
+
+	// All network namespaces are alive
+	clockMock.Add(checkInterval)
+	require.Never(t, func() bool {
+		return counter.isClosed("file-conn") || counter.isClosed("inode-conn")
+	}, 10*tick, tick)
+
+	// Network namespace from the file URL disappears
+	deadInodes.Store(fileInode, true)
+	require.Eventually(t, func() bool {
+		clockMock.Add(checkInterval)
+		return counter.isClosed("file-conn")
+	}, waitFor, tick)
+	require.False(t, counter.isClosed("inode-conn"))
+
+	// Network namespace from the inode URL disappears
+	deadInodes.Store(uintptr(42), true)
+	require.Eventually(t, func() bool {
+		clockMock.Add(checkInterval)
+		return counter.isClosed("inode-conn")
+	}, waitFor, tick)
+
+	require.False(t, counter.isClosed("no-mechanism-conn"))
+}
+
+func TestNetNSGCServer_ProcLivenessCheck(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(logger.WithLog(context.Background()))
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	counter := new(closeCounterServer)
+	server := chain.NewNetworkServiceServer(
+		netnsgc.NewServer(ctx, netnsgc.WithCheckInterval(checkInterval)),
+		counter,
+	)
+
+	_, err := server.Request(ctx, kernelRequest("alive-conn", "file:///proc/thread-self/ns/net"))
+	require.NoError(t, err)
+	// There is no network namespace with the 0 inode
+	_, err = server.Request(ctx, kernelRequest("dead-conn", "inode://4/0"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		clockMock.Add(checkInterval)
+		return counter.isClosed("dead-conn")
+	}, waitFor, tick)
+	require.False(t, counter.isClosed("alive-conn"))
+}
+
+func TestNetNSGCServer_InvalidCheckInterval(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, interval := range []time.Duration{0, -checkInterval} {
+		require.Panics(t, func() {
+			netnsgc.NewServer(ctx, netnsgc.WithCheckInterval(interval))
+		})
+	}
+}
@@ -0,0 +1,348 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persist_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/monitor"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/persist"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/serialize"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/timeout"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatepath"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatetoken"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+)
+
+const (
+	clientName   = "client"
+	serverName   = "nsmgr"
+	tokenTimeout = time.Hour
+	waitFor      = time.Second
+	tick         = 10 * time.Millisecond
+)
+
+type tailServer struct {
+	requestCh chan *networkservice.NetworkServiceRequest
+	closeCh   chan *networkservice.Connection
+}
+
+func newTailServer() *tailServer {
+	return &tailServer{
+		requestCh: make(chan *networkservice.NetworkServiceRequest, 10),
+		closeCh:   make(chan *networkservice.Connection, 10),
+	}
+}
+
+func (s *tailServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	s.requestCh <- request.Clone()
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *tailServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.closeCh <- conn.Clone()
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func newServer(ctx context.Context, store persist.Store, monitorServer *networkservice.MonitorConnectionServer, tail *tailServer) networkservice.NetworkServiceServer {
+	return chain.NewNetworkServiceServer(
+		updatepath.NewServer(serverName),
+		serialize.NewServer(),
+		timeout.NewServer(ctx),
+		monitor.NewServer(ctx, monitorServer),
+		updatetoken.NewServer(tokenGenerator(ctx)),
+		persist.NewServer(store),
+		tail,
+	)
+}
+
+func tokenGenerator(ctx context.Context) func(credentials.AuthInfo) (string, time.Time, error) {
+	return func(_ credentials.AuthInfo) (string, time.Time, error) {
+		return "token", clock.FromContext(ctx).Now().Add(tokenTimeout), nil
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	ctx := logger.WithLog(context.Background())
+	dir := t.TempDir()
+
+	store, err := persist.NewFileStore(dir)
+	require.NoError(t, err)
+
+	for _, id := range []string{"id-1", "id/2"} {
+		require.NoError(t, store.Save(&networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{
+				Id:             id,
+				NetworkService: "ns",
+			},
+		}))
+	}
+	require.NoError(t, store.Save(&networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             "id-1",
+			NetworkService: "ns-1",
+		},
+	}))
+
+	// Another store on the same directory
+	store, err = persist.NewFileStore(dir)
+	require.NoError(t, err)
+
+	requests, err := store.Load(ctx)
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+
+	services := make(map[string]string)
+	for _, request := range requests {
+		services[request.GetConnection().GetId()] = request.GetConnection().GetNetworkService()
+	}
+	require.Equal(t, map[string]string{"id-1": "ns-1", "id/2": "ns"}, services)
+
+	require.NoError(t, store.Delete("id-1"))
+	require.NoError(t, store.Delete("id-1"))
+
+	requests, err = store.Load(ctx)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	require.Equal(t, "id/2", requests[0].GetConnection().GetId())
+}
+
+func TestFileStore_BadFiles(t *testing.T) {
+	ctx := logger.WithLog(context.Background())
+	dir := t.TempDir()
+
+	store, err := persist.NewFileStore(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Save(&networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "id",
+		},
+	}))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "bad.json"), []byte("{"), 0o600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "interrupted.json.tmp"), []byte("{}"), 0o600))
+
+	requests, err := store.Load(ctx)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	require.Equal(t, "id", requests[0].GetConnection().GetId())
+
+	// Bad and temporary files are removed
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
+
+func TestRestore(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(logger.WithLog(context.Background()))
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	store, err := persist.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	var monitorServer networkservice.MonitorConnectionServer
+	tail := newTailServer()
+	client := chain.NewNetworkServiceClient(
+		updatepath.NewClient(clientName),
+		updatetoken.NewClient(tokenGenerator(ctx)),
+		adapters.NewServerToClient(newServer(ctx, store, &monitorServer, tail)),
+	)
+
+	conn, err := client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			NetworkService: "ns",
+		},
+	})
+	require.NoError(t, err)
+	serverConnID := (<-tail.requestCh).GetConnection().GetId()
+
+	// Connection passing the server for the second time should not be restored by itself
+	require.NoError(t, store.Save(&networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "second-pass-id",
+			Path: &networkservice.Path{
+				Index: 3,
+				PathSegments: []*networkservice.PathSegment{
+					{Name: clientName}, {Name: serverName}, {Name: "forwarder"}, {Name: serverName, Id: "second-pass-id"},
+				},
+			},
+		},
+	}))
+
+	// Restart the server
+	tail = newTailServer()
+	server := newServer(ctx, store, &monitorServer, tail)
+	persist.Restore(ctx, store, server, waitFor)
+
+	require.Len(t, tail.requestCh, 1)
+	request := <-tail.requestCh
+	require.Equal(t, serverConnID, request.GetConnection().GetId())
+	require.Equal(t, conn.GetPath().GetPathSegments()[0].GetId(), request.GetConnection().GetPath().GetPathSegments()[0].GetId())
+
+	requests, err := store.Load(ctx)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	require.Equal(t, serverConnID, requests[0].GetConnection().GetId())
+
+	// Monitor has the connection
+	monitorCtx, cancelMonitor := context.WithCancel(ctx)
+	defer cancelMonitor()
+
+	monitorClient, err := adapters.NewMonitorServerToClient(monitorServer).MonitorConnections(monitorCtx, new(networkservice.MonitorScopeSelector))
+	require.NoError(t, err)
+
+	event, err := monitorClient.Recv()
+	require.NoError(t, err)
+	require.Equal(t, networkservice.ConnectionEventType_INITIAL_STATE_TRANSFER, event.GetType())
+	require.Contains(t, event.GetConnections(), serverConnID)
+
+	// Timeout is armed
+	require.Eventually(t, func() bool {
+		clockMock.Add(tokenTimeout)
+		return len(tail.closeCh) == 1
+	}, waitFor, tick)
+
+	requests, err = store.Load(ctx)
+	require.NoError(t, err)
+	require.Empty(t, requests)
+}
+
+func TestRestore_Failed(t *testing.T) {
+	ctx := logger.WithLog(context.Background())
+
+	store, err := persist.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, store.Save(&networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "id",
+			Path: &networkservice.Path{
+				Index: 1,
+				PathSegments: []*networkservice.PathSegment{
+					{Name: clientName}, {Name: serverName, Id: "id"},
+				},
+			},
+		},
+	}))
+
+	// Server chain without the tokens fails the Request for the connection with no expiration
+	server := chain.NewNetworkServiceServer(
+		updatepath.NewServer(serverName),
+		serialize.NewServer(),
+		timeout.NewServer(ctx),
+		persist.NewServer(store),
+	)
+	persist.Restore(ctx, store, server, waitFor)
+
+	requests, err := store.Load(ctx)
+	require.NoError(t, err)
+	require.Empty(t, requests)
+}
+
+func TestRestore_Retry(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(logger.WithLog(context.Background()))
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	store, err := persist.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	var monitorServer networkservice.MonitorConnectionServer
+	client := chain.NewNetworkServiceClient(
+		updatepath.NewClient(clientName),
+		updatetoken.NewClient(tokenGenerator(ctx)),
+		adapters.NewServerToClient(newServer(ctx, store, &monitorServer, newTailServer())),
+	)
+
+	_, err = client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			NetworkService: "ns",
+		},
+	})
+	require.NoError(t, err)
+
+	// Restart the server, the first restore Requests fail
+	tail := newTailServer()
+	server := chain.NewNetworkServiceServer(
+		&failServer{failCount: 2},
+		newServer(ctx, store, &monitorServer, tail),
+	)
+
+	restoreCh := make(chan struct{})
+	go func() {
+		defer close(restoreCh)
+		persist.Restore(ctx, store, server, waitFor)
+	}()
+
+	require.Eventually(t, func() bool {
+		clockMock.Add(time.Second)
+		select {
+		case <-restoreCh:
+			return true
+		default:
+			return false
+		}
+	}, waitFor, tick)
+
+	require.Len(t, tail.requestCh, 1)
+
+	requests, err := store.Load(ctx)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+}
+
+type failServer struct {
+	failCount int
+}
+
+func (s *failServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if s.failCount > 0 {
+		s.failCount--
+		return nil, errors.New("failed")
+	}
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *failServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persist
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+)
+
+const (
+	restoreInitialBackoff = 100 * time.Millisecond
+	restoreMaxBackoff     = 5 * time.Second
+)
+
+type restoreInfo struct {
+	stored  *networkservice.NetworkServiceRequest
+	request *networkservice.NetworkServiceRequest
+	expires time.Time
+}
+
+// Restore - re-requests all the requests from the store with the server as if they were sent by the previous path
+//           segment, so the server chain (monitor, timeout, connect, ...) rebuilds its state for the existing
+//           connections. Failed restore Requests are retried with the backoff until the connection expires, then the
+//           connection is deleted from the store. Connections requested or closed by someone else in the meantime
+//           are not retried anymore.
+//           ctx - context for the restore Requests, clock.Clock from ctx is used for the timeouts and the backoff
+//           store - connection state store, should be the same as passed to the NewServer in the server chain
+//           server - server chain to restore
+//           requestTimeout - timeout for each of the restore Requests
+func Restore(ctx context.Context, store Store, server networkservice.NetworkServiceServer, requestTimeout time.Duration) {
+	logEntry := logger.Log(ctx).WithField("persist", "Restore")
+	clk := clock.FromContext(ctx)
+
+	requests, err := store.Load(ctx)
+	if err != nil {
+		logEntry.Errorf("failed to load connections: %+v", err)
+		return
+	}
+
+	deleteRequest := func(connID string) {
+		if deleteErr := store.Delete(connID); deleteErr != nil {
+			logEntry.Errorf("failed to delete connection: %v %+v", connID, deleteErr)
+		}
+	}
+
+	// Connections passing the server not for the first time are restored with the restore of their first pass, so
+	// they should be deleted before any restore Request to not delete the newly saved ones
+	restoreInfos := make(map[string]*restoreInfo)
+	for _, request := range requests {
+		connID := request.GetConnection().GetId()
+		info := &restoreInfo{
+			stored:  request.Clone(),
+			request: request,
+		}
+		if !prepareRestoreRequest(request) {
+			deleteRequest(connID)
+			continue
+		}
+		// Connection expires together with the previous path segment token, the same way it is done by the timeout
+		if info.expires, err = ptypes.Timestamp(request.GetConnection().GetCurrentPathSegment().GetExpires()); err != nil {
+			info.expires = time.Time{}
+		}
+		restoreInfos[connID] = info
+	}
+
+	delay := restoreInitialBackoff
+	for {
+		for connID, info := range restoreInfos {
+			err = restoreRequest(ctx, server, info.request.Clone(), requestTimeout)
+			if err == nil {
+				logEntry.Infof("connection restored: %v", connID)
+				delete(restoreInfos, connID)
+				continue
+			}
+			if !clk.Now().Before(info.expires) {
+				logEntry.Warnf("failed to restore connection: %v %+v", connID, err)
+				deleteRequest(connID)
+				delete(restoreInfos, connID)
+				continue
+			}
+			logEntry.Debugf("failed to restore connection, retrying: %v %+v", connID, err)
+		}
+		if len(restoreInfos) == 0 {
+			return
+		}
+
+		timer := clk.Timer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+		if delay *= 2; delay > restoreMaxBackoff {
+			delay = restoreMaxBackoff
+		}
+
+		forgetChanged(ctx, store, restoreInfos)
+	}
+}
+
+// forgetChanged removes the connections updated or deleted in the store since the restore has started, they are
+// already requested or closed by someone else
+func forgetChanged(ctx context.Context, store Store, restoreInfos map[string]*restoreInfo) {
+	requests, err := store.Load(ctx)
+	if err != nil {
+		logger.Log(ctx).WithField("persist", "Restore").Errorf("failed to load connections: %+v", err)
+		return
+	}
+
+	stored := make(map[string]*networkservice.NetworkServiceRequest, len(requests))
+	for _, request := range requests {
+		stored[request.GetConnection().GetId()] = request
+	}
+	for connID, info := range restoreInfos {
+		if request, ok := stored[connID]; !ok || !proto.Equal(request, info.stored) {
+			delete(restoreInfos, connID)
+		}
+	}
+}
+
+func restoreRequest(ctx context.Context, server networkservice.NetworkServiceServer, request *networkservice.NetworkServiceRequest, requestTimeout time.Duration) error {
+	requestCtx, cancelRequest := clock.FromContext(ctx).WithTimeout(ctx, requestTimeout)
+	defer cancelRequest()
+
+	_, err := server.Request(requestCtx, request)
+	return err
+}
+
+// prepareRestoreRequest moves the request back to the previous path segment. Returns false if the request is not
+// the first pass of the connection through the server.
+func prepareRestoreRequest(request *networkservice.NetworkServiceRequest) bool {
+	path := request.GetConnection().GetPath()
+	index := int(path.GetIndex())
+	segments := path.GetPathSegments()
+	if index < 1 || index >= len(segments) {
+		return false
+	}
+	for _, segment := range segments[:index] {
+		if segment.GetName() == segments[index].GetName() {
+			return false
+		}
+	}
+
+	path.Index--
+	request.Connection.Id = segments[index-1].GetId()
+
+	return true
+}
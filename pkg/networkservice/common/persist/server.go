@@ -0,0 +1,65 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package persist provides a NetworkServiceServer chain element that keeps the connection state in the Store,
+// so the chain state can be restored with Restore after the restart
+package persist
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+)
+
+type persistServer struct {
+	store Store
+}
+
+// NewServer - creates a new NetworkServiceServer chain element saving the requests for the established connections
+//             into the store and deleting them on Close. Store errors are logged and don't fail the Request, Close.
+//             store - connection state store
+func NewServer(store Store) networkservice.NetworkServiceServer {
+	return &persistServer{
+		store: store,
+	}
+}
+
+func (s *persistServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	request = request.Clone()
+	request.Connection = conn.Clone()
+	if err := s.store.Save(request); err != nil {
+		logger.Log(ctx).WithField("persistServer", "Request").Errorf("failed to save connection: %v %+v", conn.GetId(), err)
+	}
+
+	return conn, nil
+}
+
+func (s *persistServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	if err := s.store.Delete(conn.GetId()); err != nil {
+		logger.Log(ctx).WithField("persistServer", "Close").Errorf("failed to delete connection: %v %+v", conn.GetId(), err)
+	}
+	return next.Server(ctx).Close(ctx, conn)
+}
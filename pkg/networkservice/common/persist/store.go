@@ -0,0 +1,82 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persist
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/filestore"
+)
+
+// Store is a storage for the connection state
+type Store interface {
+	// Save stores the request with the given connection, replacing the previously stored one with the same ID
+	Save(request *networkservice.NetworkServiceRequest) error
+	// Delete removes the request for the connection ID, does nothing if there is no such request
+	Delete(connID string) error
+	// Load returns all the stored requests, the requests failed to decode are logged and removed from the store
+	Load(ctx context.Context) ([]*networkservice.NetworkServiceRequest, error)
+}
+
+type fileStore struct {
+	files *filestore.Store
+}
+
+// NewFileStore - creates a new Store keeping the requests as separate JSON files in dir
+//             dir - directory to store files in, is created if it doesn't exist
+func NewFileStore(dir string) (Store, error) {
+	files, err := filestore.New(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &fileStore{
+		files: files,
+	}, nil
+}
+
+func (s *fileStore) Save(request *networkservice.NetworkServiceRequest) error {
+	data, err := protojson.Marshal(request)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal request: %s", request.GetConnection().GetId())
+	}
+	return s.files.Save(request.GetConnection().GetId(), data)
+}
+
+func (s *fileStore) Delete(connID string) error {
+	return s.files.Delete(connID)
+}
+
+func (s *fileStore) Load(ctx context.Context) ([]*networkservice.NetworkServiceRequest, error) {
+	var requests []*networkservice.NetworkServiceRequest
+	err := s.files.Load(ctx, func(data []byte) error {
+		request := new(networkservice.NetworkServiceRequest)
+		if err := protojson.Unmarshal(data, request); err != nil {
+			return err
+		}
+		requests = append(requests, request)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reaper
+
+import (
+	"context"
+)
+
+type givenUpKey struct{}
+
+// WithGivenUp returns a new context marking the Close called with it as the last one: the retries of the connection
+// Close have been given up, so the subsequent chain elements should forget the connection without trying to close it.
+func WithGivenUp(parent context.Context) context.Context {
+	if parent == nil {
+		panic("cannot create context from nil parent")
+	}
+	return context.WithValue(parent, givenUpKey{}, true)
+}
+
+// IsGivenUp returns true if the Close retries have been given up for the call with the context
+func IsGivenUp(ctx context.Context) bool {
+	givenUp, _ := ctx.Value(givenUpKey{}).(bool)
+	return givenUp
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reaper
+
+import (
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+type reaperOptions struct {
+	initialBackoff  time.Duration
+	maxBackoff      time.Duration
+	authorizeServer networkservice.NetworkServiceServer
+}
+
+// Option is an option pattern for NewServer
+type Option func(o *reaperOptions)
+
+// WithBackoff sets the delay between the failed Close attempts. The delay starts with initialBackoff and doubles after
+// each failed attempt up to maxBackoff. Default is 1s, 1m.
+func WithBackoff(initialBackoff, maxBackoff time.Duration) Option {
+	return func(o *reaperOptions) {
+		o.initialBackoff = initialBackoff
+		o.maxBackoff = maxBackoff
+	}
+}
+
+// WithAuthorizeServer sets authorization server chain element for the ListOrphans callers, a connection is listed only if
+// the caller is authorized to Close it. It should be the same one the NetworkServiceServer chain uses. Default is
+// authorize.NewServer().
+func WithAuthorizeServer(authorizeServer networkservice.NetworkServiceServer) Option {
+	if authorizeServer == nil {
+		panic("Authorize server cannot be nil")
+	}
+	return func(o *reaperOptions) {
+		o.authorizeServer = authorizeServer
+	}
+}
@@ -0,0 +1,375 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.8.0
+// source: reaper.proto
+
+package reaper
+
+import (
+	context "context"
+	proto "github.com/golang/protobuf/proto"
+	empty "github.com/golang/protobuf/ptypes/empty"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type Orphan struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the connection failed to Close
+	ConnectionId string `protobuf:"bytes,1,opt,name=connection_id,json=connectionId,proto3" json:"connection_id,omitempty"`
+	// Name of the connection endpoint
+	NetworkServiceEndpointName string `protobuf:"bytes,2,opt,name=network_service_endpoint_name,json=networkServiceEndpointName,proto3" json:"network_service_endpoint_name,omitempty"`
+	// Name of the connection network service
+	NetworkService string `protobuf:"bytes,3,opt,name=network_service,json=networkService,proto3" json:"network_service,omitempty"`
+	// Number of the failed Close attempts
+	Attempts uint32 `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// Last Close error
+	Error string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *Orphan) Reset() {
+	*x = Orphan{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_reaper_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Orphan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Orphan) ProtoMessage() {}
+
+func (x *Orphan) ProtoReflect() protoreflect.Message {
+	mi := &file_reaper_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Orphan.ProtoReflect.Descriptor instead.
+func (*Orphan) Descriptor() ([]byte, []int) {
+	return file_reaper_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Orphan) GetConnectionId() string {
+	if x != nil {
+		return x.ConnectionId
+	}
+	return ""
+}
+
+func (x *Orphan) GetNetworkServiceEndpointName() string {
+	if x != nil {
+		return x.NetworkServiceEndpointName
+	}
+	return ""
+}
+
+func (x *Orphan) GetNetworkService() string {
+	if x != nil {
+		return x.NetworkService
+	}
+	return ""
+}
+
+func (x *Orphan) GetAttempts() uint32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *Orphan) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type OrphanList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Orphans []*Orphan `protobuf:"bytes,1,rep,name=orphans,proto3" json:"orphans,omitempty"`
+}
+
+func (x *OrphanList) Reset() {
+	*x = OrphanList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_reaper_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OrphanList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrphanList) ProtoMessage() {}
+
+func (x *OrphanList) ProtoReflect() protoreflect.Message {
+	mi := &file_reaper_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrphanList.ProtoReflect.Descriptor instead.
+func (*OrphanList) Descriptor() ([]byte, []int) {
+	return file_reaper_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OrphanList) GetOrphans() []*Orphan {
+	if x != nil {
+		return x.Orphans
+	}
+	return nil
+}
+
+var File_reaper_proto protoreflect.FileDescriptor
+
+var file_reaper_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x72, 0x65, 0x61, 0x70, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
+	0x72, 0x65, 0x61, 0x70, 0x65, 0x72, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0xcb, 0x01, 0x0a, 0x06, 0x4f, 0x72, 0x70, 0x68, 0x61, 0x6e, 0x12, 0x23,
+	0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x49, 0x64, 0x12, 0x41, 0x0a, 0x1d, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x1a, 0x6e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72,
+	0x6b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x08, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x22, 0x36, 0x0a, 0x0a, 0x4f, 0x72, 0x70, 0x68, 0x61, 0x6e, 0x4c, 0x69, 0x73, 0x74, 0x12,
+	0x28, 0x0a, 0x07, 0x6f, 0x72, 0x70, 0x68, 0x61, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0e, 0x2e, 0x72, 0x65, 0x61, 0x70, 0x65, 0x72, 0x2e, 0x4f, 0x72, 0x70, 0x68, 0x61, 0x6e,
+	0x52, 0x07, 0x6f, 0x72, 0x70, 0x68, 0x61, 0x6e, 0x73, 0x32, 0x4a, 0x0a, 0x0d, 0x52, 0x65, 0x61,
+	0x70, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x39, 0x0a, 0x0b, 0x4c, 0x69,
+	0x73, 0x74, 0x4f, 0x72, 0x70, 0x68, 0x61, 0x6e, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x1a, 0x12, 0x2e, 0x72, 0x65, 0x61, 0x70, 0x65, 0x72, 0x2e, 0x4f, 0x72, 0x70, 0x68, 0x61,
+	0x6e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x44, 0x5a, 0x42, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x6d, 0x65, 0x73, 0x68, 0x2f, 0x73, 0x64, 0x6b, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x63, 0x6f,
+	0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x72, 0x65, 0x61, 0x70, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_reaper_proto_rawDescOnce sync.Once
+	file_reaper_proto_rawDescData = file_reaper_proto_rawDesc
+)
+
+func file_reaper_proto_rawDescGZIP() []byte {
+	file_reaper_proto_rawDescOnce.Do(func() {
+		file_reaper_proto_rawDescData = protoimpl.X.CompressGZIP(file_reaper_proto_rawDescData)
+	})
+	return file_reaper_proto_rawDescData
+}
+
+var file_reaper_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_reaper_proto_goTypes = []interface{}{
+	(*Orphan)(nil),      // 0: reaper.Orphan
+	(*OrphanList)(nil),  // 1: reaper.OrphanList
+	(*empty.Empty)(nil), // 2: google.protobuf.Empty
+}
+var file_reaper_proto_depIdxs = []int32{
+	0, // 0: reaper.OrphanList.orphans:type_name -> reaper.Orphan
+	2, // 1: reaper.ReaperService.ListOrphans:input_type -> google.protobuf.Empty
+	1, // 2: reaper.ReaperService.ListOrphans:output_type -> reaper.OrphanList
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_reaper_proto_init() }
+func file_reaper_proto_init() {
+	if File_reaper_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_reaper_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Orphan); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_reaper_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OrphanList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_reaper_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_reaper_proto_goTypes,
+		DependencyIndexes: file_reaper_proto_depIdxs,
+		MessageInfos:      file_reaper_proto_msgTypes,
+	}.Build()
+	File_reaper_proto = out.File
+	file_reaper_proto_rawDesc = nil
+	file_reaper_proto_goTypes = nil
+	file_reaper_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// ReaperServiceClient is the client API for ReaperService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ReaperServiceClient interface {
+	// ListOrphans returns the connections the reaper keeps retrying to Close
+	ListOrphans(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*OrphanList, error)
+}
+
+type reaperServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReaperServiceClient(cc grpc.ClientConnInterface) ReaperServiceClient {
+	return &reaperServiceClient{cc}
+}
+
+func (c *reaperServiceClient) ListOrphans(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*OrphanList, error) {
+	out := new(OrphanList)
+	err := c.cc.Invoke(ctx, "/reaper.ReaperService/ListOrphans", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReaperServiceServer is the server API for ReaperService service.
+type ReaperServiceServer interface {
+	// ListOrphans returns the connections the reaper keeps retrying to Close
+	ListOrphans(context.Context, *empty.Empty) (*OrphanList, error)
+}
+
+// UnimplementedReaperServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedReaperServiceServer struct {
+}
+
+func (*UnimplementedReaperServiceServer) ListOrphans(context.Context, *empty.Empty) (*OrphanList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrphans not implemented")
+}
+
+func RegisterReaperServiceServer(s *grpc.Server, srv ReaperServiceServer) {
+	s.RegisterService(&_ReaperService_serviceDesc, srv)
+}
+
+func _ReaperService_ListOrphans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReaperServiceServer).ListOrphans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/reaper.ReaperService/ListOrphans",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReaperServiceServer).ListOrphans(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ReaperService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "reaper.ReaperService",
+	HandlerType: (*ReaperServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListOrphans",
+			Handler:    _ReaperService_ListOrphans_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "reaper.proto",
+}
@@ -0,0 +1,269 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package reaper provides a NetworkServiceServer chain element retrying the failed Close of the subsequent chain until
+// it succeeds or the connection expires, so the remote side doesn't keep the resources of the closed connection. The
+// connections being retried are listed by the ReaperService debug gRPC service.
+//
+// Reaper is the only owner of the failed connection lifetime: when it gives up on the expiration, it calls the
+// subsequent chain Close one more time with the WithGivenUp context, so the subsequent chain elements can forget the
+// connection without trying to close it (see connect.WithCloseRetry).
+package reaper
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/authorize"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/serialize"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+	"github.com/networkservicemesh/sdk/pkg/tools/scheduler"
+)
+
+type orphan struct {
+	conn       *networkservice.Connection
+	server     networkservice.NetworkServiceServer
+	executor   serialize.Executor
+	expireTime time.Time
+	attempts   uint32
+	err        error
+	backoff    time.Duration
+	task       *scheduler.Task
+}
+
+type reaperServer struct {
+	ctx             context.Context
+	clock           clock.Clock
+	scheduler       *scheduler.Scheduler
+	options         *reaperOptions
+	authorizeServer networkservice.NetworkServiceServer
+	orphans         map[string]*orphan
+	mu              sync.Mutex
+}
+
+// NewServer - creates a new NetworkServiceServer chain element retrying the failed Close of the subsequent chain with
+//             the backoff until it succeeds or the current path segment expires. Each attempt is limited with the
+//             current backoff or with the time left until the expiration, whichever is less. Request for the
+//             connection stops the retries.
+//             - ctx - context for the retried Close, clock.Clock and scheduler.Scheduler from ctx are used for the
+//                     retry timers
+//             - reaperServerPtr - *ReaperServiceServer. Same as the monitor.NewServer, it is a double pointer to
+//                     get back the debug server listing the connections being retried. Only the connections the
+//                     caller is authorized to Close are listed.
+//             - options - WithBackoff, WithAuthorizeServer
+func NewServer(ctx context.Context, reaperServerPtr *ReaperServiceServer, options ...Option) networkservice.NetworkServiceServer {
+	o := &reaperOptions{
+		initialBackoff:  time.Second,
+		maxBackoff:      time.Minute,
+		authorizeServer: authorize.NewServer(),
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+
+	rv := &reaperServer{
+		ctx:             ctx,
+		clock:           clock.FromContext(ctx),
+		scheduler:       scheduler.FromContext(ctx),
+		options:         o,
+		authorizeServer: next.NewNetworkServiceServer(o.authorizeServer),
+		orphans:         make(map[string]*orphan),
+	}
+	*reaperServerPtr = rv
+	return rv
+}
+
+func (s *reaperServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	s.forget(request.GetConnection().GetId())
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *reaperServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.forget(conn.GetId())
+
+	rv, err := next.Server(ctx).Close(ctx, conn)
+	if err != nil {
+		s.storeOrphan(ctx, conn, err)
+	}
+	return rv, err
+}
+
+func (s *reaperServer) ListOrphans(ctx context.Context, _ *empty.Empty) (*OrphanList, error) {
+	s.mu.Lock()
+	var orphans []*orphan
+	for _, o := range s.orphans {
+		orphans = append(orphans, &orphan{
+			conn:     o.conn.Clone(),
+			attempts: o.attempts,
+			err:      o.err,
+		})
+	}
+	s.mu.Unlock()
+
+	rv := new(OrphanList)
+	for _, o := range orphans {
+		if _, err := s.authorizeServer.Close(ctx, o.conn); err != nil {
+			continue
+		}
+		rv.Orphans = append(rv.Orphans, &Orphan{
+			ConnectionId:               o.conn.GetId(),
+			NetworkServiceEndpointName: o.conn.GetNetworkServiceEndpointName(),
+			NetworkService:             o.conn.GetNetworkService(),
+			Attempts:                   o.attempts,
+			Error:                      o.err.Error(),
+		})
+	}
+	sort.Slice(rv.Orphans, func(i, j int) bool {
+		return rv.Orphans[i].GetConnectionId() < rv.Orphans[j].GetConnectionId()
+	})
+
+	return rv, nil
+}
+
+func (s *reaperServer) forget(connID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if o, ok := s.orphans[connID]; ok {
+		o.task.Stop()
+		delete(s.orphans, connID)
+	}
+}
+
+func (s *reaperServer) storeOrphan(ctx context.Context, conn *networkservice.Connection, err error) {
+	logEntry := logger.Log(ctx).WithField("reaperServer", "Close")
+
+	executor := serialize.GetExecutor(ctx)
+	if executor == nil {
+		logEntry.Warnf("no executor provided, failed Close is not retried: %v", conn.GetId())
+		s.giveUp(next.Server(ctx), conn)
+		return
+	}
+
+	expireTime, tsErr := ptypes.Timestamp(conn.GetCurrentPathSegment().GetExpires())
+	if tsErr != nil {
+		logEntry.Warnf("no expiration time, failed Close is not retried: %v", conn.GetId())
+		s.giveUp(next.Server(ctx), conn)
+		return
+	}
+
+	o := &orphan{
+		conn:       conn.Clone(),
+		server:     next.Server(ctx),
+		executor:   executor,
+		expireTime: expireTime,
+		attempts:   1,
+		err:        err,
+		backoff:    s.options.initialBackoff,
+	}
+
+	s.mu.Lock()
+	scheduled := s.schedule(o)
+	if scheduled {
+		s.orphans[conn.GetId()] = o
+	}
+	s.mu.Unlock()
+
+	if !scheduled {
+		logEntry.Warnf("connection expires before the next Close attempt, giving up: %v", conn.GetId())
+		s.giveUp(o.server, o.conn)
+	}
+}
+
+// schedule schedules the next Close attempt, returns false if the connection expires before it
+func (s *reaperServer) schedule(o *orphan) bool {
+	if nextAttempt := s.clock.Now().Add(o.backoff); !nextAttempt.Before(o.expireTime) {
+		return false
+	}
+	o.task = s.scheduler.AfterFunc(o.backoff, func() {
+		s.retry(o)
+	})
+	return true
+}
+
+func (s *reaperServer) retry(o *orphan) {
+	connID := o.conn.GetId()
+	logEntry := logger.Log(s.ctx).WithField("reaperServer", "retry")
+
+	<-o.executor.AsyncExec(func() {
+		if !s.isCurrent(o) {
+			return
+		}
+
+		timeout := o.backoff
+		if untilExpire := s.clock.Until(o.expireTime); untilExpire < timeout {
+			timeout = untilExpire
+		}
+		closeCtx, cancelClose := s.clock.WithTimeout(s.ctx, timeout)
+		defer cancelClose()
+
+		_, err := o.server.Close(closeCtx, o.conn.Clone())
+
+		s.mu.Lock()
+		if s.orphans[connID] != o {
+			s.mu.Unlock()
+			return
+		}
+		if err == nil {
+			logEntry.Infof("connection closed after %v failed attempts: %v", o.attempts, connID)
+			delete(s.orphans, connID)
+			s.mu.Unlock()
+			return
+		}
+
+		o.attempts++
+		o.err = err
+		if o.backoff *= 2; o.backoff > s.options.maxBackoff {
+			o.backoff = s.options.maxBackoff
+		}
+		scheduled := s.schedule(o)
+		if !scheduled {
+			delete(s.orphans, connID)
+		}
+		s.mu.Unlock()
+
+		if !scheduled {
+			logEntry.Warnf("connection expires before the next Close attempt, giving up after %v failed attempts: %v %+v",
+				o.attempts, connID, err)
+			s.giveUp(o.server, o.conn)
+		}
+	})
+}
+
+// giveUp closes the subsequent chain with the WithGivenUp context, so it can forget the connection without trying to
+// close it. The Close is limited with the initial backoff. It should be called under the connection executor.
+func (s *reaperServer) giveUp(server networkservice.NetworkServiceServer, conn *networkservice.Connection) {
+	giveUpCtx, cancelGiveUp := s.clock.WithTimeout(WithGivenUp(s.ctx), s.options.initialBackoff)
+	defer cancelGiveUp()
+
+	_, _ = server.Close(giveUpCtx, conn.Clone())
+}
+
+func (s *reaperServer) isCurrent(o *orphan) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.orphans[o.conn.GetId()] == o
+}
@@ -0,0 +1,323 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reaper_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/reaper"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/serialize"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatepath"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatetoken"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+	"github.com/networkservicemesh/sdk/pkg/tools/token"
+)
+
+const (
+	tokenTimeout = time.Hour
+	waitFor      = time.Second
+	tick         = 10 * time.Millisecond
+)
+
+type failCloseServer struct {
+	failures, closes, givenUp int32
+}
+
+func (s *failCloseServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *failCloseServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	if reaper.IsGivenUp(ctx) {
+		atomic.AddInt32(&s.givenUp, 1)
+		return next.Server(ctx).Close(ctx, conn)
+	}
+	if atomic.AddInt32(&s.closes, 1) <= atomic.LoadInt32(&s.failures) {
+		return nil, errors.New("close failed")
+	}
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func tokenGenerator(ctx context.Context) token.GeneratorFunc {
+	return func(_ credentials.AuthInfo) (string, time.Time, error) {
+		return "token", clock.FromContext(ctx).Now().Add(tokenTimeout), nil
+	}
+}
+
+func testClient(ctx context.Context, reaperServerPtr *reaper.ReaperServiceServer, server *failCloseServer, options ...reaper.Option) networkservice.NetworkServiceClient {
+	return chain.NewNetworkServiceClient(
+		updatepath.NewClient("client"),
+		updatetoken.NewClient(tokenGenerator(ctx)),
+		adapters.NewServerToClient(
+			chain.NewNetworkServiceServer(
+				updatepath.NewServer("server"),
+				updatetoken.NewServer(tokenGenerator(ctx)),
+				serialize.NewServer(),
+				reaper.NewServer(ctx, reaperServerPtr, options...),
+				server,
+			),
+		),
+	)
+}
+
+func listOrphans(t *testing.T, reaperServer reaper.ReaperServiceServer) []*reaper.Orphan {
+	orphans, err := reaperServer.ListOrphans(context.Background(), new(empty.Empty))
+	require.NoError(t, err)
+	return orphans.GetOrphans()
+}
+
+func TestReaperServer_RetryClose(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(logger.WithLog(context.Background()))
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	var reaperServer reaper.ReaperServiceServer
+	server := &failCloseServer{failures: 2}
+	client := testClient(ctx, &reaperServer, server, reaper.WithBackoff(time.Second, time.Minute))
+
+	conn, err := client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:                         "conn-1",
+			NetworkService:             "ns-1",
+			NetworkServiceEndpointName: "nse-1",
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = client.Close(ctx, conn.Clone())
+	require.Error(t, err)
+
+	orphans := listOrphans(t, reaperServer)
+	require.Len(t, orphans, 1)
+	require.Equal(t, conn.GetPath().GetPathSegments()[1].GetId(), orphans[0].GetConnectionId())
+	require.Equal(t, "ns-1", orphans[0].GetNetworkService())
+	require.Equal(t, "nse-1", orphans[0].GetNetworkServiceEndpointName())
+	require.Equal(t, uint32(1), orphans[0].GetAttempts())
+	require.Contains(t, orphans[0].GetError(), "close failed")
+
+	// The second attempt fails, backoff is doubled
+	clockMock.Add(time.Second)
+	require.Eventually(t, func() bool {
+		orphans = listOrphans(t, reaperServer)
+		return len(orphans) == 1 && orphans[0].GetAttempts() == 2
+	}, waitFor, tick)
+	require.Equal(t, int32(2), atomic.LoadInt32(&server.closes))
+
+	clockMock.Add(time.Second)
+	require.Never(t, func() bool {
+		return atomic.LoadInt32(&server.closes) > 2
+	}, 10*tick, tick)
+
+	// The third attempt succeeds
+	clockMock.Add(time.Second)
+	require.Eventually(t, func() bool {
+		return len(listOrphans(t, reaperServer)) == 0
+	}, waitFor, tick)
+	require.Equal(t, int32(3), atomic.LoadInt32(&server.closes))
+}
+
+func TestReaperServer_Expire(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(logger.WithLog(context.Background()))
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	var reaperServer reaper.ReaperServiceServer
+	server := &failCloseServer{failures: 100}
+	client := testClient(ctx, &reaperServer, server, reaper.WithBackoff(20*time.Minute, 20*time.Minute))
+
+	conn, err := client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "conn-1"},
+	})
+	require.NoError(t, err)
+
+	_, err = client.Close(ctx, conn.Clone())
+	require.Error(t, err)
+
+	// Attempts at 20m and 40m, the next one would be after the expiration
+	for attempts := uint32(2); attempts <= 3; attempts++ {
+		clockMock.Add(20 * time.Minute)
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt32(&server.closes) == int32(attempts)
+		}, waitFor, tick)
+	}
+	require.Eventually(t, func() bool {
+		return len(listOrphans(t, reaperServer)) == 0
+	}, waitFor, tick)
+
+	// Subsequent chain is closed with the given up context to forget the connection
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&server.givenUp) == 1
+	}, waitFor, tick)
+
+	clockMock.Add(tokenTimeout)
+	require.Never(t, func() bool {
+		return atomic.LoadInt32(&server.closes) > 3
+	}, 10*tick, tick)
+}
+
+type blockCloseServer struct {
+	closes int32
+}
+
+func (s *blockCloseServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *blockCloseServer) Close(ctx context.Context, _ *networkservice.Connection) (*empty.Empty, error) {
+	if atomic.AddInt32(&s.closes, 1) == 1 {
+		return nil, errors.New("close failed")
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestReaperServer_AttemptTimeout(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(logger.WithLog(context.Background()))
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	var reaperServer reaper.ReaperServiceServer
+	server := new(blockCloseServer)
+	client := chain.NewNetworkServiceClient(
+		updatepath.NewClient("client"),
+		updatetoken.NewClient(tokenGenerator(ctx)),
+		adapters.NewServerToClient(
+			chain.NewNetworkServiceServer(
+				updatepath.NewServer("server"),
+				updatetoken.NewServer(tokenGenerator(ctx)),
+				serialize.NewServer(),
+				reaper.NewServer(ctx, &reaperServer, reaper.WithBackoff(time.Second, time.Minute)),
+				server,
+			),
+		),
+	)
+
+	conn, err := client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "conn-1"},
+	})
+	require.NoError(t, err)
+
+	_, err = client.Close(ctx, conn.Clone())
+	require.Error(t, err)
+
+	// The second attempt hangs, it is timed out after the backoff
+	clockMock.Add(time.Second)
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&server.closes) == 2
+	}, waitFor, tick)
+
+	clockMock.Add(time.Second)
+	require.Eventually(t, func() bool {
+		orphans := listOrphans(t, reaperServer)
+		return len(orphans) == 1 && orphans[0].GetAttempts() == 2
+	}, waitFor, tick)
+
+	// The third attempt is scheduled with the doubled backoff
+	clockMock.Add(2 * time.Second)
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&server.closes) == 3
+	}, waitFor, tick)
+}
+
+func TestReaperServer_Request(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(logger.WithLog(context.Background()))
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	var reaperServer reaper.ReaperServiceServer
+	server := &failCloseServer{failures: 1}
+	client := testClient(ctx, &reaperServer, server)
+
+	conn, err := client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "conn-1"},
+	})
+	require.NoError(t, err)
+
+	_, err = client.Close(ctx, conn.Clone())
+	require.Error(t, err)
+	require.Len(t, listOrphans(t, reaperServer), 1)
+
+	// Request stops the retries
+	_, err = client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: conn,
+	})
+	require.NoError(t, err)
+	require.Empty(t, listOrphans(t, reaperServer))
+
+	clockMock.Add(tokenTimeout)
+	require.Never(t, func() bool {
+		return atomic.LoadInt32(&server.closes) > 1
+	}, 10*tick, tick)
+}
+
+func TestReaperServer_ListOrphansUnauthorized(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(logger.WithLog(context.Background()))
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	var reaperServer reaper.ReaperServiceServer
+	server := &failCloseServer{failures: 1}
+	client := testClient(ctx, &reaperServer, server, reaper.WithAuthorizeServer(injecterror.NewServer()))
+
+	conn, err := client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "conn-1"},
+	})
+	require.NoError(t, err)
+
+	_, err = client.Close(ctx, conn.Clone())
+	require.Error(t, err)
+
+	// The connection is retried, but the caller is not authorized to see it
+	require.Empty(t, listOrphans(t, reaperServer))
+}
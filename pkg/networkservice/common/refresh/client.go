@@ -22,6 +22,7 @@ package refresh
 
 import (
 	"context"
+	"math/rand"
 	"time"
 
 	"github.com/networkservicemesh/sdk/pkg/tools/logger"
@@ -34,23 +35,48 @@ import (
 	"github.com/edwarnicke/serialize"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
 	"github.com/networkservicemesh/sdk/pkg/tools/extend"
+	"github.com/networkservicemesh/sdk/pkg/tools/scheduler"
+	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
+)
+
+const (
+	refreshOperation = "refresh.Request"
 )
 
 type refreshClient struct {
-	ctx      context.Context
-	timers   map[string]*time.Timer        // key == request.GetConnection.GetId()
-	cancels  map[string]context.CancelFunc // key == request.GetConnection.GetId()
-	executor serialize.Executor
+	ctx       context.Context
+	clock     clock.Clock
+	scheduler *scheduler.Scheduler
+	options   *refreshOptions
+	timers    map[string]*scheduler.Task    // key == request.GetConnection.GetId()
+	cancels   map[string]context.CancelFunc // key == request.GetConnection.GetId()
+	executor  serialize.Executor
 }
 
 // NewClient - creates new NetworkServiceClient chain element for refreshing connections before they timeout at the
 // endpoint
-func NewClient(ctx context.Context) networkservice.NetworkServiceClient {
+//             - ctx    - context for the lifecycle of the *Client* itself.  Cancel when discarding the client.
+//                        Timers are scheduled with the scheduler.Scheduler from ctx and are driven by the
+//                        clock.Clock from ctx.
+//             - options - jitter, retries and refresh failure options
+func NewClient(ctx context.Context, options ...Option) networkservice.NetworkServiceClient {
+	o := &refreshOptions{
+		jitter:     defaultJitter,
+		retryCount: defaultRetryCount,
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+
 	rv := &refreshClient{
-		ctx:     ctx,
-		timers:  make(map[string]*time.Timer),
-		cancels: make(map[string]context.CancelFunc),
+		ctx:       ctx,
+		clock:     clock.FromContext(ctx),
+		scheduler: scheduler.FromContext(ctx),
+		options:   o,
+		timers:    make(map[string]*scheduler.Task),
+		cancels:   make(map[string]context.CancelFunc),
 	}
 	return rv
 }
@@ -69,53 +95,94 @@ func (t *refreshClient) Request(ctx context.Context, request *networkservice.Net
 	refreshRequest := request.Clone()
 	refreshRequest.Connection = rv.Clone()
 
-	// TODO - introduce random noise into duration avoid timer lock
-	duration := time.Until(expireTime) / 3
-	t.executor.AsyncExec(func() {
-		// Create the refresh context
-		var cancel context.CancelFunc
-		refreshCtx, cancel := context.WithCancel(extend.WithValuesFromContext(t.ctx, ctx))
-		if deadline, ok := ctx.Deadline(); ok {
-			refreshCtx, cancel = context.WithDeadline(refreshCtx, deadline.Add(duration))
-		}
+	// Refresh spans are linked to the span of the Request
+	link := tracing.LinkFromContext(ctx)
 
+	// Each refresh Request has the same timeout as this Request has
+	var requestTimeout time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		requestTimeout = t.clock.Until(deadline)
+	}
+
+	duration := t.jitter(t.clock.Until(expireTime) / 3)
+	t.executor.AsyncExec(func() {
 		connID := refreshRequest.GetConnection().GetId()
 
-		// Stop any existing timers
+		// Stop any existing refresh
+		if cancel, ok := t.cancels[connID]; ok {
+			cancel()
+		}
 		if timer, ok := t.timers[connID]; ok {
 			timer.Stop()
 		}
 
-		// Set new timer
-		var timer *time.Timer
-		timer = time.AfterFunc(duration, func() {
-			<-t.executor.AsyncExec(func() {
-				// Check to see if we've been superseded by another timer, if so, do nothing
-				currentTimer, ok := t.timers[connID]
-				if ok && currentTimer != timer {
-					cancel()
-					return
-				}
-			})
-			// Resend request if the refreshCtx is not Done.  Note: refreshCtx should only be done if
-			// t.ctx has been canceled (usually because the clientCtx has been canceled
-			select {
-			case <-refreshCtx.Done():
-			default:
-				if _, err := t.Request(refreshCtx, refreshRequest, opts...); err != nil {
-					// TODO - do we want to retry at 2/3 and 3/3 if we fail here?
-					logger.Log(refreshCtx).Errorf("Error while attempting to refresh connection %s: %+v", connID, err)
+		// Create the refresh context, it is canceled on Close or on the next Request for the same connection
+		refreshCtx, cancel := context.WithCancel(extend.WithValuesFromContext(t.ctx, ctx))
+
+		retryCount := t.options.retryCount
+		var refresh func()
+		refresh = func() {
+			// Resend request if the refreshCtx is not Done.  Note: refreshCtx should only be done if t.ctx has been
+			// canceled (usually because the clientCtx has been canceled) or if we've been superseded by another
+			// Request or Close
+			if refreshCtx.Err() != nil {
+				return
+			}
+
+			err := t.refresh(refreshCtx, link, requestTimeout, refreshRequest, opts...)
+			if err == nil || refreshCtx.Err() != nil {
+				return
+			}
+			logger.Log(refreshCtx).Errorf("Error while attempting to refresh connection %s: %+v", connID, err)
+
+			// Retry evenly across the time left before the connection expires
+			remaining := t.clock.Until(expireTime)
+			if retryCount <= 0 || remaining <= 0 {
+				logger.Log(refreshCtx).Errorf("Failed to refresh connection %s before it expires", connID)
+				if t.options.onFailure != nil {
+					t.options.onFailure(refreshRequest.GetConnection().Clone(), err)
 				}
+				return
 			}
-			// Set timer to nil to be really really sure we don't have a circular reference that precludes garbage collection
-			timer = nil
-		})
-		t.timers[connID] = timer
+			retryDelay := remaining / time.Duration(retryCount+1)
+			retryCount--
+
+			t.executor.AsyncExec(func() {
+				if refreshCtx.Err() == nil {
+					t.timers[connID] = t.scheduler.AfterFunc(retryDelay, refresh)
+				}
+			})
+		}
+
+		t.timers[connID] = t.scheduler.AfterFunc(duration, refresh)
 		t.cancels[connID] = cancel
 	})
 	return rv, nil
 }
 
+// refresh - resends the refresh Request with the requestTimeout if it is set
+func (t *refreshClient) refresh(ctx context.Context, link tracing.Link, requestTimeout time.Duration, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) error {
+	if requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = t.clock.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+	}
+
+	ctx, span := tracing.StartLinkedSpan(ctx, refreshOperation, link)
+	defer span.Finish()
+
+	_, err := t.Request(ctx, request, opts...)
+	return err
+}
+
+// jitter - randomly adds or subtracts up to the jitter fraction of the duration
+func (t *refreshClient) jitter(duration time.Duration) time.Duration {
+	if t.options.jitter <= 0 {
+		return duration
+	}
+	return duration + time.Duration(float64(duration)*t.options.jitter*(2*rand.Float64()-1)) //nolint:gosec
+}
+
 func (t *refreshClient) Close(ctx context.Context, conn *networkservice.Connection, _ ...grpc.CallOption) (*empty.Empty, error) {
 	t.executor.AsyncExec(func() {
 		if cancel, ok := t.cancels[conn.GetId()]; ok {
@@ -26,6 +26,7 @@ import (
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 	"google.golang.org/grpc"
@@ -35,6 +36,8 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/refresh"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
 )
 
 const (
@@ -112,6 +115,120 @@ func TestRefreshClient_StopRefreshAtAnotherRequest(t *testing.T) {
 	require.Never(t, cloneClient.validator(3), neverTimeout, tickTimeout)
 }
 
+func TestRefreshClient_RetryFailedRefresh(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	counter := &failClient{
+		// The first refresh fails
+		shouldFail: func(count int32) bool { return count == 2 },
+	}
+	var failureCount int32
+	client := chain.NewNetworkServiceClient(
+		refresh.NewClient(ctx,
+			refresh.WithJitter(0),
+			refresh.WithOnFailure(func(_ *networkservice.Connection, _ error) {
+				atomic.AddInt32(&failureCount, 1)
+			}),
+		),
+		counter,
+	)
+
+	ctx = logger.WithLog(ctx)
+	conn, err := client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "id",
+		},
+	})
+	require.NoError(t, err)
+
+	// The connection is refreshed with the retry before it expires
+	require.Eventually(t, advance(clockMock, counter.validator(3)), eventuallyTimeout, tickTimeout)
+
+	_, err = client.Close(ctx, conn)
+	require.NoError(t, err)
+	require.Equal(t, int32(0), atomic.LoadInt32(&failureCount))
+}
+
+func TestRefreshClient_OnFailure(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	counter := &failClient{
+		// All refreshes fail
+		shouldFail: func(count int32) bool { return count > 1 },
+	}
+	failureCh := make(chan *networkservice.Connection, 1)
+	client := chain.NewNetworkServiceClient(
+		refresh.NewClient(ctx,
+			refresh.WithRetryCount(2),
+			refresh.WithOnFailure(func(conn *networkservice.Connection, _ error) {
+				failureCh <- conn
+			}),
+		),
+		counter,
+	)
+
+	ctx = logger.WithLog(ctx)
+	conn, err := client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "id",
+		},
+	})
+	require.NoError(t, err)
+
+	var failedConn *networkservice.Connection
+	require.Eventually(t, advance(clockMock, func() bool {
+		select {
+		case failedConn = <-failureCh:
+			return true
+		default:
+			return false
+		}
+	}), neverTimeout, tickTimeout)
+	require.Equal(t, "id", failedConn.GetId())
+
+	// Request + refresh + 2 retries
+	require.Equal(t, int32(4), atomic.LoadInt32(&counter.count))
+
+	_, err = client.Close(ctx, conn)
+	require.NoError(t, err)
+}
+
+// advance returns a condition advancing the clockMock by the tick on each check
+func advance(clockMock *clockmock.Mock, condition func() bool) func() bool {
+	return func() bool {
+		clockMock.Add(tickTimeout)
+		return condition()
+	}
+}
+
+type failClient struct {
+	countClient
+	shouldFail func(count int32) bool
+}
+
+func (c *failClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	if c.shouldFail(atomic.AddInt32(&c.count, 1)) {
+		return nil, errors.New("refresh failed")
+	}
+
+	request = request.Clone()
+	setExpires(ctx, request.GetConnection(), expireTimeout)
+
+	return next.Client(ctx).Request(ctx, request, opts...)
+}
+
 type countClient struct {
 	t     *testing.T
 	count int32
@@ -136,7 +253,7 @@ func (c *countClient) Request(ctx context.Context, request *networkservice.Netwo
 		require.Equal(c.t, endpointName, conn.NetworkServiceEndpointName)
 	}
 
-	setExpires(conn, expireTimeout)
+	setExpires(ctx, conn, expireTimeout)
 
 	return next.Client(ctx).Request(ctx, request, opts...)
 }
@@ -145,8 +262,8 @@ func (c *countClient) Close(ctx context.Context, conn *networkservice.Connection
 	return next.Client(ctx).Close(ctx, conn, opts...)
 }
 
-func setExpires(conn *networkservice.Connection, expireTimeout time.Duration) {
-	expireTime := time.Now().Add(expireTimeout)
+func setExpires(ctx context.Context, conn *networkservice.Connection, expireTimeout time.Duration) {
+	expireTime := clock.FromContext(ctx).Now().Add(expireTimeout)
 	expires := &timestamp.Timestamp{
 		Seconds: expireTime.Unix(),
 		Nanos:   int32(expireTime.Nanosecond()),
@@ -0,0 +1,59 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package refresh
+
+import (
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+const (
+	defaultJitter     = 0.2
+	defaultRetryCount = 2
+)
+
+type refreshOptions struct {
+	jitter     float64
+	retryCount int
+	onFailure  func(conn *networkservice.Connection, err error)
+}
+
+// Option is an option pattern for NewClient
+type Option func(o *refreshOptions)
+
+// WithJitter sets the random fraction of the refresh delay (expires/3) to be added or subtracted, so the connections
+// requested at the same time are not refreshed all at once. Default is 0.2.
+func WithJitter(jitter float64) Option {
+	return func(o *refreshOptions) {
+		o.jitter = jitter
+	}
+}
+
+// WithRetryCount sets the number of retries for the failed refresh. Retries are spread evenly across the time left
+// before the connection expires. Default is 2.
+func WithRetryCount(retryCount int) Option {
+	return func(o *refreshOptions) {
+		o.retryCount = retryCount
+	}
+}
+
+// WithOnFailure sets the function called when the connection could not be refreshed before it expires, err is the
+// last refresh error
+func WithOnFailure(onFailure func(conn *networkservice.Connection, err error)) Option {
+	return func(o *refreshOptions) {
+		o.onFailure = onFailure
+	}
+}
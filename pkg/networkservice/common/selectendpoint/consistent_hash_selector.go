@@ -0,0 +1,99 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+)
+
+type consistentHashSelector struct {
+	labelKey string
+}
+
+// NewConsistentHashSelector returns a Selector providing the session affinity: the same client is always sent to the
+// same NSE while it is a candidate. The client key is the labelKey connection label value, or the first path segment
+// name if labelKey is empty or the label is not set. Candidates are ordered with rendezvous (highest random weight)
+// hashing, so adding or removing a candidate remaps only the clients of that candidate.
+func NewConsistentHashSelector(labelKey string) Selector {
+	return &consistentHashSelector{
+		labelKey: labelKey,
+	}
+}
+
+func (s *consistentHashSelector) Select(_ context.Context, request *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	key := s.clientKey(request.GetConnection())
+
+	endpoints := append([]*registry.NetworkServiceEndpoint{}, candidates.Endpoints...)
+	scores := make([]uint64, len(endpoints))
+	for i, nse := range endpoints {
+		scores[i] = score(key, nse.GetName())
+	}
+	sort.Stable(&byScore{endpoints: endpoints, scores: scores})
+
+	return endpoints
+}
+
+func (s *consistentHashSelector) clientKey(conn *networkservice.Connection) string {
+	if value, ok := conn.GetLabels()[s.labelKey]; s.labelKey != "" && ok {
+		return value
+	}
+	if pathSegments := conn.GetPath().GetPathSegments(); len(pathSegments) > 0 && pathSegments[0].GetName() != "" {
+		return pathSegments[0].GetName()
+	}
+	return conn.GetId()
+}
+
+func score(key, nseName string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(nseName))
+
+	// splitmix64 finalizer to spread the FNV output
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+type byScore struct {
+	endpoints []*registry.NetworkServiceEndpoint
+	scores    []uint64
+}
+
+func (b *byScore) Len() int {
+	return len(b.endpoints)
+}
+
+func (b *byScore) Less(i, j int) bool {
+	return b.scores[i] > b.scores[j]
+}
+
+func (b *byScore) Swap(i, j int) {
+	b.endpoints[i], b.endpoints[j] = b.endpoints[j], b.endpoints[i]
+	b.scores[i], b.scores[j] = b.scores[j], b.scores[i]
+}
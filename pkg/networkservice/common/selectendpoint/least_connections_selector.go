@@ -0,0 +1,111 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+)
+
+type leastConnectionsSelector struct {
+	connections map[string]string // key == Connection.Id, value == NetworkServiceEndpoint.Name
+	counts      map[string]int    // key == NetworkServiceEndpoint.Name
+	mutex       sync.Mutex
+}
+
+// NewLeastConnectionsSelector returns a Selector preferring the candidates with the least number of active
+// connections. The connections are tracked from the Request/Close calls passing through the selectendpoint server.
+func NewLeastConnectionsSelector() Selector {
+	return &leastConnectionsSelector{
+		connections: make(map[string]string),
+		counts:      make(map[string]int),
+	}
+}
+
+func (s *leastConnectionsSelector) Select(_ context.Context, _ *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	endpoints := append([]*registry.NetworkServiceEndpoint{}, candidates.Endpoints...)
+
+	s.mutex.Lock()
+	counts := make([]int, len(endpoints))
+	for i, nse := range endpoints {
+		counts[i] = s.counts[nse.GetName()]
+	}
+	s.mutex.Unlock()
+
+	sort.Stable(&byCount{endpoints: endpoints, counts: counts})
+
+	return endpoints
+}
+
+func (s *leastConnectionsSelector) Connected(conn *networkservice.Connection) {
+	nseName := conn.GetNetworkServiceEndpointName()
+	if nseName == "" {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if oldName, ok := s.connections[conn.GetId()]; ok {
+		if oldName == nseName {
+			return
+		}
+		s.decrement(oldName)
+	}
+	s.connections[conn.GetId()] = nseName
+	s.counts[nseName]++
+}
+
+func (s *leastConnectionsSelector) Disconnected(conn *networkservice.Connection) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if nseName, ok := s.connections[conn.GetId()]; ok {
+		delete(s.connections, conn.GetId())
+		s.decrement(nseName)
+	}
+}
+
+func (s *leastConnectionsSelector) decrement(nseName string) {
+	if s.counts[nseName]--; s.counts[nseName] <= 0 {
+		delete(s.counts, nseName)
+	}
+}
+
+type byCount struct {
+	endpoints []*registry.NetworkServiceEndpoint
+	counts    []int
+}
+
+func (b *byCount) Len() int {
+	return len(b.endpoints)
+}
+
+func (b *byCount) Less(i, j int) bool {
+	return b.counts[i] < b.counts[j]
+}
+
+func (b *byCount) Swap(i, j int) {
+	b.endpoints[i], b.endpoints[j] = b.endpoints[j], b.endpoints[i]
+	b.counts[i], b.counts[j] = b.counts[j], b.counts[i]
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint
+
+import (
+	"github.com/networkservicemesh/sdk/pkg/tools/circuitbreaker"
+)
+
+type selectOptions struct {
+	selector   Selector
+	nsSelector map[string]Selector
+	breakers   *circuitbreaker.Breakers
+}
+
+// Option is an option pattern for NewServer
+type Option func(o *selectOptions)
+
+// WithSelector sets the default Selector for all the network services. Default is round robin.
+func WithSelector(selector Selector) Option {
+	return func(o *selectOptions) {
+		o.selector = selector
+	}
+}
+
+// WithNetworkServiceSelector sets the Selector for the network service with the nsName name
+func WithNetworkServiceSelector(nsName string, selector Selector) Option {
+	return func(o *selectOptions) {
+		o.nsSelector[nsName] = selector
+	}
+}
+
+// WithCircuitBreaker sets the circuit breakers keyed by the endpoint name, e.g. the same ones used by the connect chain
+// element. Candidates with the open circuits are skipped and not passed to the Selector.
+func WithCircuitBreaker(breakers *circuitbreaker.Breakers) Option {
+	return func(o *selectOptions) {
+		o.breakers = breakers
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+)
+
+// Previewer is an optional Selector interface to get the candidates order without changing the Selector state, e.g.
+// without moving the round robin position. Selectors not implementing it are previewed with Select.
+type Previewer interface {
+	// Preview returns the candidate endpoints in the order Select would return them for the request now
+	Preview(ctx context.Context, request *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint
+}
+
+// Preview returns the candidate endpoints in the order the chain element would try them for the request now, without
+// connecting to them and without changing the Selector state
+func (s *selectEndpointServer) Preview(ctx context.Context, request *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	return preview(ctx, s.getSelector(request.GetConnection().GetNetworkService()), request, s.available(candidates))
+}
+
+func preview(ctx context.Context, selector Selector, request *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	if previewer, ok := selector.(Previewer); ok {
+		return previewer.Preview(ctx, request, candidates)
+	}
+	return selector.Select(ctx, request, candidates)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+)
+
+type randomSelector struct {
+	rand *lockedRand
+}
+
+// NewRandomSelector returns a Selector trying the candidates in random order, the order is reproducible for the
+// same seed and the same sequence of the Select calls
+func NewRandomSelector(seed int64) Selector {
+	return &randomSelector{
+		rand: newLockedRand(seed),
+	}
+}
+
+func (s *randomSelector) Select(_ context.Context, _ *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	endpoints := append([]*registry.NetworkServiceEndpoint{}, candidates.Endpoints...)
+	s.rand.shuffle(len(endpoints), func(i, j int) {
+		endpoints[i], endpoints[j] = endpoints[j], endpoints[i]
+	})
+	return endpoints
+}
+
+// Preview - random order cannot be predicted without changing the random source state, so the candidates are returned
+// in the discovered order
+func (s *randomSelector) Preview(_ context.Context, _ *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	return append([]*registry.NetworkServiceEndpoint{}, candidates.Endpoints...)
+}
+
+type lockedRand struct {
+	rand  *rand.Rand
+	mutex sync.Mutex
+}
+
+func newLockedRand(seed int64) *lockedRand {
+	return &lockedRand{
+		rand: rand.New(rand.NewSource(seed)), //nolint:gosec
+	}
+}
+
+func (r *lockedRand) shuffle(n int, swap func(i, j int)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.rand.Shuffle(n, swap)
+}
+
+func (r *lockedRand) float64s(n int) []float64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = r.rand.Float64()
+	}
+	return values
+}
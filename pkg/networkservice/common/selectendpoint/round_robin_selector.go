@@ -0,0 +1,65 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+)
+
+type roundRobinSelector struct {
+	counters sync.Map // key == NetworkService.Name, value == *uint64
+}
+
+// NewRoundRobinSelector returns a Selector round robining among the candidates for each network service
+func NewRoundRobinSelector() Selector {
+	return new(roundRobinSelector)
+}
+
+func (s *roundRobinSelector) Select(_ context.Context, _ *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	endpoints := candidates.Endpoints
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	counter, _ := s.counters.LoadOrStore(candidates.NetworkService.GetName(), new(uint64))
+	return rotate(endpoints, atomic.AddUint64(counter.(*uint64), 1)-1)
+}
+
+func (s *roundRobinSelector) Preview(_ context.Context, _ *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	endpoints := candidates.Endpoints
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	var position uint64
+	if counter, ok := s.counters.Load(candidates.NetworkService.GetName()); ok {
+		position = atomic.LoadUint64(counter.(*uint64))
+	}
+	return rotate(endpoints, position)
+}
+
+func rotate(endpoints []*registry.NetworkServiceEndpoint, position uint64) []*registry.NetworkServiceEndpoint {
+	idx := int(position % uint64(len(endpoints)))
+	return append(append([]*registry.NetworkServiceEndpoint{}, endpoints[idx:]...), endpoints[:idx]...)
+}
@@ -1,5 +1,7 @@
 // Copyright (c) 2019-2020 VMware, Inc.
 //
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
 // SPDX-License-Identifier: Apache-2.0
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
@@ -14,15 +16,19 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package roundrobin
+package selectendpoint_test
 
 import (
+	"context"
 	"testing"
 
 	"google.golang.org/protobuf/proto"
 
 	"github.com/networkservicemesh/api/pkg/api/registry"
 	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/selectendpoint"
 )
 
 type args struct {
@@ -227,10 +233,14 @@ var tests = []rrtests{
 
 func Test_roundRobinSelector_SelectEndpoint(t *testing.T) {
 	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
-	rr := newRoundRobinSelector()
+	rr := selectendpoint.NewRoundRobinSelector()
 	for _, tt := range tests {
-		if got := rr.selectEndpoint(tt.args.ns, tt.args.networkServiceEndpoints); !proto.Equal(got, tt.want) {
-			t.Errorf("%s: roundRobinSelector.selectEndpoint() = %v, want %v", tt.name, got, tt.want)
+		got := rr.Select(context.Background(), nil, &discover.NetworkServiceCandidates{
+			NetworkService: tt.args.ns,
+			Endpoints:      tt.args.networkServiceEndpoints,
+		})
+		if len(got) != len(tt.args.networkServiceEndpoints) || !proto.Equal(got[0], tt.want) {
+			t.Errorf("%s: roundRobinSelector.Select() = %v, want %v first", tt.name, got, tt.want)
 		}
 	}
 }
@@ -0,0 +1,46 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package selectendpoint provides a networkservice chain element selecting an endpoint among the candidates provided
+// by discover.Candidates(ctx) with a pluggable per network service Selector strategy
+package selectendpoint
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+)
+
+// Selector is an endpoint selection strategy
+type Selector interface {
+	// Select returns the candidate endpoints in the order they should be tried for the request
+	Select(ctx context.Context, request *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint
+}
+
+// ConnectionTracker is an optional Selector interface to track the connections established with the endpoints
+type ConnectionTracker interface {
+	// Connected is called on each successful Request with the resulting connection
+	Connected(conn *networkservice.Connection)
+	// Disconnected is called on each Close with the closing connection
+	Disconnected(conn *networkservice.Connection)
+}
+
+func endpointLabels(nse *registry.NetworkServiceEndpoint, ns *registry.NetworkService) map[string]string {
+	return nse.GetNetworkServiceLabels()[ns.GetName()].GetLabels()
+}
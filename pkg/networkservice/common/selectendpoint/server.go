@@ -0,0 +1,133 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/circuitbreaker"
+	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
+)
+
+type selectEndpointServer struct {
+	selector   Selector
+	nsSelector map[string]Selector
+	breakers   *circuitbreaker.Breakers
+}
+
+// Server is a NetworkServiceServer chain element selecting an endpoint, it is also a Previewer of the selection
+type Server interface {
+	networkservice.NetworkServiceServer
+	Previewer
+}
+
+// NewServer - provides a NetworkServiceServer chain element that selects an endpoint among candidates provided by
+// discover.Candidate(ctx) in the context. Candidates are tried in the order returned by the network service Selector
+// until one of them accepts the Request.
+func NewServer(options ...Option) Server {
+	o := &selectOptions{
+		selector:   NewRoundRobinSelector(),
+		nsSelector: make(map[string]Selector),
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+	return &selectEndpointServer{
+		selector:   o.selector,
+		nsSelector: o.nsSelector,
+		breakers:   o.breakers,
+	}
+}
+
+func (s *selectEndpointServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	selector := s.getSelector(request.GetConnection().GetNetworkService())
+
+	if clienturlctx.ClientURL(ctx) != nil {
+		conn, err := next.Server(ctx).Request(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		connected(selector, conn)
+		return conn, nil
+	}
+
+	candidates := s.available(discover.Candidates(ctx))
+	endpoints := selector.Select(ctx, request, candidates)
+	if len(endpoints) == 0 {
+		return nil, errors.Errorf("failed to find endpoint for Network Service: %v %v", candidates.NetworkService, candidates.Endpoints)
+	}
+
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint.Url)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		request.GetConnection().NetworkServiceEndpointName = endpoint.Name
+		conn, err := next.Server(ctx).Request(clienturlctx.WithClientURL(ctx, u), request)
+		if err == nil {
+			connected(selector, conn)
+			return conn, nil
+		}
+	}
+	return nil, errors.Errorf("all candidates %#v fail", candidates)
+}
+
+func (s *selectEndpointServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	if clienturlctx.ClientURL(ctx) == nil {
+		return nil, errors.Errorf("passed incorrect connection: %+v", conn)
+	}
+	if tracker, ok := s.getSelector(conn.GetNetworkService()).(ConnectionTracker); ok {
+		tracker.Disconnected(conn)
+	}
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+// available returns the candidates without the ones with the open circuits
+func (s *selectEndpointServer) available(candidates *discover.NetworkServiceCandidates) *discover.NetworkServiceCandidates {
+	if s.breakers == nil {
+		return candidates
+	}
+	available := &discover.NetworkServiceCandidates{
+		NetworkService: candidates.NetworkService,
+	}
+	for _, nse := range candidates.Endpoints {
+		if s.breakers.State(nse.Name) != circuitbreaker.Open {
+			available.Endpoints = append(available.Endpoints, nse)
+		}
+	}
+	return available
+}
+
+func (s *selectEndpointServer) getSelector(nsName string) Selector {
+	if selector, ok := s.nsSelector[nsName]; ok {
+		return selector
+	}
+	return s.selector
+}
+
+func connected(selector Selector, conn *networkservice.Connection) {
+	if tracker, ok := selector.(ConnectionTracker); ok {
+		tracker.Connected(conn)
+	}
+}
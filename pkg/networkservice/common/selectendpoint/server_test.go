@@ -0,0 +1,349 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/selectendpoint"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/circuitbreaker"
+	"github.com/networkservicemesh/sdk/pkg/tools/clientinfo"
+	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
+)
+
+const (
+	nsName = "ns"
+)
+
+type testEndpointServer struct {
+	failed map[string]bool // key == NetworkServiceEndpoint.Url
+}
+
+func (s *testEndpointServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if s.failed[clienturlctx.ClientURL(ctx).String()] {
+		return nil, errors.New("failed")
+	}
+	return request.GetConnection(), nil
+}
+
+func (s *testEndpointServer) Close(_ context.Context, _ *networkservice.Connection) (*empty.Empty, error) {
+	return new(empty.Empty), nil
+}
+
+func testCandidates(count int, weights ...string) (*registry.NetworkService, []*registry.NetworkServiceEndpoint) {
+	var nses []*registry.NetworkServiceEndpoint
+	for i := 0; i < count; i++ {
+		nse := &registry.NetworkServiceEndpoint{
+			Name: fmt.Sprintf("nse-%d", i),
+			Url:  fmt.Sprintf("tcp://nse-%d", i),
+		}
+		if i < len(weights) {
+			nse.NetworkServiceLabels = map[string]*registry.NetworkServiceLabels{
+				nsName: {Labels: map[string]string{selectendpoint.WeightLabel: weights[i]}},
+			}
+		}
+		nses = append(nses, nse)
+	}
+	return &registry.NetworkService{Name: nsName}, nses
+}
+
+func request(ctx context.Context, t *testing.T, server networkservice.NetworkServiceServer, id string) *networkservice.Connection {
+	conn, err := server.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             id,
+			NetworkService: nsName,
+		},
+	})
+	require.NoError(t, err)
+	return conn
+}
+
+func TestSelectEndpointServer_Fallback(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ns, nses := testCandidates(3)
+	server := next.NewNetworkServiceServer(
+		selectendpoint.NewServer(),
+		&testEndpointServer{failed: map[string]bool{"tcp://nse-0": true, "tcp://nse-1": true}},
+	)
+
+	ctx := discover.WithCandidates(context.Background(), nses, ns)
+	require.Equal(t, "nse-2", request(ctx, t, server, "id").GetNetworkServiceEndpointName())
+
+	server = next.NewNetworkServiceServer(
+		selectendpoint.NewServer(),
+		&testEndpointServer{failed: map[string]bool{"tcp://nse-0": true, "tcp://nse-1": true, "tcp://nse-2": true}},
+	)
+	_, err := server.Request(ctx, &networkservice.NetworkServiceRequest{Connection: new(networkservice.Connection)})
+	require.Error(t, err)
+}
+
+func TestSelectEndpointServer_LeastConnections(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ns, nses := testCandidates(3)
+	server := next.NewNetworkServiceServer(
+		selectendpoint.NewServer(
+			selectendpoint.WithSelector(selectendpoint.NewRandomSelector(0)),
+			selectendpoint.WithNetworkServiceSelector(nsName, selectendpoint.NewLeastConnectionsSelector()),
+		),
+		&testEndpointServer{},
+	)
+
+	ctx := discover.WithCandidates(context.Background(), nses, ns)
+
+	var conns []*networkservice.Connection
+	for i := 0; i < 3; i++ {
+		conns = append(conns, request(ctx, t, server, fmt.Sprint(i)))
+		require.Equal(t, nses[i].Name, conns[i].GetNetworkServiceEndpointName())
+	}
+
+	// Refresh should not change the counts
+	request(clienturlctx.WithClientURL(ctx, &url.URL{}), t, server, conns[0].GetId())
+	require.Equal(t, "nse-0", request(ctx, t, server, "3").GetNetworkServiceEndpointName())
+
+	u, err := url.Parse(nses[1].Url)
+	require.NoError(t, err)
+	_, err = server.Close(clienturlctx.WithClientURL(ctx, u), conns[1])
+	require.NoError(t, err)
+
+	require.Equal(t, "nse-1", request(ctx, t, server, "4").GetNetworkServiceEndpointName())
+}
+
+func TestSelectEndpointServer_Weighted(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ns, nses := testCandidates(3, "0", "1", "3")
+	selector := selectendpoint.NewWeightedSelector(0)
+	candidates := &discover.NetworkServiceCandidates{NetworkService: ns, Endpoints: nses}
+
+	const count = 4000
+	selected := make(map[string]int)
+	for i := 0; i < count; i++ {
+		endpoints := selector.Select(context.Background(), nil, candidates)
+		require.Len(t, endpoints, 3)
+		require.Equal(t, "nse-0", endpoints[2].Name)
+		selected[endpoints[0].Name]++
+	}
+
+	require.Zero(t, selected["nse-0"])
+	require.InDelta(t, count/4, selected["nse-1"], count/20)
+	require.InDelta(t, count*3/4, selected["nse-2"], count/20)
+}
+
+func TestSelectEndpointServer_Random(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ns, nses := testCandidates(5)
+	candidates := &discover.NetworkServiceCandidates{NetworkService: ns, Endpoints: nses}
+
+	selector1 := selectendpoint.NewRandomSelector(1)
+	selector2 := selectendpoint.NewRandomSelector(1)
+	for i := 0; i < 10; i++ {
+		endpoints := selector1.Select(context.Background(), nil, candidates)
+		require.Len(t, endpoints, len(nses))
+		require.Equal(t, endpoints, selector2.Select(context.Background(), nil, candidates))
+	}
+}
+
+func TestSelectEndpointServer_Topology(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ns, nses := testCandidates(4)
+	for i, labels := range []map[string]string{
+		{clientinfo.NodeNameLabel: "node-2", clientinfo.ClusterNameLabel: "cluster-2"},
+		{clientinfo.NodeNameLabel: "node-2", clientinfo.ClusterNameLabel: "cluster-1"},
+		{clientinfo.NodeNameLabel: "node-1", clientinfo.ClusterNameLabel: "cluster-1"},
+		nil,
+	} {
+		nses[i].NetworkServiceLabels = map[string]*registry.NetworkServiceLabels{
+			nsName: {Labels: labels},
+		}
+	}
+
+	ctx := discover.WithCandidates(context.Background(), nses, ns)
+	testRequest := func(server networkservice.NetworkServiceServer) string {
+		conn, err := server.Request(ctx, &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{
+				NetworkService: nsName,
+				Labels: map[string]string{
+					clientinfo.NodeNameLabel:    "node-1",
+					clientinfo.ClusterNameLabel: "cluster-1",
+				},
+			},
+		})
+		require.NoError(t, err)
+		return conn.GetNetworkServiceEndpointName()
+	}
+
+	failed := make(map[string]bool)
+	server := next.NewNetworkServiceServer(
+		selectendpoint.NewServer(selectendpoint.WithSelector(
+			selectendpoint.NewTopologySelector(selectendpoint.NewRoundRobinSelector),
+		)),
+		&testEndpointServer{failed: failed},
+	)
+
+	for i := 0; i < 3; i++ {
+		require.Equal(t, "nse-2", testRequest(server))
+	}
+
+	failed["tcp://nse-2"] = true
+	for i := 0; i < 3; i++ {
+		require.Equal(t, "nse-1", testRequest(server))
+	}
+
+	failed["tcp://nse-1"] = true
+	require.Contains(t, []string{"nse-0", "nse-3"}, testRequest(server))
+
+	server = next.NewNetworkServiceServer(
+		selectendpoint.NewServer(selectendpoint.WithSelector(
+			selectendpoint.NewTopologySelector(selectendpoint.NewRoundRobinSelector, clientinfo.ClusterNameLabel),
+		)),
+		&testEndpointServer{},
+	)
+	require.Equal(t, "nse-1", testRequest(server))
+	require.Equal(t, "nse-2", testRequest(server))
+}
+
+func TestSelectEndpointServer_ConsistentHash(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	const clientsCount = 1000
+	const labelKey = "session"
+
+	selector := selectendpoint.NewConsistentHashSelector(labelKey)
+	selectAll := func(nses []*registry.NetworkServiceEndpoint) map[string]string {
+		ns := &registry.NetworkService{Name: nsName}
+		selected := make(map[string]string)
+		for i := 0; i < clientsCount; i++ {
+			conn := &networkservice.Connection{
+				Path: &networkservice.Path{
+					PathSegments: []*networkservice.PathSegment{{Name: fmt.Sprintf("client-%d", i)}},
+				},
+			}
+			if i%2 == 0 {
+				conn.Labels = map[string]string{labelKey: fmt.Sprintf("session-%d", i)}
+			}
+			endpoints := selector.Select(context.Background(), &networkservice.NetworkServiceRequest{Connection: conn},
+				&discover.NetworkServiceCandidates{NetworkService: ns, Endpoints: nses})
+			require.Len(t, endpoints, len(nses))
+			selected[fmt.Sprint(i)] = endpoints[0].Name
+		}
+		return selected
+	}
+
+	_, nses := testCandidates(6)
+
+	selected := selectAll(nses[:5])
+	require.Equal(t, selected, selectAll(nses[:5]))
+
+	counts := make(map[string]int)
+	for _, nseName := range selected {
+		counts[nseName]++
+	}
+	require.Len(t, counts, 5)
+	for _, count := range counts {
+		require.InDelta(t, clientsCount/5, count, clientsCount/10)
+	}
+
+	// Removing NSE remaps only its clients
+	removed := selectAll(nses[1:5])
+	for client, nseName := range selected {
+		if nseName != "nse-0" {
+			require.Equal(t, nseName, removed[client])
+		}
+	}
+
+	// Adding NSE remaps clients only to it
+	added := selectAll(nses)
+	for client, nseName := range selected {
+		if added[client] != nseName {
+			require.Equal(t, "nse-5", added[client])
+		}
+	}
+}
+
+func TestSelectEndpointServer_Preview(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ns, nses := testCandidates(3)
+	selectServer := selectendpoint.NewServer(selectendpoint.WithSelector(selectendpoint.NewRoundRobinSelector()))
+	server := next.NewNetworkServiceServer(
+		selectServer,
+		&testEndpointServer{},
+	)
+
+	ctx := discover.WithCandidates(context.Background(), nses, ns)
+	testPreview := func() (names []string) {
+		endpoints := selectServer.Preview(ctx, &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{
+				NetworkService: nsName,
+			},
+		}, discover.Candidates(ctx))
+		for _, nse := range endpoints {
+			names = append(names, nse.Name)
+		}
+		return names
+	}
+
+	require.Equal(t, []string{"nse-0", "nse-1", "nse-2"}, testPreview())
+	require.Equal(t, []string{"nse-0", "nse-1", "nse-2"}, testPreview())
+	require.Equal(t, "nse-0", request(ctx, t, server, "id-0").GetNetworkServiceEndpointName())
+
+	require.Equal(t, []string{"nse-1", "nse-2", "nse-0"}, testPreview())
+	require.Equal(t, "nse-1", request(ctx, t, server, "id-1").GetNetworkServiceEndpointName())
+}
+
+func TestSelectEndpointServer_CircuitBreaker(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ns, nses := testCandidates(3)
+	breakers := circuitbreaker.NewBreakers(circuitbreaker.WithFailureThreshold(1))
+	server := next.NewNetworkServiceServer(
+		selectendpoint.NewServer(selectendpoint.WithCircuitBreaker(breakers)),
+		&testEndpointServer{},
+	)
+
+	ctx := discover.WithCandidates(context.Background(), nses, ns)
+
+	breakers.Failure("nse-1")
+	for i := 0; i < 4; i++ {
+		require.NotEqual(t, "nse-1", request(ctx, t, server, fmt.Sprint(i)).GetNetworkServiceEndpointName())
+	}
+
+	breakers.Failure("nse-0")
+	breakers.Failure("nse-2")
+	_, err := server.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			NetworkService: nsName,
+		},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+	"github.com/networkservicemesh/sdk/pkg/tools/clientinfo"
+)
+
+type topologySelector struct {
+	selectors []Selector
+	labelKeys []string
+}
+
+// NewTopologySelector returns a Selector preferring the candidates closer to the client. Candidates are split into
+// the tiers by the first of labelKeys having the same value in the client connection labels and in the NSE labels,
+// candidates matching no key form the last tier. Tiers are tried in the labelKeys order, candidates inside a tier are
+// ordered with the tier own Selector created with newSelector. Default labelKeys are clientinfo.NodeNameLabel,
+// clientinfo.ClusterNameLabel: node local candidates first, then the cluster local ones, then any.
+func NewTopologySelector(newSelector func() Selector, labelKeys ...string) Selector {
+	if len(labelKeys) == 0 {
+		labelKeys = []string{clientinfo.NodeNameLabel, clientinfo.ClusterNameLabel}
+	}
+	s := &topologySelector{
+		labelKeys: labelKeys,
+	}
+	for i := 0; i <= len(labelKeys); i++ {
+		s.selectors = append(s.selectors, newSelector())
+	}
+	return s
+}
+
+func (s *topologySelector) Select(ctx context.Context, request *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	return s.order(ctx, request, candidates, func(ctx context.Context, selector Selector, request *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+		return selector.Select(ctx, request, candidates)
+	})
+}
+
+func (s *topologySelector) Preview(ctx context.Context, request *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	return s.order(ctx, request, candidates, preview)
+}
+
+func (s *topologySelector) order(ctx context.Context, request *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates,
+	selectFunc func(context.Context, Selector, *networkservice.NetworkServiceRequest, *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+	clientLabels := request.GetConnection().GetLabels()
+
+	tiers := make([][]*registry.NetworkServiceEndpoint, len(s.labelKeys)+1)
+	for _, nse := range candidates.Endpoints {
+		tier := len(s.labelKeys)
+		nseLabels := endpointLabels(nse, candidates.NetworkService)
+		for i, key := range s.labelKeys {
+			if value, ok := clientLabels[key]; ok && nseLabels[key] == value {
+				tier = i
+				break
+			}
+		}
+		tiers[tier] = append(tiers[tier], nse)
+	}
+
+	var endpoints []*registry.NetworkServiceEndpoint
+	for i, tier := range tiers {
+		if len(tier) == 0 {
+			continue
+		}
+		endpoints = append(endpoints, selectFunc(ctx, s.selectors[i], request, &discover.NetworkServiceCandidates{
+			NetworkService: candidates.NetworkService,
+			Endpoints:      tier,
+		})...)
+	}
+	return endpoints
+}
+
+// Connected - the same NSE can be in the different tiers for the different clients, so all the tier selectors track
+// all the connections
+func (s *topologySelector) Connected(conn *networkservice.Connection) {
+	for _, selector := range s.selectors {
+		if tracker, ok := selector.(ConnectionTracker); ok {
+			tracker.Connected(conn)
+		}
+	}
+}
+
+func (s *topologySelector) Disconnected(conn *networkservice.Connection) {
+	for _, selector := range s.selectors {
+		if tracker, ok := selector.(ConnectionTracker); ok {
+			tracker.Disconnected(conn)
+		}
+	}
+}
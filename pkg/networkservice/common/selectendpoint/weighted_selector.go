@@ -0,0 +1,106 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+)
+
+const (
+	// WeightLabel is the NSE network service label setting the NSE weight for the weighted Selector
+	WeightLabel = "weight"
+
+	defaultWeight = 1
+)
+
+type weightedSelector struct {
+	rand *lockedRand
+}
+
+// NewWeightedSelector returns a Selector randomly ordering the candidates proportionally to their weights set with
+// the WeightLabel NSE label (e.g. weight=3). Candidates without a valid weight have weight 1, candidates with weight 0
+// are tried only after all the others.
+func NewWeightedSelector(seed int64) Selector {
+	return &weightedSelector{
+		rand: newLockedRand(seed),
+	}
+}
+
+func (s *weightedSelector) Select(_ context.Context, _ *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	endpoints := append([]*registry.NetworkServiceEndpoint{}, candidates.Endpoints...)
+
+	// Weighted random sampling without replacement: sort by u^(1/weight) descending
+	keys := s.rand.float64s(len(endpoints))
+	for i, nse := range endpoints {
+		if weight := endpointWeight(nse, candidates.NetworkService); weight > 0 {
+			keys[i] = math.Pow(keys[i], 1/weight)
+		} else {
+			keys[i] = -1
+		}
+	}
+	sort.Stable(&byKey{endpoints: endpoints, keys: keys})
+
+	return endpoints
+}
+
+// Preview - random order cannot be predicted without changing the random source state, so the candidates are returned
+// in the most probable order: by the weight descending
+func (s *weightedSelector) Preview(_ context.Context, _ *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	endpoints := append([]*registry.NetworkServiceEndpoint{}, candidates.Endpoints...)
+
+	keys := make([]float64, len(endpoints))
+	for i, nse := range endpoints {
+		keys[i] = endpointWeight(nse, candidates.NetworkService)
+	}
+	sort.Stable(&byKey{endpoints: endpoints, keys: keys})
+
+	return endpoints
+}
+
+func endpointWeight(nse *registry.NetworkServiceEndpoint, ns *registry.NetworkService) float64 {
+	weight, err := strconv.ParseFloat(endpointLabels(nse, ns)[WeightLabel], 64)
+	if err != nil || weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+		return defaultWeight
+	}
+	return weight
+}
+
+type byKey struct {
+	endpoints []*registry.NetworkServiceEndpoint
+	keys      []float64
+}
+
+func (b *byKey) Len() int {
+	return len(b.endpoints)
+}
+
+func (b *byKey) Less(i, j int) bool {
+	return b.keys[i] > b.keys[j]
+}
+
+func (b *byKey) Swap(i, j int) {
+	b.endpoints[i], b.endpoints[j] = b.endpoints[j], b.endpoints[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
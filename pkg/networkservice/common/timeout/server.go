@@ -21,9 +21,10 @@ package timeout
 
 import (
 	"context"
-	"time"
 
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
 	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+	"github.com/networkservicemesh/sdk/pkg/tools/scheduler"
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
@@ -36,17 +37,22 @@ import (
 )
 
 type timeoutServer struct {
-	ctx    context.Context
-	timers timerMap
+	ctx       context.Context
+	clock     clock.Clock
+	scheduler *scheduler.Scheduler
+	timers    timerMap
 }
 
 // NewServer - creates a new NetworkServiceServer chain element that implements timeout of expired connections
 //             for the subsequent chain elements.
 // WARNING: `timeout` uses ctx as a context for the Close, so if there are any chain elements setting some data
 //          in context in chain before the `timeout`, these changes won't appear in the Close context.
+// Timers are scheduled with the scheduler.Scheduler from ctx and are driven by the clock.Clock from ctx.
 func NewServer(ctx context.Context) networkservice.NetworkServiceServer {
 	return &timeoutServer{
-		ctx: ctx,
+		ctx:       ctx,
+		clock:     clock.FromContext(ctx),
+		scheduler: scheduler.FromContext(ctx),
 	}
 }
 
@@ -84,7 +90,7 @@ func (t *timeoutServer) Request(ctx context.Context, request *networkservice.Net
 	return conn, nil
 }
 
-func (t *timeoutServer) createTimer(ctx context.Context, conn *networkservice.Connection) (*time.Timer, error) {
+func (t *timeoutServer) createTimer(ctx context.Context, conn *networkservice.Connection) (clock.Timer, error) {
 	logEntry := logger.Log(ctx).WithField("timeoutServer", "createTimer")
 
 	executor := serialize.GetExecutor(ctx)
@@ -102,8 +108,8 @@ func (t *timeoutServer) createTimer(ctx context.Context, conn *networkservice.Co
 
 	conn = conn.Clone()
 
-	timerPtr := new(*time.Timer)
-	*timerPtr = time.AfterFunc(time.Until(expireTime), func() {
+	timerPtr := new(clock.Timer)
+	*timerPtr = t.scheduler.AfterFunc(t.clock.Until(expireTime), func() {
 		<-executor.AsyncExec(func() {
 			if timer, _ := t.timers.Load(conn.GetId()); timer != *timerPtr {
 				logEntry.Warnf("timer has been already stopped: %v", conn.GetId())
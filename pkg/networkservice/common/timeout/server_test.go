@@ -41,6 +41,8 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
 )
 
 const (
@@ -57,7 +59,7 @@ func testClient(ctx context.Context, server networkservice.NetworkServiceServer,
 	return chain.NewNetworkServiceClient(
 		updatepath.NewClient(clientName),
 		updatetoken.NewClient(func(_ credentials.AuthInfo) (string, time.Time, error) {
-			return "token", time.Now().Add(duration), nil
+			return "token", clock.FromContext(ctx).Now().Add(duration), nil
 		}),
 		kernel.NewClient(),
 		adapters.NewServerToClient(
@@ -77,12 +79,19 @@ func TestTimeoutServer_Request(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
 	connServer := newConnectionsServer(t)
 
 	_, err := testClient(ctx, connServer, tokenTimeout).Request(logger.WithLog(ctx), &networkservice.NetworkServiceRequest{})
 	require.NoError(t, err)
 	require.Condition(t, connServer.validator(1, 0))
 
+	clockMock.Add(tokenTimeout / 2)
+	require.Never(t, connServer.validator(0, 1), 10*tick, tick)
+
+	clockMock.Add(tokenTimeout / 2)
 	require.Eventually(t, connServer.validator(0, 1), waitFor, tick)
 }
 
@@ -90,6 +99,9 @@ func TestTimeoutServer_Close_BeforeTimeout(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
 	connServer := newConnectionsServer(t)
 
 	client := testClient(ctx, connServer, tokenTimeout)
@@ -104,13 +116,17 @@ func TestTimeoutServer_Close_BeforeTimeout(t *testing.T) {
 	require.Condition(t, connServer.validator(0, 1))
 
 	// ensure there will be no double Close
-	<-time.After(waitFor)
+	clockMock.Add(tokenTimeout)
+	<-time.After(10 * tick)
 }
 
 func TestTimeoutServer_Close_AfterTimeout(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
 	connServer := newConnectionsServer(t)
 
 	client := testClient(ctx, connServer, tokenTimeout)
@@ -120,6 +136,7 @@ func TestTimeoutServer_Close_AfterTimeout(t *testing.T) {
 	require.NoError(t, err)
 	require.Condition(t, connServer.validator(1, 0))
 
+	clockMock.Add(tokenTimeout)
 	require.Eventually(t, connServer.validator(0, 1), waitFor, tick)
 
 	_, err = client.Close(ctx, conn)
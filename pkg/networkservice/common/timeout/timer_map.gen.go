@@ -1,9 +1,10 @@
-// Code generated by "-output timer_map.gen.go -type timerMap<string,*time.Timer> -output timer_map.gen.go -type timerMap<string,*time.Timer>"; DO NOT EDIT.
+// Code generated by "-output timer_map.gen.go -type timerMap<string,github.com/networkservicemesh/sdk/pkg/tools/clock.Timer> -output timer_map.gen.go -type timerMap<string,github.com/networkservicemesh/sdk/pkg/tools/clock.Timer>"; DO NOT EDIT.
 package timeout
 
 import (
 	"sync" // Used by sync.Map.
-	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
 )
 
 // Generate code that will fail if the constants change value.
@@ -13,43 +14,43 @@ func _() {
 	_ = (sync.Map)(timerMap{})
 }
 
-var _nil_timerMap_time_Timer_value = func() (val *time.Timer) { return }()
+var _nil_timerMap_clock_Timer_value = func() (val clock.Timer) { return }()
 
 // Load returns the value stored in the map for a key, or nil if no
 // value is present.
 // The ok result indicates whether value was found in the map.
-func (m *timerMap) Load(key string) (*time.Timer, bool) {
+func (m *timerMap) Load(key string) (clock.Timer, bool) {
 	value, ok := (*sync.Map)(m).Load(key)
 	if value == nil {
-		return _nil_timerMap_time_Timer_value, ok
+		return _nil_timerMap_clock_Timer_value, ok
 	}
-	return value.(*time.Timer), ok
+	return value.(clock.Timer), ok
 }
 
 // Store sets the value for a key.
-func (m *timerMap) Store(key string, value *time.Timer) {
+func (m *timerMap) Store(key string, value clock.Timer) {
 	(*sync.Map)(m).Store(key, value)
 }
 
 // LoadOrStore returns the existing value for the key if present.
 // Otherwise, it stores and returns the given value.
 // The loaded result is true if the value was loaded, false if stored.
-func (m *timerMap) LoadOrStore(key string, value *time.Timer) (*time.Timer, bool) {
+func (m *timerMap) LoadOrStore(key string, value clock.Timer) (clock.Timer, bool) {
 	actual, loaded := (*sync.Map)(m).LoadOrStore(key, value)
 	if actual == nil {
-		return _nil_timerMap_time_Timer_value, loaded
+		return _nil_timerMap_clock_Timer_value, loaded
 	}
-	return actual.(*time.Timer), loaded
+	return actual.(clock.Timer), loaded
 }
 
 // LoadAndDelete deletes the value for a key, returning the previous value if any.
 // The loaded result reports whether the key was present.
-func (m *timerMap) LoadAndDelete(key string) (value *time.Timer, loaded bool) {
+func (m *timerMap) LoadAndDelete(key string) (value clock.Timer, loaded bool) {
 	actual, loaded := (*sync.Map)(m).LoadAndDelete(key)
 	if actual == nil {
-		return _nil_timerMap_time_Timer_value, loaded
+		return _nil_timerMap_clock_Timer_value, loaded
 	}
-	return actual.(*time.Timer), loaded
+	return actual.(clock.Timer), loaded
 }
 
 // Delete deletes the value for a key.
@@ -67,8 +68,8 @@ func (m *timerMap) Delete(key string) {
 //
 // Range may be O(N) with the number of elements in the map even if f returns
 // false after a constant number of calls.
-func (m *timerMap) Range(f func(key string, value *time.Timer) bool) {
+func (m *timerMap) Range(f func(key string, value clock.Timer) bool) {
 	(*sync.Map)(m).Range(func(key, value interface{}) bool {
-		return f(key.(string), value.(*time.Timer))
+		return f(key.(string), value.(clock.Timer))
 	})
 }
@@ -0,0 +1,210 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dualstack provides the tools shared by the IPAM chain elements allocating the addresses of the both IP
+// families for the same connection. Prefixes are grouped by the IP family, the family of the first prefix is the
+// primary one: its addresses are set to the IPContext with the routes, addresses of the second family are set to the
+// Connection.Context.ExtraContext with the SecondarySrcIPAddrKey, SecondaryDstIPAddrKey keys.
+package dualstack
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/ippool"
+)
+
+const (
+	// SecondarySrcIPAddrKey - Connection.Context.ExtraContext key for the source address of the second IP family,
+	// IPContext has room only for one source address. IPAM doesn't add the routes for the second IP family, forwarder
+	// configuring the secondary addresses on the interface should add them on its own.
+	SecondarySrcIPAddrKey = "secondary_src_ip_addr"
+	// SecondaryDstIPAddrKey - Connection.Context.ExtraContext key for the destination address of the second IP family,
+	// IPContext has room only for one destination address
+	SecondaryDstIPAddrKey = "secondary_dst_ip_addr"
+)
+
+// Allocation is a set of the connection addresses of a single IP family
+type Allocation interface {
+	// SrcAddr returns the source address in CIDR notation
+	SrcAddr() string
+	// DstAddr returns the destination address in CIDR notation
+	DstAddr() string
+	// SrcRoute returns the prefix of the source side route
+	SrcRoute() string
+	// DstRoute returns the prefix of the destination side route
+	DstRoute() string
+	// IsExcluded returns true if some of the addresses are in exclude
+	IsExcluded(exclude *ippool.IPPool) bool
+	// Free returns the addresses back to the pool
+	Free()
+}
+
+// AllocateFunc returns the Allocation for the IP family: current if it shouldn't be changed, or a new one. current is
+// nil if there is no Allocation for the family yet or its addresses have been excluded.
+type AllocateFunc func(family int, current Allocation, exclude *ippool.IPPool) (Allocation, error)
+
+// GroupByFamily groups the prefixes by the IP family, the family of the first prefix goes first
+func GroupByFamily(prefixes []*net.IPNet) ([][]*net.IPNet, error) {
+	if len(prefixes) == 0 {
+		return nil, errors.New("required one or more prefixes")
+	}
+
+	var ipv4Nets, ipv6Nets []*net.IPNet
+	for _, prefix := range prefixes {
+		if prefix == nil {
+			return nil, errors.Errorf("prefix must not be nil: %+v", prefixes)
+		}
+		if prefix.IP.To4() != nil {
+			ipv4Nets = append(ipv4Nets, prefix)
+		} else {
+			ipv6Nets = append(ipv6Nets, prefix)
+		}
+	}
+
+	if prefixes[0].IP.To4() == nil {
+		ipv4Nets, ipv6Nets = ipv6Nets, ipv4Nets
+	}
+
+	var families [][]*net.IPNet
+	for _, ipNets := range [][]*net.IPNet{ipv4Nets, ipv6Nets} {
+		if len(ipNets) > 0 {
+			families = append(families, ipNets)
+		}
+	}
+	return families, nil
+}
+
+// Allocate updates the connection allocations, one for each IP family: allocations with the addresses excluded by the
+// IPContext.ExcludedPrefixes are freed, then allocate is called for each family. Addresses and routes of the resulting
+// allocations are set to the connection. If some family can't be allocated, the new allocations of the other families
+// are freed, so the connection doesn't keep an incomplete set of the addresses.
+func Allocate(conn *networkservice.Connection, allocations []Allocation, allocate AllocateFunc) error {
+	if conn.GetContext() == nil {
+		conn.Context = &networkservice.ConnectionContext{}
+	}
+	if conn.GetContext().GetIpContext() == nil {
+		conn.GetContext().IpContext = &networkservice.IPContext{}
+	}
+	ipContext := conn.GetContext().GetIpContext()
+
+	exclude, err := ippool.NewWithNetString(ipContext.GetExcludedPrefixes()...)
+	if err != nil {
+		return err
+	}
+
+	var allocated []int
+	for i := range allocations {
+		// only the primary IP family addresses are configured on the interface, so only they need the routes
+		primary := i == 0
+		if allocations[i] != nil && allocations[i].IsExcluded(exclude) {
+			if primary {
+				deleteRoutes(ipContext, allocations[i])
+			}
+			allocations[i].Free()
+			allocations[i] = nil
+		}
+
+		allocation, allocErr := allocate(i, allocations[i], exclude)
+		if allocErr != nil {
+			for _, j := range allocated {
+				if j == 0 {
+					deleteRoutes(ipContext, allocations[j])
+				}
+				allocations[j].Free()
+				allocations[j] = nil
+			}
+			return allocErr
+		}
+		if allocation != allocations[i] {
+			if allocations[i] != nil {
+				if primary {
+					deleteRoutes(ipContext, allocations[i])
+				}
+				allocations[i].Free()
+			}
+			allocations[i] = allocation
+			allocated = append(allocated, i)
+		}
+
+		if primary {
+			ipContext.SrcIpAddr = allocation.SrcAddr()
+			ipContext.DstIpAddr = allocation.DstAddr()
+			addRoute(&ipContext.SrcRoutes, allocation.SrcRoute())
+			addRoute(&ipContext.DstRoutes, allocation.DstRoute())
+		} else {
+			if conn.GetContext().GetExtraContext() == nil {
+				conn.GetContext().ExtraContext = make(map[string]string)
+			}
+			conn.GetContext().GetExtraContext()[SecondarySrcIPAddrKey] = allocation.SrcAddr()
+			conn.GetContext().GetExtraContext()[SecondaryDstIPAddrKey] = allocation.DstAddr()
+		}
+	}
+
+	return nil
+}
+
+// HostAddr returns ip in CIDR notation with the single address mask: /32 for IPv4, /128 for IPv6
+func HostAddr(ip net.IP) string {
+	if ipv4 := ip.To4(); ipv4 != nil {
+		ip = ipv4
+	}
+	bits := len(ip) * 8
+	return (&net.IPNet{
+		IP:   ip,
+		Mask: net.CIDRMask(bits, bits),
+	}).String()
+}
+
+// ParseAddr parses addr both in CIDR and in IP notation, returns nil if addr is not valid
+func ParseAddr(addr string) net.IP {
+	ip, _, err := net.ParseCIDR(addr)
+	if err != nil {
+		ip = net.ParseIP(addr)
+	}
+	if ipv4 := ip.To4(); ipv4 != nil {
+		return ipv4
+	}
+	return ip
+}
+
+func deleteRoutes(ipContext *networkservice.IPContext, allocation Allocation) {
+	deleteRoute(&ipContext.SrcRoutes, allocation.SrcRoute())
+	deleteRoute(&ipContext.DstRoutes, allocation.DstRoute())
+}
+
+func deleteRoute(routes *[]*networkservice.Route, prefix string) {
+	for i, route := range *routes {
+		if route.Prefix == prefix {
+			*routes = append((*routes)[:i], (*routes)[i+1:]...)
+			return
+		}
+	}
+}
+
+func addRoute(routes *[]*networkservice.Route, prefix string) {
+	for _, route := range *routes {
+		if route.Prefix == prefix {
+			return
+		}
+	}
+	*routes = append(*routes, &networkservice.Route{
+		Prefix: prefix,
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dualstack_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/dualstack"
+	"github.com/networkservicemesh/sdk/pkg/tools/ippool"
+)
+
+func parseCIDR(t *testing.T, s string) *net.IPNet {
+	_, ipNet, err := net.ParseCIDR(s)
+	require.NoError(t, err)
+	return ipNet
+}
+
+func TestGroupByFamily(t *testing.T) {
+	ipv4Net1, ipv4Net2 := parseCIDR(t, "10.0.0.0/24"), parseCIDR(t, "10.0.1.0/24")
+	ipv6Net := parseCIDR(t, "fe80::/64")
+
+	families, err := dualstack.GroupByFamily([]*net.IPNet{ipv6Net, ipv4Net1, ipv4Net2})
+	require.NoError(t, err)
+	require.Equal(t, [][]*net.IPNet{{ipv6Net}, {ipv4Net1, ipv4Net2}}, families)
+
+	_, err = dualstack.GroupByFamily(nil)
+	require.Error(t, err)
+
+	_, err = dualstack.GroupByFamily([]*net.IPNet{ipv4Net1, nil})
+	require.Error(t, err)
+}
+
+type testAllocation struct {
+	srcAddr, dstAddr string
+	freed            bool
+}
+
+func (a *testAllocation) SrcAddr() string  { return a.srcAddr }
+func (a *testAllocation) DstAddr() string  { return a.dstAddr }
+func (a *testAllocation) SrcRoute() string { return a.dstAddr }
+func (a *testAllocation) DstRoute() string { return a.srcAddr }
+func (a *testAllocation) Free()            { a.freed = true }
+
+func (a *testAllocation) IsExcluded(exclude *ippool.IPPool) bool {
+	return exclude.Contains(dualstack.ParseAddr(a.srcAddr)) || exclude.Contains(dualstack.ParseAddr(a.dstAddr))
+}
+
+func TestAllocate(t *testing.T) {
+	ipv4 := &testAllocation{srcAddr: "10.0.0.1/32", dstAddr: "10.0.0.0/32"}
+	ipv6 := &testAllocation{srcAddr: "fe80::1/128", dstAddr: "fe80::/128"}
+
+	conn := new(networkservice.Connection)
+	allocations := make([]dualstack.Allocation, 2)
+	err := dualstack.Allocate(conn, allocations, func(family int, _ dualstack.Allocation, _ *ippool.IPPool) (dualstack.Allocation, error) {
+		return []dualstack.Allocation{ipv4, ipv6}[family], nil
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "10.0.0.1/32", conn.GetContext().GetIpContext().GetSrcIpAddr())
+	require.Equal(t, "10.0.0.0/32", conn.GetContext().GetIpContext().GetDstIpAddr())
+	require.Equal(t, "fe80::1/128", conn.GetContext().GetExtraContext()[dualstack.SecondarySrcIPAddrKey])
+	require.Equal(t, "fe80::/128", conn.GetContext().GetExtraContext()[dualstack.SecondaryDstIPAddrKey])
+	require.Equal(t, []*networkservice.Route{{Prefix: "10.0.0.0/32"}}, conn.GetContext().GetIpContext().GetSrcRoutes())
+	require.Equal(t, []*networkservice.Route{{Prefix: "10.0.0.1/32"}}, conn.GetContext().GetIpContext().GetDstRoutes())
+
+	// Excluded IPv4 allocation is freed, the new one is freed as well if IPv6 allocation fails
+	conn.GetContext().GetIpContext().ExcludedPrefixes = []string{"10.0.0.0/24", "fe80::/64"}
+	newIPv4 := &testAllocation{srcAddr: "10.0.1.1/32", dstAddr: "10.0.1.0/32"}
+	err = dualstack.Allocate(conn, allocations, func(family int, current dualstack.Allocation, _ *ippool.IPPool) (dualstack.Allocation, error) {
+		if family == 0 {
+			return newIPv4, nil
+		}
+		return nil, errors.New("no IPv6 addresses")
+	})
+	require.Error(t, err)
+
+	require.True(t, ipv4.freed)
+	require.True(t, ipv6.freed)
+	require.True(t, newIPv4.freed)
+	require.Equal(t, []dualstack.Allocation{nil, nil}, allocations)
+	require.Empty(t, conn.GetContext().GetIpContext().GetSrcRoutes())
+}
@@ -14,33 +14,27 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package point2pointipam
+package extraprefixipam
 
 import (
-	"encoding/binary"
-	"net"
+	"context"
 
-	"github.com/RoaringBitmap/roaring"
-
-	"github.com/networkservicemesh/sdk/pkg/tools/cidr"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
 )
 
-func exclude(prefixes ...string) (*roaring.Bitmap, error) {
-	exclude := roaring.New()
-	for _, prefix := range prefixes {
-		_, ipNet, err := net.ParseCIDR(prefix)
-		if err != nil {
-			return nil, err
-		}
-		low := binary.BigEndian.Uint32(cidr.NetworkAddress(ipNet).To4())
-		high := binary.BigEndian.Uint32(cidr.BroadcastAddress(ipNet).To4()) + 1
+type keyType struct{}
+
+func storeConnInfo(ctx context.Context, connInfo *connectionInfo) {
+	metadata.Map(ctx, false).Store(keyType{}, connInfo)
+}
 
-		exclude.AddRange(uint64(low), uint64(high))
+func loadConnInfo(ctx context.Context) (*connectionInfo, bool) {
+	if raw, ok := metadata.Map(ctx, false).Load(keyType{}); ok {
+		return raw.(*connectionInfo), true
 	}
-	return exclude, nil
+	return nil, false
 }
 
-func addrToInt(addr string) uint32 {
-	ip, _, _ := net.ParseCIDR(addr)
-	return binary.BigEndian.Uint32(ip.To4())
+func deleteConnInfo(ctx context.Context) {
+	metadata.Map(ctx, false).Delete(keyType{})
 }
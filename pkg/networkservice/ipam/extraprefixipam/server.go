@@ -0,0 +1,167 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package extraprefixipam provides an IPAM server chain element allocating the extra prefixes
+package extraprefixipam
+
+import (
+	"context"
+	"net"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+	"github.com/networkservicemesh/sdk/pkg/tools/prefixpool"
+)
+
+type extraPrefixServer struct {
+	prefixPool *prefixpool.PrefixPool
+}
+
+type connectionInfo struct {
+	requests []*networkservice.ExtraPrefixRequest
+	prefixes []string
+}
+
+// NewServer - creates a new NetworkServiceServer chain element allocating the prefixes requested with the
+//             IPContext.ExtraPrefixRequest from the prefixPool. Allocated prefixes don't intersect with the
+//             IPContext.ExcludedPrefixes and are returned in the IPContext.ExtraPrefixes, they are released on Close.
+//             prefixPool - pool to allocate the prefixes from
+func NewServer(prefixPool *prefixpool.PrefixPool) networkservice.NetworkServiceServer {
+	return &extraPrefixServer{
+		prefixPool: prefixPool,
+	}
+}
+
+func (s *extraPrefixServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	conn := request.GetConnection()
+	if conn.GetContext() == nil {
+		conn.Context = &networkservice.ConnectionContext{}
+	}
+	if conn.GetContext().GetIpContext() == nil {
+		conn.GetContext().IpContext = &networkservice.IPContext{}
+	}
+	ipContext := conn.GetContext().GetIpContext()
+	requests := ipContext.GetExtraPrefixRequest()
+
+	connInfo, ok := loadConnInfo(ctx)
+	if ok && !connInfo.shouldUpdate(requests, ipContext.GetExcludedPrefixes()) {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	if ok {
+		// requests are changed or some of the existing prefixes are excluded
+		deletePrefixes(&ipContext.ExtraPrefixes, connInfo.prefixes)
+		s.release(ctx, conn.GetId())
+		deleteConnInfo(ctx)
+	}
+
+	if len(requests) > 0 {
+		prefixes, err := s.prefixPool.ExtractExtraPrefixes(conn.GetId(), ipContext.GetExcludedPrefixes(), requests...)
+		if err != nil {
+			return nil, err
+		}
+		connInfo = &connectionInfo{
+			prefixes: prefixes,
+		}
+		for _, r := range requests {
+			connInfo.requests = append(connInfo.requests, proto.Clone(r).(*networkservice.ExtraPrefixRequest))
+		}
+		storeConnInfo(ctx, connInfo)
+		addPrefixes(&ipContext.ExtraPrefixes, prefixes)
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		// metadata is deleted on the Request failure, so there will be no Close to release the prefixes
+		if _, loaded := loadConnInfo(ctx); loaded {
+			s.release(ctx, request.GetConnection().GetId())
+		}
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (s *extraPrefixServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	if _, ok := loadConnInfo(ctx); ok {
+		s.release(ctx, conn.GetId())
+	}
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func (s *extraPrefixServer) release(ctx context.Context, connID string) {
+	if err := s.prefixPool.Release(connID); err != nil {
+		logger.Log(ctx).WithField("extraPrefixServer", "release").Errorf("failed to release prefixes: %v %+v", connID, err)
+	}
+}
+
+func (i *connectionInfo) shouldUpdate(requests []*networkservice.ExtraPrefixRequest, excludedPrefixes []string) bool {
+	if len(requests) != len(i.requests) {
+		return true
+	}
+	for k := range requests {
+		if !proto.Equal(requests[k], i.requests[k]) {
+			return true
+		}
+	}
+
+	for _, excludedPrefix := range excludedPrefixes {
+		_, excludedNet, excludedErr := net.ParseCIDR(excludedPrefix)
+		if excludedErr != nil {
+			return true
+		}
+		for _, prefix := range i.prefixes {
+			_, prefixNet, prefixErr := net.ParseCIDR(prefix)
+			if prefixErr != nil || prefixNet.Contains(excludedNet.IP) || excludedNet.Contains(prefixNet.IP) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func deletePrefixes(prefixes *[]string, deleted []string) {
+	var left []string
+	for _, prefix := range *prefixes {
+		if !contains(deleted, prefix) {
+			left = append(left, prefix)
+		}
+	}
+	*prefixes = left
+}
+
+func addPrefixes(prefixes *[]string, added []string) {
+	for _, prefix := range added {
+		if !contains(*prefixes, prefix) {
+			*prefixes = append(*prefixes, prefix)
+		}
+	}
+}
+
+func contains(prefixes []string, prefix string) bool {
+	for _, p := range prefixes {
+		if p == prefix {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extraprefixipam_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatepath"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/extraprefixipam"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+	"github.com/networkservicemesh/sdk/pkg/tools/prefixpool"
+)
+
+func newServer(prefixPool *prefixpool.PrefixPool, additionalFunctionality ...networkservice.NetworkServiceServer) networkservice.NetworkServiceServer {
+	return next.NewNetworkServiceServer(
+		append([]networkservice.NetworkServiceServer{
+			updatepath.NewServer("ipam"),
+			metadata.NewServer(),
+			extraprefixipam.NewServer(prefixPool),
+		}, additionalFunctionality...)...,
+	)
+}
+
+func newRequest(excludedPrefixes ...string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Context: &networkservice.ConnectionContext{
+				IpContext: &networkservice.IPContext{
+					ExcludedPrefixes: excludedPrefixes,
+					ExtraPrefixRequest: []*networkservice.ExtraPrefixRequest{
+						{
+							AddrFamily:      &networkservice.IpFamily{Family: networkservice.IpFamily_IPV4},
+							RequiredNumber:  1,
+							RequestedNumber: 1,
+							PrefixLen:       26,
+						},
+						{
+							AddrFamily:      &networkservice.IpFamily{Family: networkservice.IpFamily_IPV6},
+							RequiredNumber:  1,
+							RequestedNumber: 1,
+							PrefixLen:       65,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestServer(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx := logger.WithLog(context.Background())
+
+	prefixPool, err := prefixpool.New("10.0.0.0/25", "fe80::/64")
+	require.NoError(t, err)
+
+	server := newServer(prefixPool)
+
+	conn1, err := server.Request(ctx, newRequest())
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.0/26", "fe80::/65"}, conn1.GetContext().GetIpContext().GetExtraPrefixes())
+
+	conn2, err := server.Request(ctx, newRequest())
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.64/26", "fe80::8000:0:0:0/65"}, conn2.GetContext().GetIpContext().GetExtraPrefixes())
+
+	_, err = server.Request(ctx, newRequest())
+	require.Error(t, err)
+
+	_, err = server.Close(ctx, conn1)
+	require.NoError(t, err)
+
+	conn3, err := server.Request(ctx, newRequest())
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.0/26", "fe80::/65"}, conn3.GetContext().GetIpContext().GetExtraPrefixes())
+}
+
+func TestServer_ExcludedPrefixes(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx := logger.WithLog(context.Background())
+
+	prefixPool, err := prefixpool.New("10.0.0.0/24", "fe80::/64")
+	require.NoError(t, err)
+
+	server := newServer(prefixPool)
+
+	conn, err := server.Request(ctx, newRequest("10.0.0.0/26"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.64/26", "fe80::/65"}, conn.GetContext().GetIpContext().GetExtraPrefixes())
+
+	// Refresh keeps the same prefixes
+	request := newRequest("10.0.0.0/26")
+	request.Connection = conn.Clone()
+	conn, err = server.Request(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.64/26", "fe80::/65"}, conn.GetContext().GetIpContext().GetExtraPrefixes())
+
+	// Granted prefixes become excluded
+	request.Connection = conn.Clone()
+	request.Connection.Context.IpContext.ExcludedPrefixes = []string{"10.0.0.0/25", "fe80::1/128"}
+	conn, err = server.Request(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.128/26", "fe80::8000:0:0:0/65"}, conn.GetContext().GetIpContext().GetExtraPrefixes())
+
+	_, err = server.Close(ctx, conn)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"10.0.0.0/24", "fe80::/64"}, prefixPool.GetPrefixes())
+}
+
+func TestServer_RequestFailed(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx := logger.WithLog(context.Background())
+
+	prefixPool, err := prefixpool.New("10.0.0.0/24", "fe80::/64")
+	require.NoError(t, err)
+
+	_, err = newServer(prefixPool, injecterror.NewServer()).Request(ctx, newRequest())
+	require.Error(t, err)
+	require.ElementsMatch(t, []string{"10.0.0.0/24", "fe80::/64"}, prefixPool.GetPrefixes())
+}
+
+func TestServer_NoExtraPrefixRequest(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx := logger.WithLog(context.Background())
+
+	prefixPool, err := prefixpool.New("10.0.0.0/24")
+	require.NoError(t, err)
+
+	conn, err := newServer(prefixPool).Request(ctx, new(networkservice.NetworkServiceRequest))
+	require.NoError(t, err)
+	require.Empty(t, conn.GetContext().GetIpContext().GetExtraPrefixes())
+	require.Equal(t, []string{"10.0.0.0/24"}, prefixPool.GetPrefixes())
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package point2pointipam
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/dualstack"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+)
+
+type restoredLease struct {
+	connInfos []dualstack.Allocation
+	expires   time.Time
+}
+
+// restoreLeases pulls the addresses of the stored leases from the IP pools, so they are not allocated for the other
+// connections until the leases are requested again or expire. Leases are restored only once, but if the lease store
+// fails to load, restore is retried on the next call.
+func (s *ipamServer) restoreLeases(ctx context.Context) error {
+	if s.leaseStore == nil || atomic.LoadInt32(&s.leasesRestored) == 1 {
+		return nil
+	}
+
+	s.restoreMu.Lock()
+	defer s.restoreMu.Unlock()
+
+	if atomic.LoadInt32(&s.leasesRestored) == 1 {
+		return nil
+	}
+
+	logEntry := logger.Log(ctx).WithField("ipamServer", "restoreLeases")
+
+	leases, err := s.leaseStore.Load(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to load leases")
+	}
+
+	now := clock.FromContext(ctx).Now()
+	for _, lease := range leases {
+		if isExpired(lease.Expires, now) {
+			s.deleteLease(ctx, lease.ConnectionID)
+			continue
+		}
+
+		connInfos := s.restoreConnInfos(lease)
+		if connInfos == nil {
+			logEntry.Warnf("lease addresses don't match the prefixes or are already allocated: %v", lease.ConnectionID)
+			s.deleteLease(ctx, lease.ConnectionID)
+			continue
+		}
+
+		s.restoredLeasesMu.Lock()
+		s.restoredLeases[lease.ConnectionID] = &restoredLease{
+			connInfos: connInfos,
+			expires:   lease.Expires,
+		}
+		s.restoredLeasesMu.Unlock()
+		logEntry.Infof("lease restored: %v", lease.ConnectionID)
+	}
+
+	atomic.StoreInt32(&s.leasesRestored, 1)
+
+	return nil
+}
+
+// restoreConnInfos returns nil if none of the lease addresses can be restored
+func (s *ipamServer) restoreConnInfos(lease *Lease) []dualstack.Allocation {
+	var restored bool
+	connInfos := make([]dualstack.Allocation, len(s.ipPools))
+	for _, addrs := range lease.Addrs {
+		srcIP, _, srcErr := net.ParseCIDR(addrs.SrcAddr)
+		dstIP, _, dstErr := net.ParseCIDR(addrs.DstAddr)
+		if srcErr != nil || dstErr != nil {
+			continue
+		}
+
+		for i, ipNets := range s.ipNets {
+			for j, ipNet := range ipNets {
+				if connInfos[i] != nil || !ipNet.Contains(srcIP) || !ipNet.Contains(dstIP) {
+					continue
+				}
+
+				ipPool := s.ipPools[i][j]
+				if err := ipPool.PullIP(srcIP); err != nil {
+					continue
+				}
+				if err := ipPool.PullIP(dstIP); err != nil {
+					ipPool.Add(srcIP)
+					continue
+				}
+
+				connInfos[i] = &connectionInfo{
+					ipPool:  ipPool,
+					srcAddr: addrs.SrcAddr,
+					dstAddr: addrs.DstAddr,
+				}
+				restored = true
+			}
+		}
+	}
+
+	if !restored {
+		return nil
+	}
+	return connInfos
+}
+
+// reclaimExpiredLeases frees the addresses of the restored leases not requested again before their expiration
+func (s *ipamServer) reclaimExpiredLeases(ctx context.Context) {
+	if s.leaseStore == nil {
+		return
+	}
+
+	now := clock.FromContext(ctx).Now()
+
+	s.restoredLeasesMu.Lock()
+	defer s.restoredLeasesMu.Unlock()
+
+	for connID, lease := range s.restoredLeases {
+		if isExpired(lease.expires, now) {
+			delete(s.restoredLeases, connID)
+			lease.free()
+			s.deleteLease(ctx, connID)
+			logger.Log(ctx).WithField("ipamServer", "reclaimExpiredLeases").Infof("lease reclaimed: %v", connID)
+		}
+	}
+}
+
+func (s *ipamServer) loadRestoredLease(connID string) *restoredLease {
+	s.restoredLeasesMu.Lock()
+	defer s.restoredLeasesMu.Unlock()
+
+	lease, ok := s.restoredLeases[connID]
+	if !ok {
+		return nil
+	}
+	delete(s.restoredLeases, connID)
+
+	return lease
+}
+
+func (s *ipamServer) storeRestoredLease(connID string, lease *restoredLease) {
+	if lease == nil {
+		return
+	}
+	for _, connInfo := range lease.connInfos {
+		if connInfo != nil {
+			s.restoredLeasesMu.Lock()
+			s.restoredLeases[connID] = lease
+			s.restoredLeasesMu.Unlock()
+			return
+		}
+	}
+}
+
+func (s *ipamServer) saveLease(ctx context.Context, conn *networkservice.Connection, connInfos []dualstack.Allocation) {
+	if s.leaseStore == nil {
+		return
+	}
+
+	lease := &Lease{
+		ConnectionID: conn.GetId(),
+	}
+	for _, connInfo := range connInfos {
+		if connInfo != nil {
+			lease.Addrs = append(lease.Addrs, &LeaseAddrs{
+				SrcAddr: connInfo.SrcAddr(),
+				DstAddr: connInfo.DstAddr(),
+			})
+		}
+	}
+	if expires := conn.GetCurrentPathSegment().GetExpires(); expires != nil {
+		if expireTime, err := ptypes.Timestamp(expires); err == nil {
+			lease.Expires = expireTime
+		}
+	}
+
+	if err := s.leaseStore.Save(lease); err != nil {
+		logger.Log(ctx).WithField("ipamServer", "saveLease").Errorf("failed to save lease: %v %+v", conn.GetId(), err)
+	}
+}
+
+func (s *ipamServer) deleteLease(ctx context.Context, connID string) {
+	if s.leaseStore == nil {
+		return
+	}
+
+	if err := s.leaseStore.Delete(connID); err != nil {
+		logger.Log(ctx).WithField("ipamServer", "deleteLease").Errorf("failed to delete lease: %v %+v", connID, err)
+	}
+}
+
+func (l *restoredLease) free() {
+	for _, connInfo := range l.connInfos {
+		if connInfo != nil {
+			connInfo.Free()
+		}
+	}
+}
+
+func isExpired(expires, now time.Time) bool {
+	return !expires.IsZero() && !now.Before(expires)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package point2pointipam
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/filestore"
+)
+
+// Lease is a set of the addresses allocated for the connection
+type Lease struct {
+	// ConnectionID - ID of the connection the addresses are allocated for
+	ConnectionID string `json:"connection_id"`
+	// Addrs - allocated address pairs, one for each IP family
+	Addrs []*LeaseAddrs `json:"addrs"`
+	// Expires - time after which the lease can be reclaimed if the connection is not requested again, zero time
+	// means that the lease never expires
+	Expires time.Time `json:"expires"`
+}
+
+// LeaseAddrs is a pair of the addresses allocated for the connection
+type LeaseAddrs struct {
+	SrcAddr string `json:"src_addr"`
+	DstAddr string `json:"dst_addr"`
+}
+
+// LeaseStore is a storage for the IPAM leases
+type LeaseStore interface {
+	// Save stores the lease, replacing the previously stored one with the same connection ID
+	Save(lease *Lease) error
+	// Delete removes the lease for the connection ID, does nothing if there is no such lease
+	Delete(connID string) error
+	// Load returns all the stored leases, the leases failed to decode are logged and removed from the store
+	Load(ctx context.Context) ([]*Lease, error)
+}
+
+type fileLeaseStore struct {
+	files *filestore.Store
+}
+
+// NewFileLeaseStore - creates a new LeaseStore keeping the leases as separate JSON files in dir
+//             dir - directory to store files in, is created if it doesn't exist
+func NewFileLeaseStore(dir string) (LeaseStore, error) {
+	files, err := filestore.New(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &fileLeaseStore{
+		files: files,
+	}, nil
+}
+
+func (s *fileLeaseStore) Save(lease *Lease) error {
+	data, err := json.Marshal(lease)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal lease: %s", lease.ConnectionID)
+	}
+	return s.files.Save(lease.ConnectionID, data)
+}
+
+func (s *fileLeaseStore) Delete(connID string) error {
+	return s.files.Delete(connID)
+}
+
+func (s *fileLeaseStore) Load(ctx context.Context) ([]*Lease, error) {
+	var leases []*Lease
+	err := s.files.Load(ctx, func(data []byte) error {
+		lease := new(Lease)
+		if err := json.Unmarshal(data, lease); err != nil {
+			return err
+		}
+		leases = append(leases, lease)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return leases, nil
+}
@@ -19,18 +19,19 @@ package point2pointipam
 import (
 	"context"
 
+	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/dualstack"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
 )
 
 type keyType struct{}
 
-func storeConnInfo(ctx context.Context, connInfo *connectionInfo) {
-	metadata.Map(ctx, false).Store(keyType{}, connInfo)
+func storeConnInfos(ctx context.Context, connInfos []dualstack.Allocation) {
+	metadata.Map(ctx, false).Store(keyType{}, connInfos)
 }
 
-func loadConnInfo(ctx context.Context) (*connectionInfo, bool) {
+func loadConnInfos(ctx context.Context) ([]dualstack.Allocation, bool) {
 	if raw, ok := metadata.Map(ctx, false).Load(keyType{}); ok {
-		return raw.(*connectionInfo), true
+		return raw.([]dualstack.Allocation), true
 	}
 	return nil, false
 }
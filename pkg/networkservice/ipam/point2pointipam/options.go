@@ -0,0 +1,53 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package point2pointipam
+
+// StaticAddrPolicy defines IPAM behavior when the source address requested by the client can't be granted
+type StaticAddrPolicy int
+
+const (
+	// StaticAddrFallback - allocate some other address, default policy
+	StaticAddrFallback StaticAddrPolicy = iota
+	// StaticAddrStrict - fail the Request
+	StaticAddrStrict
+)
+
+type serverOptions struct {
+	leaseStore       LeaseStore
+	staticAddrPolicy StaticAddrPolicy
+}
+
+// Option is an option pattern for NewServerWithOptions
+type Option func(o *serverOptions)
+
+// WithLeaseStore sets the store to keep the allocated addresses in, so they survive the restart: the connection
+// requested again after the restart gets the same addresses. Leases not requested again are reclaimed after they
+// expire.
+func WithLeaseStore(leaseStore LeaseStore) Option {
+	return func(o *serverOptions) {
+		o.leaseStore = leaseStore
+	}
+}
+
+// WithStaticAddrPolicy sets the policy for the case when the source address requested by the client can't be granted:
+// it is out of the prefixes, is excluded or is already allocated for the other connection. Default is
+// StaticAddrFallback.
+func WithStaticAddrPolicy(policy StaticAddrPolicy) Option {
+	return func(o *serverOptions) {
+		o.staticAddrPolicy = policy
+	}
+}
@@ -22,51 +22,94 @@ import (
 	"net"
 	"sync"
 
-	"github.com/RoaringBitmap/roaring"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/pkg/errors"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/dualstack"
+	"github.com/networkservicemesh/sdk/pkg/tools/ippool"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+)
+
+const (
+	// SecondarySrcIPAddrKey - same as dualstack.SecondarySrcIPAddrKey
+	SecondarySrcIPAddrKey = dualstack.SecondarySrcIPAddrKey
+	// SecondaryDstIPAddrKey - same as dualstack.SecondaryDstIPAddrKey
+	SecondaryDstIPAddrKey = dualstack.SecondaryDstIPAddrKey
+	// RequestedSrcIPAddrLabel - Connection.Labels key for the source address requested by the client, it takes
+	// precedence over the IPContext.SrcIpAddr requested on the first Request
+	RequestedSrcIPAddrLabel = "requested_src_ip_addr"
 )
 
 type ipamServer struct {
-	ipPools  []*ipPool
-	prefixes []*net.IPNet
-	once     sync.Once
-	initErr  error
+	// ipPools are grouped by the IP family, the family of the first prefix goes first
+	ipPools [][]*ippool.IPPool
+	// ipNets are the prefixes of the ipPools
+	ipNets           [][]*net.IPNet
+	prefixes         []*net.IPNet
+	leaseStore       LeaseStore
+	staticAddrPolicy StaticAddrPolicy
+	// restoredLeases are the leases loaded from the leaseStore and not yet requested again
+	restoredLeases   map[string]*restoredLease
+	restoredLeasesMu sync.Mutex
+	leasesRestored   int32
+	restoreMu        sync.Mutex
+	once             sync.Once
+	initErr          error
 }
 
 type connectionInfo struct {
-	ipPool  *ipPool
+	ipPool  *ippool.IPPool
 	srcAddr string
 	dstAddr string
 }
 
-func (i *connectionInfo) shouldUpdate(exclude *roaring.Bitmap) bool {
-	return exclude.Contains(addrToInt(i.srcAddr)) || exclude.Contains(addrToInt(i.dstAddr))
+// NewServer - creates a new NetworkServiceServer chain element that implements IPAM service. Prefixes can be both
+//             IPv4 and IPv6, if there are prefixes of the both families, a pair of addresses of each family is
+//             assigned to the connection. The pair of the first prefix family is set to the IPContext, the other one
+//             is set to the Connection.Context.ExtraContext with the SecondarySrcIPAddrKey, SecondaryDstIPAddrKey keys.
+//             Routes are set only for the first prefix family addresses configured on the interface.
+//             Client can request a static source address with the RequestedSrcIPAddrLabel connection label or with the
+//             IPContext.SrcIpAddr (Connection.Context.ExtraContext SecondarySrcIPAddrKey for the second IP family).
+//             The address is granted if it is in the prefixes, is not excluded and is not allocated for the other
+//             connection, otherwise IPAM acts according to the StaticAddrPolicy.
+//             prefixes - prefixes to allocate the addresses from
+func NewServer(prefixes ...*net.IPNet) networkservice.NetworkServiceServer {
+	return NewServerWithOptions(prefixes)
 }
 
-// NewServer - creates a new NetworkServiceServer chain element that implements IPAM service.
-func NewServer(prefixes ...*net.IPNet) networkservice.NetworkServiceServer {
+// NewServerWithOptions - same as NewServer, but configured with options
+//             options - IPAM options: lease store, static address policy
+func NewServerWithOptions(prefixes []*net.IPNet, options ...Option) networkservice.NetworkServiceServer {
+	o := new(serverOptions)
+	for _, opt := range options {
+		opt(o)
+	}
+
 	return &ipamServer{
-		prefixes: prefixes,
+		prefixes:         prefixes,
+		leaseStore:       o.leaseStore,
+		staticAddrPolicy: o.staticAddrPolicy,
+		restoredLeases:   make(map[string]*restoredLease),
 	}
 }
 
 func (s *ipamServer) init() {
-	if len(s.prefixes) == 0 {
-		s.initErr = errors.New("required one or more prefixes")
+	ipNets, err := dualstack.GroupByFamily(s.prefixes)
+	if err != nil {
+		s.initErr = err
 		return
 	}
 
-	for _, prefix := range s.prefixes {
-		if prefix == nil {
-			s.initErr = errors.Errorf("prefix must not be nil: %+v", s.prefixes)
-			return
+	s.ipNets = ipNets
+	for _, familyNets := range ipNets {
+		var ipPools []*ippool.IPPool
+		for _, ipNet := range familyNets {
+			ipPools = append(ipPools, ippool.NewWithNet(ipNet))
 		}
-		s.ipPools = append(s.ipPools, newIPPool(prefix))
+		s.ipPools = append(s.ipPools, ipPools)
 	}
 }
 
@@ -75,77 +118,121 @@ func (s *ipamServer) Request(ctx context.Context, request *networkservice.Networ
 	if s.initErr != nil {
 		return nil, s.initErr
 	}
+	if err := s.restoreLeases(ctx); err != nil {
+		return nil, err
+	}
+
+	s.reclaimExpiredLeases(ctx)
 
 	conn := request.GetConnection()
-	if conn.GetContext() == nil {
-		conn.Context = &networkservice.ConnectionContext{}
-	}
-	if conn.GetContext().GetIpContext() == nil {
-		conn.GetContext().IpContext = &networkservice.IPContext{}
+
+	connInfos, ok := loadConnInfos(ctx)
+	var lease *restoredLease
+	if !ok {
+		if lease = s.loadRestoredLease(conn.GetId()); lease != nil {
+			connInfos = lease.connInfos
+		} else {
+			connInfos = make([]dualstack.Allocation, len(s.ipPools))
+		}
 	}
-	ipContext := conn.GetContext().GetIpContext()
 
-	exclude, err := exclude(ipContext.GetExcludedPrefixes()...)
+	requestedSrcIPs := s.requestedSrcIPs(conn, connInfos)
+
+	err := dualstack.Allocate(conn, connInfos, func(i int, current dualstack.Allocation, exclude *ippool.IPPool) (dualstack.Allocation, error) {
+		if srcIP := requestedSrcIPs[i]; srcIP != nil && (current == nil || !dualstack.ParseAddr(current.SrcAddr()).Equal(srcIP)) {
+			connInfo, staticErr := getP2PAddrsWithSrc(s.ipPools[i], exclude, srcIP)
+			switch {
+			case staticErr == nil:
+				return connInfo, nil
+			case s.staticAddrPolicy == StaticAddrStrict:
+				return nil, staticErr
+			}
+		}
+		if current != nil {
+			return current, nil
+		}
+		return getP2PAddrs(s.ipPools[i], exclude)
+	})
 	if err != nil {
+		s.storeRestoredLease(conn.GetId(), lease)
 		return nil, err
 	}
+	storeConnInfos(ctx, connInfos)
 
-	connInfo, ok := loadConnInfo(ctx)
-	if ok && connInfo.shouldUpdate(exclude) {
-		// some of the existing addresses are excluded
-		deleteRoute(&ipContext.SrcRoutes, connInfo.dstAddr)
-		deleteRoute(&ipContext.DstRoutes, connInfo.srcAddr)
-		s.free(connInfo)
-		ok = false
-	}
-	if !ok {
-		if connInfo, err = s.getP2PAddrs(exclude); err != nil {
-			return nil, err
-		}
-		storeConnInfo(ctx, connInfo)
+	conn, err = next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		// the restored addresses should be still reclaimed on expiration
+		s.storeRestoredLease(request.GetConnection().GetId(), lease)
+		return nil, err
 	}
 
-	ipContext.SrcIpAddr = connInfo.srcAddr
-	addRoute(&ipContext.SrcRoutes, connInfo.dstAddr)
-
-	ipContext.DstIpAddr = connInfo.dstAddr
-	addRoute(&ipContext.DstRoutes, connInfo.srcAddr)
+	s.saveLease(ctx, conn, connInfos)
 
-	return next.Server(ctx).Request(ctx, request)
+	return conn, nil
 }
 
-func (s *ipamServer) getP2PAddrs(exclude *roaring.Bitmap) (connInfo *connectionInfo, err error) {
-	var dstAddr, srcAddr string
-	for _, ipPool := range s.ipPools {
-		if dstAddr, srcAddr, err = ipPool.getP2PAddrs(exclude); err == nil {
+func getP2PAddrs(ipPools []*ippool.IPPool, exclude *ippool.IPPool) (dualstack.Allocation, error) {
+	var err error
+	for _, ipPool := range ipPools {
+		var dstIP, srcIP net.IP
+		if dstIP, srcIP, err = ipPool.PullP2PAddrs(exclude); err == nil {
 			return &connectionInfo{
 				ipPool:  ipPool,
-				srcAddr: srcAddr,
-				dstAddr: dstAddr,
+				srcAddr: dualstack.HostAddr(srcIP),
+				dstAddr: dualstack.HostAddr(dstIP),
 			}, nil
 		}
 	}
 	return nil, err
 }
 
-func deleteRoute(routes *[]*networkservice.Route, prefix string) {
-	for i, route := range *routes {
-		if route.Prefix == prefix {
-			*routes = append((*routes)[:i], (*routes)[i+1:]...)
-			return
+// getP2PAddrsWithSrc allocates the pair with the given source address
+func getP2PAddrsWithSrc(ipPools []*ippool.IPPool, exclude *ippool.IPPool, srcIP net.IP) (dualstack.Allocation, error) {
+	if exclude.Contains(srcIP) {
+		return nil, errors.Errorf("requested source address is excluded: %v", srcIP)
+	}
+	for _, ipPool := range ipPools {
+		if ipPool.PullIP(srcIP) != nil {
+			continue
 		}
+		dstIP, err := ipPool.Pull(exclude)
+		if err != nil {
+			ipPool.Add(srcIP)
+			return nil, err
+		}
+		return &connectionInfo{
+			ipPool:  ipPool,
+			srcAddr: dualstack.HostAddr(srcIP),
+			dstAddr: dualstack.HostAddr(dstIP),
+		}, nil
 	}
+	return nil, errors.Errorf("requested source address is out of the prefixes or is already allocated: %v", srcIP)
 }
 
-func addRoute(routes *[]*networkservice.Route, prefix string) {
-	for _, route := range *routes {
-		if route.Prefix == prefix {
-			return
+// requestedSrcIPs returns the source addresses requested by the client for each IP family, nil if not requested. The
+// IPContext addresses are requested only for the families with no current allocation: on refresh they are the addresses
+// already allocated for the connection, not the client requests.
+func (s *ipamServer) requestedSrcIPs(conn *networkservice.Connection, connInfos []dualstack.Allocation) []net.IP {
+	srcIPs := make([]net.IP, len(s.ipPools))
+	for _, requested := range []struct {
+		addr     string
+		explicit bool
+	}{
+		{conn.GetLabels()[RequestedSrcIPAddrLabel], true},
+		{conn.GetContext().GetIpContext().GetSrcIpAddr(), false},
+		{conn.GetContext().GetExtraContext()[SecondarySrcIPAddrKey], false},
+	} {
+		ip := dualstack.ParseAddr(requested.addr)
+		if ip == nil {
+			continue
+		}
+		for i, ipNets := range s.ipNets {
+			if srcIPs[i] == nil && (requested.explicit || connInfos[i] == nil) && (ipNets[0].IP.To4() != nil) == (ip.To4() != nil) {
+				srcIPs[i] = ip
+			}
 		}
 	}
-	*routes = append(*routes, &networkservice.Route{
-		Prefix: prefix,
-	})
+	return srcIPs
 }
 
 func (s *ipamServer) Close(ctx context.Context, conn *networkservice.Connection) (_ *empty.Empty, err error) {
@@ -153,15 +240,51 @@ func (s *ipamServer) Close(ctx context.Context, conn *networkservice.Connection)
 	if s.initErr != nil {
 		return nil, s.initErr
 	}
+	if restoreErr := s.restoreLeases(ctx); restoreErr != nil {
+		logger.Log(ctx).WithField("ipamServer", "Close").Errorf("%+v", restoreErr)
+	}
 
-	if connInfo, ok := loadConnInfo(ctx); ok {
-		s.free(connInfo)
+	if connInfos, ok := loadConnInfos(ctx); ok {
+		for _, connInfo := range connInfos {
+			if connInfo != nil {
+				connInfo.Free()
+			}
+		}
+	} else if lease := s.loadRestoredLease(conn.GetId()); lease != nil {
+		lease.free()
 	}
+	s.deleteLease(ctx, conn.GetId())
 
 	return next.Server(ctx).Close(ctx, conn)
 }
 
-func (s *ipamServer) free(connInfo *connectionInfo) {
-	connInfo.ipPool.freeAddrs(connInfo.srcAddr)
-	connInfo.ipPool.freeAddrs(connInfo.dstAddr)
+func (i *connectionInfo) SrcAddr() string {
+	return i.srcAddr
+}
+
+func (i *connectionInfo) DstAddr() string {
+	return i.dstAddr
+}
+
+func (i *connectionInfo) SrcRoute() string {
+	return i.dstAddr
+}
+
+func (i *connectionInfo) DstRoute() string {
+	return i.srcAddr
+}
+
+func (i *connectionInfo) IsExcluded(exclude *ippool.IPPool) bool {
+	srcIP, _, srcErr := net.ParseCIDR(i.srcAddr)
+	dstIP, _, dstErr := net.ParseCIDR(i.dstAddr)
+
+	return srcErr != nil || dstErr != nil || exclude.Contains(srcIP) || exclude.Contains(dstIP)
+}
+
+func (i *connectionInfo) Free() {
+	for _, addr := range []string{i.srcAddr, i.dstAddr} {
+		if ip, _, err := net.ParseCIDR(addr); err == nil {
+			i.ipPool.Add(ip)
+		}
+	}
 }
@@ -18,9 +18,14 @@ package point2pointipam_test
 
 import (
 	"context"
+	"io/ioutil"
 	"net"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
@@ -29,6 +34,9 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/point2pointipam"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
 )
 
 func newIpamServer(prefixes ...*net.IPNet) networkservice.NetworkServiceServer {
@@ -185,3 +193,424 @@ func TestRefreshRequest(t *testing.T) {
 	require.NoError(t, err)
 	validateConn(t, conn, "192.168.0.4/32", "192.168.0.5/32")
 }
+
+func TestServer_IPv6(t *testing.T) {
+	_, ipNet, err := net.ParseCIDR("fe80::/64")
+	require.NoError(t, err)
+
+	srv := newIpamServer(ipNet)
+
+	conn1, err := srv.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	validateConn(t, conn1, "fe80::/128", "fe80::1/128")
+
+	req2 := newRequest()
+	req2.Connection.Context.IpContext.ExcludedPrefixes = []string{"fe80::2/127", "fe80::5/128"}
+	conn2, err := srv.Request(context.Background(), req2)
+	require.NoError(t, err)
+	validateConn(t, conn2, "fe80::4/128", "fe80::6/128")
+
+	_, err = srv.Close(context.Background(), conn1)
+	require.NoError(t, err)
+
+	conn3, err := srv.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	validateConn(t, conn3, "fe80::/128", "fe80::1/128")
+}
+
+func TestOutOfIPs_IPv6(t *testing.T) {
+	_, ipNet, err := net.ParseCIDR("fe80::1:0/120")
+	require.NoError(t, err)
+
+	srv := newIpamServer(ipNet)
+
+	req := newRequest()
+	req.Connection.Context.IpContext.ExcludedPrefixes = []string{"fe80::1:0/121", "fe80::1:80/122", "fe80::1:c0/123", "fe80::1:e0/124", "fe80::1:f0/125"}
+	for i := 0; i < 4; i++ {
+		_, err = srv.Request(context.Background(), req.Clone())
+		require.NoError(t, err)
+	}
+
+	_, err = srv.Request(context.Background(), req.Clone())
+	require.Error(t, err)
+}
+
+func TestServer_DualStack(t *testing.T) {
+	_, ipv4Net, err := net.ParseCIDR("192.168.0.0/16")
+	require.NoError(t, err)
+	_, ipv6Net, err := net.ParseCIDR("fe80::/64")
+	require.NoError(t, err)
+
+	srv := newIpamServer(ipv4Net, ipv6Net)
+
+	conn, err := srv.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+
+	require.Equal(t, "192.168.0.0/32", conn.Context.IpContext.DstIpAddr)
+	require.Equal(t, "192.168.0.1/32", conn.Context.IpContext.SrcIpAddr)
+	require.Equal(t, "fe80::/128", conn.Context.ExtraContext[point2pointipam.SecondaryDstIPAddrKey])
+	require.Equal(t, "fe80::1/128", conn.Context.ExtraContext[point2pointipam.SecondarySrcIPAddrKey])
+	// Routes are set only for the primary IP family
+	require.Equal(t, []*networkservice.Route{{Prefix: "192.168.0.1/32"}}, conn.Context.IpContext.DstRoutes)
+	require.Equal(t, []*networkservice.Route{{Prefix: "192.168.0.0/32"}}, conn.Context.IpContext.SrcRoutes)
+
+	// Only IPv6 addresses are excluded, so only they are changed
+	req := newRequest()
+	req.Connection = conn.Clone()
+	req.Connection.Context.IpContext.ExcludedPrefixes = []string{"fe80::/127"}
+	conn, err = srv.Request(context.Background(), req)
+	require.NoError(t, err)
+
+	require.Equal(t, "192.168.0.0/32", conn.Context.IpContext.DstIpAddr)
+	require.Equal(t, "192.168.0.1/32", conn.Context.IpContext.SrcIpAddr)
+	require.Equal(t, "fe80::2/128", conn.Context.ExtraContext[point2pointipam.SecondaryDstIPAddrKey])
+	require.Equal(t, "fe80::3/128", conn.Context.ExtraContext[point2pointipam.SecondarySrcIPAddrKey])
+	require.Equal(t, []*networkservice.Route{{Prefix: "192.168.0.1/32"}}, conn.Context.IpContext.DstRoutes)
+	require.Equal(t, []*networkservice.Route{{Prefix: "192.168.0.0/32"}}, conn.Context.IpContext.SrcRoutes)
+}
+
+func TestServer_DualStack_IPv6First(t *testing.T) {
+	_, ipv4Net, err := net.ParseCIDR("192.168.0.0/16")
+	require.NoError(t, err)
+	_, ipv6Net, err := net.ParseCIDR("fe80::/64")
+	require.NoError(t, err)
+
+	srv := newIpamServer(ipv6Net, ipv4Net)
+
+	conn, err := srv.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+
+	require.Equal(t, "fe80::/128", conn.Context.IpContext.DstIpAddr)
+	require.Equal(t, "fe80::1/128", conn.Context.IpContext.SrcIpAddr)
+	require.Equal(t, "192.168.0.0/32", conn.Context.ExtraContext[point2pointipam.SecondaryDstIPAddrKey])
+	require.Equal(t, "192.168.0.1/32", conn.Context.ExtraContext[point2pointipam.SecondarySrcIPAddrKey])
+
+	// IPv6 addresses are freed if IPv4 addresses can't be allocated
+	req := newRequest()
+	req.Connection.Context.IpContext.ExcludedPrefixes = []string{"192.168.0.0/16"}
+	_, err = srv.Request(context.Background(), req)
+	require.Error(t, err)
+
+	conn, err = srv.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	require.Equal(t, "fe80::2/128", conn.Context.IpContext.DstIpAddr)
+	require.Equal(t, "fe80::3/128", conn.Context.IpContext.SrcIpAddr)
+	require.Equal(t, "192.168.0.2/32", conn.Context.ExtraContext[point2pointipam.SecondaryDstIPAddrKey])
+	require.Equal(t, "192.168.0.3/32", conn.Context.ExtraContext[point2pointipam.SecondarySrcIPAddrKey])
+}
+
+func newLeaseRequest(t *testing.T, connID string, expires time.Time) *networkservice.NetworkServiceRequest {
+	ts, err := ptypes.TimestampProto(expires)
+	require.NoError(t, err)
+
+	request := newRequest()
+	request.Connection.Id = connID
+	request.Connection.Path = &networkservice.Path{
+		PathSegments: []*networkservice.PathSegment{{
+			Name:    "ipam",
+			Id:      connID,
+			Expires: ts,
+		}},
+	}
+	return request
+}
+
+func TestServer_LeaseStore(t *testing.T) {
+	_, ipNet, err := net.ParseCIDR("192.168.0.0/16")
+	require.NoError(t, err)
+
+	clockMock := clockmock.NewMock()
+	ctx := logger.WithLog(clock.WithClock(context.Background(), clockMock))
+
+	dir := t.TempDir()
+	newServer := func() networkservice.NetworkServiceServer {
+		leaseStore, storeErr := point2pointipam.NewFileLeaseStore(dir)
+		require.NoError(t, storeErr)
+
+		return next.NewNetworkServiceServer(
+			metadata.NewServer(),
+			point2pointipam.NewServerWithOptions([]*net.IPNet{ipNet}, point2pointipam.WithLeaseStore(leaseStore)),
+		)
+	}
+
+	srv := newServer()
+
+	expires := clockMock.Now().Add(time.Hour)
+	_, err = srv.Request(ctx, newLeaseRequest(t, "conn-1", expires))
+	require.NoError(t, err)
+	conn2, err := srv.Request(ctx, newLeaseRequest(t, "conn-2", expires))
+	require.NoError(t, err)
+	validateConn(t, conn2, "192.168.0.2/32", "192.168.0.3/32")
+
+	_, err = srv.Close(ctx, conn2)
+	require.NoError(t, err)
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	// Restart
+	srv = newServer()
+
+	conn3, err := srv.Request(ctx, newLeaseRequest(t, "conn-3", expires))
+	require.NoError(t, err)
+	validateConn(t, conn3, "192.168.0.2/32", "192.168.0.3/32")
+
+	conn1, err := srv.Request(ctx, newLeaseRequest(t, "conn-1", expires))
+	require.NoError(t, err)
+	validateConn(t, conn1, "192.168.0.0/32", "192.168.0.1/32")
+}
+
+func TestServer_LeaseStore_Expire(t *testing.T) {
+	_, ipNet, err := net.ParseCIDR("192.168.0.0/16")
+	require.NoError(t, err)
+
+	clockMock := clockmock.NewMock()
+	ctx := logger.WithLog(clock.WithClock(context.Background(), clockMock))
+
+	dir := t.TempDir()
+	newServer := func() networkservice.NetworkServiceServer {
+		leaseStore, storeErr := point2pointipam.NewFileLeaseStore(dir)
+		require.NoError(t, storeErr)
+
+		return next.NewNetworkServiceServer(
+			metadata.NewServer(),
+			point2pointipam.NewServerWithOptions([]*net.IPNet{ipNet}, point2pointipam.WithLeaseStore(leaseStore)),
+		)
+	}
+
+	srv := newServer()
+
+	_, err = srv.Request(ctx, newLeaseRequest(t, "conn-1", clockMock.Now().Add(time.Hour)))
+	require.NoError(t, err)
+
+	// Restart
+	srv = newServer()
+
+	conn2, err := srv.Request(ctx, newLeaseRequest(t, "conn-2", clockMock.Now().Add(2*time.Hour)))
+	require.NoError(t, err)
+	validateConn(t, conn2, "192.168.0.2/32", "192.168.0.3/32")
+
+	clockMock.Add(time.Hour)
+
+	conn3, err := srv.Request(ctx, newLeaseRequest(t, "conn-3", clockMock.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	validateConn(t, conn3, "192.168.0.0/32", "192.168.0.1/32")
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+}
+
+func TestServer_LeaseStore_BadFiles(t *testing.T) {
+	_, ipNet, err := net.ParseCIDR("192.168.0.0/16")
+	require.NoError(t, err)
+
+	ctx := logger.WithLog(context.Background())
+
+	dir := t.TempDir()
+	leaseStore, err := point2pointipam.NewFileLeaseStore(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, leaseStore.Save(&point2pointipam.Lease{
+		ConnectionID: "conn-1",
+		Addrs: []*point2pointipam.LeaseAddrs{{
+			SrcAddr: "192.168.0.1/32",
+			DstAddr: "192.168.0.0/32",
+		}},
+	}))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "bad.json"), []byte("{"), 0o600))
+
+	srv := next.NewNetworkServiceServer(
+		metadata.NewServer(),
+		point2pointipam.NewServerWithOptions([]*net.IPNet{ipNet}, point2pointipam.WithLeaseStore(leaseStore)),
+	)
+
+	// Bad lease file is skipped, the good one is restored
+	conn2, err := srv.Request(ctx, newLeaseRequest(t, "conn-2", time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	validateConn(t, conn2, "192.168.0.2/32", "192.168.0.3/32")
+}
+
+type failLoadLeaseStore struct {
+	point2pointipam.LeaseStore
+	failures int
+}
+
+func (s *failLoadLeaseStore) Load(ctx context.Context) ([]*point2pointipam.Lease, error) {
+	if s.failures > 0 {
+		s.failures--
+		return nil, errors.New("load failed")
+	}
+	return s.LeaseStore.Load(ctx)
+}
+
+func TestServer_LeaseStore_LoadRetry(t *testing.T) {
+	_, ipNet, err := net.ParseCIDR("192.168.0.0/16")
+	require.NoError(t, err)
+
+	ctx := logger.WithLog(context.Background())
+
+	fileLeaseStore, err := point2pointipam.NewFileLeaseStore(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, fileLeaseStore.Save(&point2pointipam.Lease{
+		ConnectionID: "conn-1",
+		Addrs: []*point2pointipam.LeaseAddrs{{
+			SrcAddr: "192.168.0.1/32",
+			DstAddr: "192.168.0.0/32",
+		}},
+	}))
+
+	srv := next.NewNetworkServiceServer(
+		metadata.NewServer(),
+		point2pointipam.NewServerWithOptions([]*net.IPNet{ipNet}, point2pointipam.WithLeaseStore(&failLoadLeaseStore{
+			LeaseStore: fileLeaseStore,
+			failures:   1,
+		})),
+	)
+
+	_, err = srv.Request(ctx, newLeaseRequest(t, "conn-2", time.Now().Add(time.Hour)))
+	require.Error(t, err)
+
+	// Leases are loaded on the next Request
+	conn2, err := srv.Request(ctx, newLeaseRequest(t, "conn-2", time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+	validateConn(t, conn2, "192.168.0.2/32", "192.168.0.3/32")
+}
+
+func TestServer_StaticAddr(t *testing.T) {
+	_, ipNet, err := net.ParseCIDR("192.168.0.0/16")
+	require.NoError(t, err)
+
+	srv := newIpamServer(ipNet)
+
+	req1 := newRequest()
+	req1.Connection.Context.IpContext.SrcIpAddr = "192.168.0.10/32"
+	conn1, err := srv.Request(context.Background(), req1)
+	require.NoError(t, err)
+	validateConn(t, conn1, "192.168.0.0/32", "192.168.0.10/32")
+
+	// Label takes precedence over the IPContext
+	req2 := newRequest()
+	req2.Connection.Labels = map[string]string{point2pointipam.RequestedSrcIPAddrLabel: "192.168.0.20"}
+	req2.Connection.Context.IpContext.SrcIpAddr = "192.168.0.30/32"
+	conn2, err := srv.Request(context.Background(), req2)
+	require.NoError(t, err)
+	validateConn(t, conn2, "192.168.0.1/32", "192.168.0.20/32")
+
+	// Already allocated address
+	req3 := newRequest()
+	req3.Connection.Context.IpContext.SrcIpAddr = "192.168.0.10/32"
+	conn3, err := srv.Request(context.Background(), req3)
+	require.NoError(t, err)
+	validateConn(t, conn3, "192.168.0.2/32", "192.168.0.3/32")
+
+	// Out of the prefixes address
+	req4 := newRequest()
+	req4.Connection.Context.IpContext.SrcIpAddr = "10.0.0.1/32"
+	conn4, err := srv.Request(context.Background(), req4)
+	require.NoError(t, err)
+	validateConn(t, conn4, "192.168.0.4/32", "192.168.0.5/32")
+
+	// Refresh with the changed IPContext address keeps the allocated one
+	req1.Connection = conn1.Clone()
+	req1.Connection.Context.IpContext.SrcIpAddr = "192.168.0.40/32"
+	conn1, err = srv.Request(context.Background(), req1)
+	require.NoError(t, err)
+	validateConn(t, conn1, "192.168.0.0/32", "192.168.0.10/32")
+
+	// Refresh with the changed requested address
+	req1.Connection = conn1.Clone()
+	req1.Connection.Labels = map[string]string{point2pointipam.RequestedSrcIPAddrLabel: "192.168.0.40"}
+	conn1, err = srv.Request(context.Background(), req1)
+	require.NoError(t, err)
+	validateConn(t, conn1, "192.168.0.6/32", "192.168.0.40/32")
+
+	// Freed address is available again
+	req5 := newRequest()
+	req5.Connection.Context.IpContext.SrcIpAddr = "192.168.0.10/32"
+	conn5, err := srv.Request(context.Background(), req5)
+	require.NoError(t, err)
+	validateConn(t, conn5, "192.168.0.0/32", "192.168.0.10/32")
+}
+
+func TestServer_StaticAddr_Strict(t *testing.T) {
+	_, ipNet, err := net.ParseCIDR("192.168.0.0/16")
+	require.NoError(t, err)
+
+	srv := next.NewNetworkServiceServer(
+		updatepath.NewServer("ipam"),
+		metadata.NewServer(),
+		point2pointipam.NewServerWithOptions([]*net.IPNet{ipNet}, point2pointipam.WithStaticAddrPolicy(point2pointipam.StaticAddrStrict)),
+	)
+
+	req := newRequest()
+	req.Connection.Context.IpContext.SrcIpAddr = "192.168.0.10/32"
+	conn, err := srv.Request(context.Background(), req)
+	require.NoError(t, err)
+	validateConn(t, conn, "192.168.0.0/32", "192.168.0.10/32")
+
+	req = newRequest()
+	req.Connection.Context.IpContext.SrcIpAddr = "192.168.0.10/32"
+	_, err = srv.Request(context.Background(), req)
+	require.Error(t, err)
+
+	req = newRequest()
+	req.Connection.Context.IpContext.SrcIpAddr = "192.168.0.20/32"
+	req.Connection.Context.IpContext.ExcludedPrefixes = []string{"192.168.0.16/28"}
+	_, err = srv.Request(context.Background(), req)
+	require.Error(t, err)
+
+	req = newRequest()
+	req.Connection.Context.IpContext.SrcIpAddr = "10.0.0.1/32"
+	_, err = srv.Request(context.Background(), req)
+	require.Error(t, err)
+
+	// Without the requested address any address is allocated
+	conn, err = srv.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	validateConn(t, conn, "192.168.0.1/32", "192.168.0.2/32")
+}
+
+func TestServer_StaticAddr_StrictRefreshExcluded(t *testing.T) {
+	_, ipNet, err := net.ParseCIDR("192.168.0.0/16")
+	require.NoError(t, err)
+
+	srv := next.NewNetworkServiceServer(
+		updatepath.NewServer("ipam"),
+		metadata.NewServer(),
+		point2pointipam.NewServerWithOptions([]*net.IPNet{ipNet}, point2pointipam.WithStaticAddrPolicy(point2pointipam.StaticAddrStrict)),
+	)
+
+	req := newRequest()
+	conn, err := srv.Request(context.Background(), req)
+	require.NoError(t, err)
+	validateConn(t, conn, "192.168.0.0/32", "192.168.0.1/32")
+
+	// Allocated addresses are not the requested ones, so they are reallocated on the exclusion
+	req.Connection = conn.Clone()
+	req.Connection.Context.IpContext.ExcludedPrefixes = []string{"192.168.0.0/30"}
+	conn, err = srv.Request(context.Background(), req)
+	require.NoError(t, err)
+	validateConn(t, conn, "192.168.0.4/32", "192.168.0.5/32")
+}
+
+func TestServer_StaticAddr_DualStack(t *testing.T) {
+	_, ipv4Net, err := net.ParseCIDR("192.168.0.0/16")
+	require.NoError(t, err)
+	_, ipv6Net, err := net.ParseCIDR("fe80::/64")
+	require.NoError(t, err)
+
+	srv := newIpamServer(ipv4Net, ipv6Net)
+
+	req := newRequest()
+	req.Connection.Labels = map[string]string{point2pointipam.RequestedSrcIPAddrLabel: "fe80::10"}
+	req.Connection.Context.IpContext.SrcIpAddr = "192.168.0.10/32"
+	conn, err := srv.Request(context.Background(), req)
+	require.NoError(t, err)
+
+	require.Equal(t, "192.168.0.0/32", conn.Context.IpContext.DstIpAddr)
+	require.Equal(t, "192.168.0.10/32", conn.Context.IpContext.SrcIpAddr)
+	require.Equal(t, "fe80::/128", conn.Context.ExtraContext[point2pointipam.SecondaryDstIPAddrKey])
+	require.Equal(t, "fe80::10/128", conn.Context.ExtraContext[point2pointipam.SecondarySrcIPAddrKey])
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package singlepointipam
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/dualstack"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type keyType struct{}
+
+func storeConnInfos(ctx context.Context, connInfos []dualstack.Allocation) {
+	metadata.Map(ctx, false).Store(keyType{}, connInfos)
+}
+
+func loadConnInfos(ctx context.Context) ([]dualstack.Allocation, bool) {
+	if raw, ok := metadata.Map(ctx, false).Load(keyType{}); ok {
+		return raw.([]dualstack.Allocation), true
+	}
+	return nil, false
+}
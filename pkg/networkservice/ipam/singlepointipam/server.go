@@ -0,0 +1,233 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package singlepointipam provides an IPAM server chain element for the shared subnet model: NSE owns a single
+// address in the subnet and each client gets an address from the same subnet.
+package singlepointipam
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/dualstack"
+	"github.com/networkservicemesh/sdk/pkg/tools/cidr"
+	"github.com/networkservicemesh/sdk/pkg/tools/ippool"
+)
+
+type singlePointIPAMServer struct {
+	// subnets are grouped by the IP family, the family of the first prefix goes first
+	subnets  [][]*subnet
+	prefixes []*net.IPNet
+	once     sync.Once
+	initErr  error
+}
+
+type subnet struct {
+	ipNet   *net.IPNet
+	nseIP   net.IP
+	nseAddr string
+	ipPool  *ippool.IPPool
+}
+
+type connectionInfo struct {
+	subnet  *subnet
+	srcAddr string
+}
+
+// NewServer - creates a new NetworkServiceServer chain element that implements IPAM service for the shared subnet
+//             model. For each prefix NSE owns the prefix address (e.g. 10.0.0.1/16), or the first host address if
+//             the prefix address is the subnet address (e.g. 10.0.0.0/16). Each client gets an address from the same
+//             subnet with the route to the subnet, the NSE side gets the route to the client address.
+//             Prefixes can be both IPv4 and IPv6, the dual-stack mode works the same way as in the point2pointipam:
+//             the addresses of the second IP family are set to the Connection.Context.ExtraContext with the
+//             dualstack.SecondarySrcIPAddrKey, dualstack.SecondaryDstIPAddrKey keys, routes are set only for the
+//             first prefix family addresses configured on the interface.
+//             prefixes - NSE addresses with the subnet masks
+func NewServer(prefixes ...*net.IPNet) networkservice.NetworkServiceServer {
+	return &singlePointIPAMServer{
+		prefixes: prefixes,
+	}
+}
+
+func (s *singlePointIPAMServer) init() {
+	ipNets, err := dualstack.GroupByFamily(s.prefixes)
+	if err != nil {
+		s.initErr = err
+		return
+	}
+
+	for _, familyNets := range ipNets {
+		var subnets []*subnet
+		for _, ipNet := range familyNets {
+			sn, snErr := newSubnet(ipNet)
+			if snErr != nil {
+				s.initErr = snErr
+				return
+			}
+			subnets = append(subnets, sn)
+		}
+		s.subnets = append(s.subnets, subnets)
+	}
+}
+
+func newSubnet(prefix *net.IPNet) (*subnet, error) {
+	ipNet := &net.IPNet{
+		IP:   prefix.IP.Mask(prefix.Mask),
+		Mask: prefix.Mask,
+	}
+	if ipNet.IP == nil {
+		return nil, errors.Errorf("invalid prefix: %v", prefix)
+	}
+	ipPool := ippool.NewWithNet(ipNet)
+
+	// Subnet and broadcast addresses can't be used by the hosts
+	if ones, bits := ipNet.Mask.Size(); bits-ones > 1 {
+		if err := ipPool.PullIP(ipNet.IP); err != nil {
+			return nil, err
+		}
+		if ipNet.IP.To4() != nil {
+			if err := ipPool.PullIP(cidr.BroadcastAddress(ipNet)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	nseIP := prefix.IP
+	if nseIP.Equal(ipNet.IP) {
+		var err error
+		if nseIP, err = ipPool.Pull(nil); err != nil {
+			return nil, errors.Wrapf(err, "no address for NSE in the prefix: %v", prefix)
+		}
+	} else if err := ipPool.PullIP(nseIP); err != nil {
+		return nil, errors.Wrapf(err, "NSE address can't be used: %v", prefix)
+	}
+
+	return &subnet{
+		ipNet:   ipNet,
+		nseIP:   nseIP,
+		nseAddr: (&net.IPNet{IP: nseIP, Mask: ipNet.Mask}).String(),
+		ipPool:  ipPool,
+	}, nil
+}
+
+func (s *singlePointIPAMServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	s.once.Do(s.init)
+	if s.initErr != nil {
+		return nil, s.initErr
+	}
+
+	connInfos, ok := loadConnInfos(ctx)
+	if !ok {
+		connInfos = make([]dualstack.Allocation, len(s.subnets))
+	}
+
+	err := dualstack.Allocate(request.GetConnection(), connInfos, func(i int, current dualstack.Allocation, exclude *ippool.IPPool) (dualstack.Allocation, error) {
+		if current != nil {
+			return current, nil
+		}
+		return getAddr(s.subnets[i], exclude)
+	})
+	if err != nil {
+		return nil, err
+	}
+	storeConnInfos(ctx, connInfos)
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		// metadata is deleted on the Request failure, so there will be no Close to free the addresses
+		for _, connInfo := range connInfos {
+			if connInfo != nil {
+				connInfo.Free()
+			}
+		}
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func getAddr(subnets []*subnet, exclude *ippool.IPPool) (dualstack.Allocation, error) {
+	err := errors.New("all subnets NSE addresses are excluded")
+	for _, sn := range subnets {
+		if exclude.Contains(sn.nseIP) {
+			continue
+		}
+		var srcIP net.IP
+		if srcIP, err = sn.ipPool.Pull(exclude); err == nil {
+			return &connectionInfo{
+				subnet:  sn,
+				srcAddr: (&net.IPNet{IP: srcIP, Mask: sn.ipNet.Mask}).String(),
+			}, nil
+		}
+	}
+	return nil, err
+}
+
+func (s *singlePointIPAMServer) Close(ctx context.Context, conn *networkservice.Connection) (_ *empty.Empty, err error) {
+	s.once.Do(s.init)
+	if s.initErr != nil {
+		return nil, s.initErr
+	}
+
+	if connInfos, ok := loadConnInfos(ctx); ok {
+		for _, connInfo := range connInfos {
+			if connInfo != nil {
+				connInfo.Free()
+			}
+		}
+	}
+
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func (i *connectionInfo) SrcAddr() string {
+	return i.srcAddr
+}
+
+func (i *connectionInfo) DstAddr() string {
+	return i.subnet.nseAddr
+}
+
+func (i *connectionInfo) SrcRoute() string {
+	return i.subnet.ipNet.String()
+}
+
+func (i *connectionInfo) DstRoute() string {
+	srcIP, _, err := net.ParseCIDR(i.srcAddr)
+	if err != nil {
+		return i.srcAddr
+	}
+	return dualstack.HostAddr(srcIP)
+}
+
+func (i *connectionInfo) IsExcluded(exclude *ippool.IPPool) bool {
+	srcIP, _, srcErr := net.ParseCIDR(i.srcAddr)
+
+	return srcErr != nil || exclude.Contains(srcIP) || exclude.Contains(i.subnet.nseIP)
+}
+
+func (i *connectionInfo) Free() {
+	if ip, _, err := net.ParseCIDR(i.srcAddr); err == nil {
+		i.subnet.ipPool.Add(ip)
+	}
+}
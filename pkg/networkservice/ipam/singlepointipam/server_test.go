@@ -0,0 +1,197 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package singlepointipam_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatepath"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/dualstack"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/ipam/singlepointipam"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+func newIpamServer(prefixes ...*net.IPNet) networkservice.NetworkServiceServer {
+	return next.NewNetworkServiceServer(
+		updatepath.NewServer("ipam"),
+		metadata.NewServer(),
+		singlepointipam.NewServer(prefixes...),
+	)
+}
+
+func newRequest() *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Context: &networkservice.ConnectionContext{
+				IpContext: new(networkservice.IPContext),
+			},
+		},
+	}
+}
+
+func parsePrefix(t *testing.T, prefix string) *net.IPNet {
+	ip, ipNet, err := net.ParseCIDR(prefix)
+	require.NoError(t, err)
+	ipNet.IP = ip
+	return ipNet
+}
+
+func validateConn(t *testing.T, conn *networkservice.Connection, dst, src, subnet, srcHost string) {
+	require.Equal(t, dst, conn.Context.IpContext.DstIpAddr)
+	require.Equal(t, []*networkservice.Route{
+		{
+			Prefix: srcHost,
+		},
+	}, conn.Context.IpContext.DstRoutes)
+
+	require.Equal(t, src, conn.Context.IpContext.SrcIpAddr)
+	require.Equal(t, []*networkservice.Route{
+		{
+			Prefix: subnet,
+		},
+	}, conn.Context.IpContext.SrcRoutes)
+}
+
+func TestServer(t *testing.T) {
+	srv := newIpamServer(parsePrefix(t, "10.0.0.1/16"))
+
+	conn1, err := srv.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	validateConn(t, conn1, "10.0.0.1/16", "10.0.0.2/16", "10.0.0.0/16", "10.0.0.2/32")
+
+	conn2, err := srv.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	validateConn(t, conn2, "10.0.0.1/16", "10.0.0.3/16", "10.0.0.0/16", "10.0.0.3/32")
+
+	_, err = srv.Close(context.Background(), conn1)
+	require.NoError(t, err)
+
+	conn3, err := srv.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	validateConn(t, conn3, "10.0.0.1/16", "10.0.0.2/16", "10.0.0.0/16", "10.0.0.2/32")
+}
+
+func TestServer_SubnetAddress(t *testing.T) {
+	srv := newIpamServer(parsePrefix(t, "10.0.0.0/16"))
+
+	conn, err := srv.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	validateConn(t, conn, "10.0.0.1/16", "10.0.0.2/16", "10.0.0.0/16", "10.0.0.2/32")
+}
+
+func TestServer_InvalidNSEAddress(t *testing.T) {
+	_, err := newIpamServer(parsePrefix(t, "10.0.0.255/24")).Request(context.Background(), newRequest())
+	require.Error(t, err)
+}
+
+func TestOutOfIPs(t *testing.T) {
+	srv := newIpamServer(parsePrefix(t, "10.0.0.1/30"))
+
+	_, err := srv.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+
+	_, err = srv.Request(context.Background(), newRequest())
+	require.Error(t, err)
+}
+
+func TestServer_RequestFailed(t *testing.T) {
+	ipamServer := singlepointipam.NewServer(parsePrefix(t, "10.0.0.1/30"))
+
+	_, err := next.NewNetworkServiceServer(
+		updatepath.NewServer("ipam"),
+		metadata.NewServer(),
+		ipamServer,
+		injecterror.NewServer(),
+	).Request(context.Background(), newRequest())
+	require.Error(t, err)
+
+	// The only client address is freed on the failure
+	conn, err := next.NewNetworkServiceServer(
+		updatepath.NewServer("ipam"),
+		metadata.NewServer(),
+		ipamServer,
+	).Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	validateConn(t, conn, "10.0.0.1/30", "10.0.0.2/30", "10.0.0.0/30", "10.0.0.2/32")
+}
+
+func TestRefreshRequest(t *testing.T) {
+	srv := newIpamServer(parsePrefix(t, "10.0.0.1/16"), parsePrefix(t, "172.16.0.1/16"))
+
+	req := newRequest()
+	req.Connection.Context.IpContext.ExcludedPrefixes = []string{"10.0.0.2/32"}
+	conn, err := srv.Request(context.Background(), req)
+	require.NoError(t, err)
+	validateConn(t, conn, "10.0.0.1/16", "10.0.0.3/16", "10.0.0.0/16", "10.0.0.3/32")
+
+	req = newRequest()
+	req.Connection.Id = conn.Id
+	conn, err = srv.Request(context.Background(), req)
+	require.NoError(t, err)
+	validateConn(t, conn, "10.0.0.1/16", "10.0.0.3/16", "10.0.0.0/16", "10.0.0.3/32")
+
+	req.Connection = conn.Clone()
+	req.Connection.Context.IpContext.ExcludedPrefixes = []string{"10.0.0.2/31"}
+	conn, err = srv.Request(context.Background(), req)
+	require.NoError(t, err)
+	validateConn(t, conn, "10.0.0.1/16", "10.0.0.4/16", "10.0.0.0/16", "10.0.0.4/32")
+
+	// NSE address is excluded, so the next subnet is used
+	req.Connection = conn.Clone()
+	req.Connection.Context.IpContext.ExcludedPrefixes = []string{"10.0.0.1/32"}
+	conn, err = srv.Request(context.Background(), req)
+	require.NoError(t, err)
+	validateConn(t, conn, "172.16.0.1/16", "172.16.0.2/16", "172.16.0.0/16", "172.16.0.2/32")
+
+	// Freed addresses are available for the other connections
+	conn2, err := srv.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	validateConn(t, conn2, "10.0.0.1/16", "10.0.0.2/16", "10.0.0.0/16", "10.0.0.2/32")
+	conn3, err := srv.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	validateConn(t, conn3, "10.0.0.1/16", "10.0.0.3/16", "10.0.0.0/16", "10.0.0.3/32")
+}
+
+func TestServer_IPv6(t *testing.T) {
+	srv := newIpamServer(parsePrefix(t, "fe80::/64"))
+
+	conn, err := srv.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+	validateConn(t, conn, "fe80::1/64", "fe80::2/64", "fe80::/64", "fe80::2/128")
+}
+
+func TestServer_DualStack(t *testing.T) {
+	srv := newIpamServer(parsePrefix(t, "10.0.0.1/16"), parsePrefix(t, "fe80::1/64"))
+
+	conn, err := srv.Request(context.Background(), newRequest())
+	require.NoError(t, err)
+
+	require.Equal(t, "10.0.0.1/16", conn.Context.IpContext.DstIpAddr)
+	require.Equal(t, "10.0.0.2/16", conn.Context.IpContext.SrcIpAddr)
+	require.Equal(t, "fe80::1/64", conn.Context.ExtraContext[dualstack.SecondaryDstIPAddrKey])
+	require.Equal(t, "fe80::2/64", conn.Context.ExtraContext[dualstack.SecondarySrcIPAddrKey])
+	require.Equal(t, []*networkservice.Route{{Prefix: "10.0.0.2/32"}}, conn.Context.IpContext.DstRoutes)
+	require.Equal(t, []*networkservice.Route{{Prefix: "10.0.0.0/16"}}, conn.Context.IpContext.SrcRoutes)
+}
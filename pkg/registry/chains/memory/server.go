@@ -1,4 +1,4 @@
-// Copyright (c) 2020 Doc.ai and/or its affiliates.
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -29,34 +29,88 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/registry/common/connect"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/expire"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/metrics"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/proxy"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/setid"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/tools/scheduler"
 )
 
+type serverOptions struct {
+	dialOptions    []grpc.DialOption
+	metricsOptions []metrics.Option
+	withMetrics    bool
+}
+
+// Option modifies server option value
+type Option func(o *serverOptions)
+
+// WithDialOptions sets gRPC Dial Options for the proxy registry connections
+func WithDialOptions(dialOptions ...grpc.DialOption) Option {
+	return func(o *serverOptions) {
+		o.dialOptions = dialOptions
+	}
+}
+
+// WithMetrics enables metrics collection for the NS and NSE registry chains
+func WithMetrics(metricsOptions ...metrics.Option) Option {
+	return func(o *serverOptions) {
+		o.withMetrics = true
+		o.metricsOptions = metricsOptions
+	}
+}
+
 // NewServer creates new registry server based on memory storage
-func NewServer(ctx context.Context, proxyRegistryURL *url.URL, options ...grpc.DialOption) registryserver.Registry {
-	nseChain := chain.NewNetworkServiceEndpointRegistryServer(
+func NewServer(ctx context.Context, proxyRegistryURL *url.URL, dialOptions ...grpc.DialOption) registryserver.Registry {
+	return NewServerWithOptions(ctx, proxyRegistryURL, WithDialOptions(dialOptions...))
+}
+
+// NewServerWithOptions - same as NewServer, but configured with options
+func NewServerWithOptions(ctx context.Context, proxyRegistryURL *url.URL, options ...Option) registryserver.Registry {
+	opts := new(serverOptions)
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	// All the registration timers share the same scheduler
+	ctx = scheduler.WithScheduler(ctx, scheduler.FromContext(ctx))
+
+	nseServers := []registry.NetworkServiceEndpointRegistryServer{
 		setid.NewNetworkServiceEndpointRegistryServer(),
-		expire.NewNetworkServiceEndpointRegistryServer(time.Minute),
-		memory.NewNetworkServiceEndpointRegistryServer(),
-		proxy.NewNetworkServiceEndpointRegistryServer(proxyRegistryURL),
-		connect.NewNetworkServiceEndpointRegistryServer(ctx, func(ctx context.Context, cc grpc.ClientConnInterface) registry.NetworkServiceEndpointRegistryClient {
-			return chain.NewNetworkServiceEndpointRegistryClient(
-				registry.NewNetworkServiceEndpointRegistryClient(cc),
-			)
-		}, connect.WithClientDialOptions(options...)),
+		expire.NewNetworkServiceEndpointRegistryServer(ctx, time.Minute),
+	}
+	if opts.withMetrics {
+		nseServers = append(nseServers, metrics.NewNetworkServiceEndpointRegistryServer(opts.metricsOptions...))
+	}
+	nseChain := chain.NewNetworkServiceEndpointRegistryServer(
+		append(nseServers,
+			memory.NewNetworkServiceEndpointRegistryServer(),
+			proxy.NewNetworkServiceEndpointRegistryServer(proxyRegistryURL),
+			connect.NewNetworkServiceEndpointRegistryServer(ctx, func(ctx context.Context, cc grpc.ClientConnInterface) registry.NetworkServiceEndpointRegistryClient {
+				return chain.NewNetworkServiceEndpointRegistryClient(
+					registry.NewNetworkServiceEndpointRegistryClient(cc),
+				)
+			}, connect.WithClientDialOptions(opts.dialOptions...)),
+		)...,
 	)
-	nsChain := chain.NewNetworkServiceRegistryServer(
+
+	nsServers := []registry.NetworkServiceRegistryServer{
 		expire.NewNetworkServiceServer(ctx, adapters.NetworkServiceEndpointServerToClient(nseChain)),
-		memory.NewNetworkServiceRegistryServer(),
-		proxy.NewNetworkServiceRegistryServer(proxyRegistryURL),
-		connect.NewNetworkServiceRegistryServer(ctx, func(ctx context.Context, cc grpc.ClientConnInterface) registry.NetworkServiceRegistryClient {
-			return chain.NewNetworkServiceRegistryClient(
-				registry.NewNetworkServiceRegistryClient(cc),
-			)
-		}, connect.WithClientDialOptions(options...)),
+	}
+	if opts.withMetrics {
+		nsServers = append(nsServers, metrics.NewNetworkServiceRegistryServer(opts.metricsOptions...))
+	}
+	nsChain := chain.NewNetworkServiceRegistryServer(
+		append(nsServers,
+			memory.NewNetworkServiceRegistryServer(),
+			proxy.NewNetworkServiceRegistryServer(proxyRegistryURL),
+			connect.NewNetworkServiceRegistryServer(ctx, func(ctx context.Context, cc grpc.ClientConnInterface) registry.NetworkServiceRegistryClient {
+				return chain.NewNetworkServiceRegistryClient(
+					registry.NewNetworkServiceRegistryClient(cc),
+				)
+			}, connect.WithClientDialOptions(opts.dialOptions...)),
+		)...,
 	)
 
 	return registryserver.NewServer(nsChain, nseChain)
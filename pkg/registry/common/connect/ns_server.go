@@ -1,4 +1,4 @@
-// Copyright (c) 2020 Doc.ai and/or its affiliates.
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -30,10 +30,11 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
 )
 
 type nsCacheEntry struct {
-	expirationTimer *time.Timer
+	expirationTimer clock.Timer
 	client          registry.NetworkServiceRegistryClient
 }
 
@@ -43,13 +44,16 @@ type connectNSServer struct {
 	cache             nsClientMap
 	connectExpiration time.Duration
 	ctx               context.Context
+	clock             clock.Clock
 }
 
 // NewNetworkServiceRegistryServer creates new connect NetworkServiceEndpointRegistryServer with specific chain context, registry client factory and options
 // that allows connecting to other registries via passed clienturl.
+// ctx - a context for all lifecycle, cached clients expiration timers are driven by the clock.Clock from it
 func NewNetworkServiceRegistryServer(ctx context.Context, clientFactory func(ctx context.Context, cc grpc.ClientConnInterface) registry.NetworkServiceRegistryClient, options ...Option) registry.NetworkServiceRegistryServer {
 	r := &connectNSServer{
 		ctx:               ctx,
+		clock:             clock.FromContext(ctx),
 		clientFactory:     clientFactory,
 		connectExpiration: defaultConnectExpiration,
 	}
@@ -87,7 +91,7 @@ func (c *connectNSServer) connect(ctx context.Context) registry.NetworkServiceRe
 	ctx = extend.WithValuesFromContext(c.ctx, ctx)
 	client := clienturl.NewNetworkServiceRegistryClient(ctx, c.clientFactory, c.dialOptions...)
 	cached, _ := c.cache.LoadOrStore(key, &nsCacheEntry{
-		expirationTimer: time.AfterFunc(c.connectExpiration, func() {
+		expirationTimer: c.clock.AfterFunc(c.connectExpiration, func() {
 			c.cache.Delete(key)
 		}),
 		client: client,
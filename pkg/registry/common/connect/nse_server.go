@@ -1,4 +1,4 @@
-// Copyright (c) 2020 Doc.ai and/or its affiliates.
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -30,10 +30,11 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
 )
 
 type nseCacheEntry struct {
-	expirationTimer *time.Timer
+	expirationTimer clock.Timer
 	client          registry.NetworkServiceEndpointRegistryClient
 }
 
@@ -43,16 +44,18 @@ type connectNSEServer struct {
 	cache             nseClientMap
 	connectExpiration time.Duration
 	ctx               context.Context
+	clock             clock.Clock
 }
 
 // NewNetworkServiceEndpointRegistryServer creates new connect NetworkServiceEndpointEndpointRegistryServer with specific chain context, registry client factory and options
 // that allows connecting to other registries via passed clienturl.
-// ctx - a context for all lifecycle
+// ctx - a context for all lifecycle, cached clients expiration timers are driven by the clock.Clock from it
 func NewNetworkServiceEndpointRegistryServer(ctx context.Context,
 	clientFactory func(ctx context.Context, cc grpc.ClientConnInterface) registry.NetworkServiceEndpointRegistryClient,
 	options ...Option) registry.NetworkServiceEndpointRegistryServer {
 	r := &connectNSEServer{
 		ctx:               ctx,
+		clock:             clock.FromContext(ctx),
 		clientFactory:     clientFactory,
 		connectExpiration: defaultConnectExpiration,
 	}
@@ -88,7 +91,7 @@ func (c *connectNSEServer) connect(ctx context.Context) registry.NetworkServiceE
 	ctx = extend.WithValuesFromContext(c.ctx, ctx)
 	client := clienturl.NewNetworkServiceEndpointRegistryClient(ctx, c.clientFactory, c.dialOptions...)
 	cached, _ := c.cache.LoadOrStore(key, &nseCacheEntry{
-		expirationTimer: time.AfterFunc(c.connectExpiration, func() {
+		expirationTimer: c.clock.AfterFunc(c.connectExpiration, func() {
 			c.cache.Delete(key)
 		}),
 		client: client,
@@ -0,0 +1,35 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expire
+
+import (
+	"context"
+)
+
+type contextKeyType string
+
+const expiredKey contextKeyType = "Expired"
+
+func withExpired(parent context.Context) context.Context {
+	return context.WithValue(parent, expiredKey, true)
+}
+
+// IsExpired returns true if ctx is a context of the Unregister call issued by the expire chain element on expiration
+func IsExpired(ctx context.Context) bool {
+	expired, ok := ctx.Value(expiredKey).(bool)
+	return ok && expired
+}
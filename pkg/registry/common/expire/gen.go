@@ -1,4 +1,4 @@
-// Copyright (c) 2020 Doc.ai and/or its affiliates.
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -18,7 +18,7 @@ package expire
 
 import "sync"
 
-//go:generate go-syncmap -output timer_sync_map.gen.go -type timerMap<string,*time.Timer>
+//go:generate go-syncmap -output timer_sync_map.gen.go -type timerMap<string,github.com/networkservicemesh/sdk/pkg/tools/clock.Timer>
 //go:generate go-syncmap -output int_sync_map.gen.go -type intMap<string,*int32>
 //go:generate go-syncmap -output context_sync_map.gen.go -type contextMap<string,context.Context>
 
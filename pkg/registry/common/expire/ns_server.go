@@ -21,10 +21,11 @@ import (
 	"errors"
 	"sync"
 	"sync/atomic"
-	"time"
 
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
 	"github.com/networkservicemesh/sdk/pkg/tools/extend"
+	"github.com/networkservicemesh/sdk/pkg/tools/scheduler"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/networkservicemesh/api/pkg/api/registry"
@@ -38,6 +39,8 @@ type nsServer struct {
 	contexts   contextMap
 	once       sync.Once
 	chainCtx   context.Context
+	clock      clock.Clock
+	scheduler  *scheduler.Scheduler
 }
 
 func (n *nsServer) checkUpdates() {
@@ -59,11 +62,11 @@ func (n *nsServer) checkUpdates() {
 			} else {
 				atomic.AddInt32(stored, 1)
 			}
-			duration := time.Until(nse.ExpirationTime.AsTime())
-			timer := time.AfterFunc(duration, func() {
+			duration := n.clock.Until(nse.ExpirationTime.AsTime())
+			timer := n.scheduler.AfterFunc(duration, func() {
 				if atomic.AddInt32(stored, -1) <= 0 {
 					if ctx, ok := n.contexts.Load(ns); ok {
-						_, _ = n.Unregister(ctx, &registry.NetworkService{Name: ns})
+						_, _ = n.Unregister(withExpired(ctx), &registry.NetworkService{Name: ns})
 					}
 				}
 			})
@@ -112,6 +115,8 @@ func (n *nsServer) Unregister(ctx context.Context, request *registry.NetworkServ
 }
 
 // NewNetworkServiceServer wraps passed NetworkServiceRegistryServer and monitor NetworkServiceEndpoints via passed NetworkServiceEndpointRegistryClient
+// ctx - a context for all lifecycle, expiration timers are scheduled with the scheduler.Scheduler from it and are
+// driven by the clock.Clock from it
 func NewNetworkServiceServer(ctx context.Context, nseClient registry.NetworkServiceEndpointRegistryClient) registry.NetworkServiceRegistryServer {
-	return &nsServer{nseClient: nseClient, chainCtx: ctx}
+	return &nsServer{nseClient: nseClient, chainCtx: ctx, clock: clock.FromContext(ctx), scheduler: scheduler.FromContext(ctx)}
 }
@@ -25,10 +25,14 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
 	"github.com/networkservicemesh/sdk/pkg/tools/extend"
+	"github.com/networkservicemesh/sdk/pkg/tools/scheduler"
 )
 
 type nseServer struct {
+	clock         clock.Clock
+	scheduler     *scheduler.Scheduler
 	timers        timerMap
 	nseExpiration time.Duration
 }
@@ -38,12 +42,12 @@ func (n *nseServer) Register(ctx context.Context, nse *registry.NetworkServiceEn
 	if err != nil {
 		return nil, err
 	}
-	resp.ExpirationTime = timestamppb.New(time.Now().Add(n.nseExpiration))
+	resp.ExpirationTime = timestamppb.New(n.clock.Now().Add(n.nseExpiration))
 
 	unregisterNSE := resp.Clone()
 
-	timer := time.AfterFunc(n.nseExpiration, func() {
-		unregisterCtx, cancel := context.WithTimeout(extend.WithValuesFromContext(context.Background(), ctx), n.nseExpiration)
+	timer := n.scheduler.AfterFunc(n.nseExpiration, func() {
+		unregisterCtx, cancel := n.clock.WithTimeout(withExpired(extend.WithValuesFromContext(context.Background(), ctx)), n.nseExpiration)
 		defer cancel()
 		_, _ = next.NetworkServiceEndpointRegistryServer(unregisterCtx).Unregister(unregisterCtx, unregisterNSE)
 	})
@@ -74,8 +78,12 @@ func (n *nseServer) Unregister(ctx context.Context, nse *registry.NetworkService
 }
 
 // NewNetworkServiceEndpointRegistryServer wraps passed NetworkServiceEndpointRegistryServer and monitor Network service endpoints
-func NewNetworkServiceEndpointRegistryServer(nseExpiration time.Duration) registry.NetworkServiceEndpointRegistryServer {
+// ctx - a context for all lifecycle, expiration timers are scheduled with the scheduler.Scheduler from it and are
+// driven by the clock.Clock from it
+func NewNetworkServiceEndpointRegistryServer(ctx context.Context, nseExpiration time.Duration) registry.NetworkServiceEndpointRegistryServer {
 	return &nseServer{
+		clock:         clock.FromContext(ctx),
+		scheduler:     scheduler.FromContext(ctx),
 		nseExpiration: nseExpiration,
 	}
 }
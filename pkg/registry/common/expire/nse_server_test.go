@@ -33,6 +33,8 @@ import (
 
 	"github.com/networkservicemesh/sdk/pkg/registry/common/expire"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
 )
 
 type remoteNSEServer struct{}
@@ -52,7 +54,7 @@ func (n *remoteNSEServer) Unregister(ctx context.Context, nse *registry.NetworkS
 func Test_ExpireServer_ShouldCorrectlySetExpirationTime_InRemoteCase(t *testing.T) {
 	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
 
-	s := next.NewNetworkServiceEndpointRegistryServer(expire.NewNetworkServiceEndpointRegistryServer(time.Hour), new(remoteNSEServer))
+	s := next.NewNetworkServiceEndpointRegistryServer(expire.NewNetworkServiceEndpointRegistryServer(context.Background(), time.Hour), new(remoteNSEServer))
 
 	resp, err := s.Register(context.Background(), &registry.NetworkServiceEndpoint{Name: "nse-1"})
 
@@ -64,8 +66,11 @@ func Test_ExpireServer_ShouldCorrectlySetExpirationTime_InRemoteCase(t *testing.
 func TestNewNetworkServiceEndpointRegistryServer(t *testing.T) {
 	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
 
+	clockMock := clockmock.NewMock()
+	ctx := clock.WithClock(context.Background(), clockMock)
+
 	s := next.NewNetworkServiceEndpointRegistryServer(
-		expire.NewNetworkServiceEndpointRegistryServer(testPeriod*2),
+		expire.NewNetworkServiceEndpointRegistryServer(ctx, testPeriod*2),
 		new(remoteNSEServer), // <-- GRPC invocation
 		memory.NewNetworkServiceEndpointRegistryServer(),
 	)
@@ -82,6 +87,14 @@ func TestNewNetworkServiceEndpointRegistryServer(t *testing.T) {
 	list := registry.ReadNetworkServiceEndpointList(stream)
 	require.NotEmpty(t, list)
 
+	clockMock.Add(testPeriod)
+	stream, err = c.Find(context.Background(), &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, registry.ReadNetworkServiceEndpointList(stream))
+
+	clockMock.Add(testPeriod)
 	require.Eventually(t, func() bool {
 		stream, err = c.Find(context.Background(), &registry.NetworkServiceEndpointQuery{
 			NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
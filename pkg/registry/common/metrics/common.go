@@ -0,0 +1,157 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics provides registry chain elements collecting Prometheus/OpenMetrics metrics for the
+// Register/Find/Unregister calls, the stored entries, the open watches and the expirations
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/expire"
+	"github.com/networkservicemesh/sdk/pkg/tools/metrics"
+)
+
+const (
+	subsystem = "registry"
+
+	nsResource  = "ns"
+	nseResource = "nse"
+
+	registerMethod   = "Register"
+	findMethod       = "Find"
+	unregisterMethod = "Unregister"
+)
+
+var (
+	callLabelNames     = []string{"chain", "resource", "method"}
+	resourceLabelNames = []string{"chain", "resource"}
+)
+
+type metricsCollectors struct {
+	duration    *prometheus.HistogramVec
+	calls       *prometheus.CounterVec
+	entries     *prometheus.GaugeVec
+	watches     *prometheus.GaugeVec
+	expirations *prometheus.CounterVec
+}
+
+func newMetricsCollectors(registerer prometheus.Registerer) *metricsCollectors {
+	return &metricsCollectors{
+		duration: metrics.Register(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: subsystem,
+			Name:      "call_duration_seconds",
+			Help:      "Duration of the registry Register/Find/Unregister calls, Find(Watch=true) calls are not observed.",
+			Buckets:   prometheus.DefBuckets,
+		}, callLabelNames)).(*prometheus.HistogramVec),
+		calls: metrics.Register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: subsystem,
+			Name:      "calls_total",
+			Help:      "Number of the registry Register/Find/Unregister calls by the gRPC status code.",
+		}, append(callLabelNames, "code"))).(*prometheus.CounterVec),
+		entries: metrics.Register(registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: subsystem,
+			Name:      "entries",
+			Help:      "Number of the registered entries.",
+		}, resourceLabelNames)).(*prometheus.GaugeVec),
+		watches: metrics.Register(registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: subsystem,
+			Name:      "find_watches",
+			Help:      "Number of the open Find(Watch=true) streams.",
+		}, resourceLabelNames)).(*prometheus.GaugeVec),
+		expirations: metrics.Register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: subsystem,
+			Name:      "expirations_total",
+			Help:      "Number of the entries unregistered by the expire chain element.",
+		}, resourceLabelNames)).(*prometheus.CounterVec),
+	}
+}
+
+type metricsElement struct {
+	chainName  string
+	resource   string
+	collectors *metricsCollectors
+	names      namesMap
+}
+
+func newMetricsElement(resource string, options ...Option) *metricsElement {
+	o := &metricsOptions{
+		registerer: prometheus.DefaultRegisterer,
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+	return &metricsElement{
+		chainName:  o.chainName,
+		resource:   resource,
+		collectors: newMetricsCollectors(o.registerer),
+	}
+}
+
+func (m *metricsElement) observe(method string, startTime time.Time, err error) {
+	m.collectors.duration.
+		WithLabelValues(m.chainName, m.resource, method).
+		Observe(time.Since(startTime).Seconds())
+	m.count(method, err)
+}
+
+func (m *metricsElement) count(method string, err error) {
+	m.collectors.calls.
+		WithLabelValues(m.chainName, m.resource, method, status.Code(errors.Cause(err)).String()).
+		Inc()
+}
+
+func (m *metricsElement) registered(name string) {
+	if _, loaded := m.names.LoadOrStore(name, struct{}{}); !loaded {
+		m.collectors.entries.WithLabelValues(m.chainName, m.resource).Inc()
+	}
+}
+
+func (m *metricsElement) unregistered(ctx context.Context, name string) {
+	if _, loaded := m.names.LoadAndDelete(name); loaded {
+		m.collectors.entries.WithLabelValues(m.chainName, m.resource).Dec()
+	}
+	if expire.IsExpired(ctx) {
+		m.collectors.expirations.WithLabelValues(m.chainName, m.resource).Inc()
+	}
+}
+
+func (m *metricsElement) find(watch bool, find func() error) error {
+	if watch {
+		watches := m.collectors.watches.WithLabelValues(m.chainName, m.resource)
+		watches.Inc()
+		defer watches.Dec()
+
+		err := find()
+		m.count(findMethod, err)
+		return err
+	}
+
+	startTime := time.Now()
+	err := find()
+	m.observe(findMethod, startTime, err)
+	return err
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+)
+
+type metricsNSServer struct {
+	*metricsElement
+}
+
+// NewNetworkServiceRegistryServer creates new NetworkServiceRegistryServer collecting metrics for the rest of the
+// chain. It should be placed after the expire chain element to count the expirations.
+func NewNetworkServiceRegistryServer(options ...Option) registry.NetworkServiceRegistryServer {
+	return &metricsNSServer{
+		metricsElement: newMetricsElement(nsResource, options...),
+	}
+}
+
+func (s *metricsNSServer) Register(ctx context.Context, ns *registry.NetworkService) (*registry.NetworkService, error) {
+	startTime := time.Now()
+
+	resp, err := next.NetworkServiceRegistryServer(ctx).Register(ctx, ns)
+	if err == nil {
+		s.registered(resp.GetName())
+	}
+	s.observe(registerMethod, startTime, err)
+
+	return resp, err
+}
+
+func (s *metricsNSServer) Find(query *registry.NetworkServiceQuery, server registry.NetworkServiceRegistry_FindServer) error {
+	return s.find(query.GetWatch(), func() error {
+		return next.NetworkServiceRegistryServer(server.Context()).Find(query, server)
+	})
+}
+
+func (s *metricsNSServer) Unregister(ctx context.Context, ns *registry.NetworkService) (*empty.Empty, error) {
+	startTime := time.Now()
+
+	resp, err := next.NetworkServiceRegistryServer(ctx).Unregister(ctx, ns)
+	if err == nil {
+		s.unregistered(ctx, ns.GetName())
+	}
+	s.observe(unregisterMethod, startTime, err)
+
+	return resp, err
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+)
+
+type metricsNSEServer struct {
+	*metricsElement
+}
+
+// NewNetworkServiceEndpointRegistryServer creates new NetworkServiceEndpointRegistryServer collecting metrics for the
+// rest of the chain. It should be placed after the expire chain element to count the expirations.
+func NewNetworkServiceEndpointRegistryServer(options ...Option) registry.NetworkServiceEndpointRegistryServer {
+	return &metricsNSEServer{
+		metricsElement: newMetricsElement(nseResource, options...),
+	}
+}
+
+func (s *metricsNSEServer) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
+	startTime := time.Now()
+
+	resp, err := next.NetworkServiceEndpointRegistryServer(ctx).Register(ctx, nse)
+	if err == nil {
+		s.registered(resp.GetName())
+	}
+	s.observe(registerMethod, startTime, err)
+
+	return resp, err
+}
+
+func (s *metricsNSEServer) Find(query *registry.NetworkServiceEndpointQuery, server registry.NetworkServiceEndpointRegistry_FindServer) error {
+	return s.find(query.GetWatch(), func() error {
+		return next.NetworkServiceEndpointRegistryServer(server.Context()).Find(query, server)
+	})
+}
+
+func (s *metricsNSEServer) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*empty.Empty, error) {
+	startTime := time.Now()
+
+	resp, err := next.NetworkServiceEndpointRegistryServer(ctx).Unregister(ctx, nse)
+	if err == nil {
+		s.unregistered(ctx, nse.GetName())
+	}
+	s.observe(unregisterMethod, startTime, err)
+
+	return resp, err
+}
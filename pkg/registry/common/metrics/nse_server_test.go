@@ -0,0 +1,121 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/expire"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/metrics"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+)
+
+const (
+	chainName     = "registry"
+	expireTimeout = 100 * time.Millisecond
+	waitFor       = time.Second
+	tick          = 10 * time.Millisecond
+)
+
+func metricValue(t *testing.T, registry *prometheus.Registry, name string) float64 {
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() == name && len(family.GetMetric()) > 0 {
+			metric := family.GetMetric()[0]
+			if metric.GetGauge() != nil {
+				return metric.GetGauge().GetValue()
+			}
+			return metric.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestMetricsNSEServer_RegisterUnregister(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	reg := prometheus.NewRegistry()
+	s := next.NewNetworkServiceEndpointRegistryServer(
+		metrics.NewNetworkServiceEndpointRegistryServer(metrics.WithChainName(chainName), metrics.WithRegisterer(reg)),
+		memory.NewNetworkServiceEndpointRegistryServer(),
+	)
+
+	_, err := s.Register(context.Background(), &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+	_, err = s.Register(context.Background(), &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+	_, err = s.Register(context.Background(), &registry.NetworkServiceEndpoint{Name: "nse-2"})
+	require.NoError(t, err)
+
+	require.Equal(t, 2., metricValue(t, reg, "nsm_registry_entries"))
+	require.Equal(t, 3., metricValue(t, reg, "nsm_registry_calls_total"))
+
+	_, err = s.Unregister(context.Background(), &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+
+	require.Equal(t, 1., metricValue(t, reg, "nsm_registry_entries"))
+	require.Equal(t, 0., metricValue(t, reg, "nsm_registry_expirations_total"))
+}
+
+func TestMetricsNSEServer_WatchAndExpire(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	reg := prometheus.NewRegistry()
+	s := next.NewNetworkServiceEndpointRegistryServer(
+		expire.NewNetworkServiceEndpointRegistryServer(context.Background(), expireTimeout),
+		metrics.NewNetworkServiceEndpointRegistryServer(metrics.WithChainName(chainName), metrics.WithRegisterer(reg)),
+		memory.NewNetworkServiceEndpointRegistryServer(),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := adapters.NetworkServiceEndpointServerToClient(s).Find(ctx, &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+		Watch:                  true,
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return metricValue(t, reg, "nsm_registry_find_watches") == 1
+	}, waitFor, tick)
+
+	_, err = s.Register(context.Background(), &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+	require.Equal(t, 1., metricValue(t, reg, "nsm_registry_entries"))
+
+	require.Eventually(t, func() bool {
+		return metricValue(t, reg, "nsm_registry_expirations_total") == 1
+	}, waitFor, tick)
+	require.Equal(t, 0., metricValue(t, reg, "nsm_registry_entries"))
+
+	cancel()
+	require.Eventually(t, func() bool {
+		return metricValue(t, reg, "nsm_registry_find_watches") == 0
+	}, waitFor, tick)
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metricsOptions struct {
+	chainName  string
+	registerer prometheus.Registerer
+}
+
+// Option is an option pattern for NewNetworkServiceRegistryServer, NewNetworkServiceEndpointRegistryServer
+type Option func(o *metricsOptions)
+
+// WithChainName sets the "chain" label value for all the metrics collected by the chain element
+func WithChainName(chainName string) Option {
+	return func(o *metricsOptions) {
+		o.chainName = chainName
+	}
+}
+
+// WithRegisterer sets the prometheus.Registerer to register metrics with. Default is prometheus.DefaultRegisterer.
+func WithRegisterer(registerer prometheus.Registerer) Option {
+	return func(o *metricsOptions) {
+		o.registerer = registerer
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2020 Doc.ai and/or its affiliates.
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -27,10 +27,17 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/streamchannel"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
+)
+
+const (
+	watchOperation = "querycache.Find(Watch=true)"
 )
 
 type queryCacheNSEClient struct {
 	chainCtx context.Context
+	clock    clock.Clock
 	cache    memory.NetworkServiceEndpointSyncMap
 }
 
@@ -42,7 +49,7 @@ func (q *queryCacheNSEClient) Find(ctx context.Context, in *registry.NetworkServ
 	if in.Watch {
 		return next.NetworkServiceEndpointRegistryClient(ctx).Find(ctx, in, opts...)
 	}
-	if nse, ok := q.cache.Load(in.String()); ok {
+	if nse, ok := q.cache.Load(in.String()); ok && !q.isExpired(nse) {
 		resultCh := make(chan *registry.NetworkServiceEndpoint, 1)
 		resultCh <- nse
 		close(resultCh)
@@ -53,6 +60,8 @@ func (q *queryCacheNSEClient) Find(ctx context.Context, in *registry.NetworkServ
 		return nil, err
 	}
 	nses := registry.ReadNetworkServiceEndpointList(client)
+	// Watch spans are linked to the span of the Find
+	link := tracing.LinkFromContext(ctx)
 	resultCh := make(chan *registry.NetworkServiceEndpoint, len(nses))
 	for _, nse := range nses {
 		nseQuery := &registry.NetworkServiceEndpointQuery{
@@ -65,8 +74,10 @@ func (q *queryCacheNSEClient) Find(ctx context.Context, in *registry.NetworkServ
 		q.cache.Store(key, nse)
 		go func() {
 			defer q.cache.Delete(key)
+			watchCtx, span := tracing.StartLinkedSpan(q.chainCtx, watchOperation, link)
+			defer span.Finish()
 			nseQuery.Watch = true
-			stream, err := next.NetworkServiceEndpointRegistryClient(ctx).Find(q.chainCtx, nseQuery, opts...)
+			stream, err := next.NetworkServiceEndpointRegistryClient(ctx).Find(watchCtx, nseQuery, opts...)
 			if err != nil {
 				return
 			}
@@ -85,12 +96,19 @@ func (q *queryCacheNSEClient) Find(ctx context.Context, in *registry.NetworkServ
 	return streamchannel.NewNetworkServiceEndpointFindClient(ctx, resultCh), nil
 }
 
+// isExpired returns true if the cached NSE has expired and so is going to be unregistered, the cached value shouldn't
+// be used in such case
+func (q *queryCacheNSEClient) isExpired(nse *registry.NetworkServiceEndpoint) bool {
+	return nse.GetExpirationTime() != nil && !q.clock.Now().Before(nse.GetExpirationTime().AsTime())
+}
+
 func (q *queryCacheNSEClient) Unregister(ctx context.Context, in *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*empty.Empty, error) {
 	return next.NetworkServiceEndpointRegistryClient(ctx).Unregister(ctx, in, opts...)
 }
 
 // NewClient creates new querycache registry.NetworkServiceEndpointRegistryClient that caches all resolved NSEs
 // All cached NSE is
+// Expired NSEs are not taken from the cache, the expiration is checked with the clock.Clock from chainCtx.
 func NewClient(chainCtx context.Context) registry.NetworkServiceEndpointRegistryClient {
-	return &queryCacheNSEClient{chainCtx: chainCtx}
+	return &queryCacheNSEClient{chainCtx: chainCtx, clock: clock.FromContext(chainCtx)}
 }
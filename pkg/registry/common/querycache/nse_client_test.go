@@ -1,4 +1,4 @@
-// Copyright (c) 2020 Doc.ai and/or its affiliates.
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -27,11 +27,14 @@ import (
 	"github.com/networkservicemesh/api/pkg/api/registry"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/querycache"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
 )
 
 type FindCountServer struct{ findCount *int32 }
@@ -106,3 +109,39 @@ func Test_QueryCacheServer_ShouldCacheNSEs(t *testing.T) {
 		}, time.Second, time.Second/10)
 	}
 }
+
+func Test_QueryCacheServer_ShouldNotUseExpiredNSEs(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	findsCount := new(int32)
+	mem := next.NewNetworkServiceEndpointRegistryServer(&FindCountServer{findCount: findsCount}, memory.NewNetworkServiceEndpointRegistryServer())
+
+	_, err := mem.Register(ctx, &registry.NetworkServiceEndpoint{
+		Name:           "nse-1",
+		ExpirationTime: timestamppb.New(clockMock.Now().Add(time.Hour)),
+	})
+	require.NoError(t, err)
+
+	client := next.NewNetworkServiceEndpointRegistryClient(querycache.NewClient(ctx), adapters.NetworkServiceEndpointServerToClient(mem))
+	find := func() {
+		stream, err := client.Find(ctx, &registry.NetworkServiceEndpointQuery{
+			NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{Name: "nse-1"},
+		})
+		require.NoError(t, err)
+		require.Len(t, registry.ReadNetworkServiceEndpointList(stream), 1)
+	}
+
+	find()
+	find()
+	require.Equal(t, int32(1), atomic.LoadInt32(findsCount))
+
+	clockMock.Add(time.Hour)
+
+	find()
+	require.Equal(t, int32(2), atomic.LoadInt32(findsCount))
+}
@@ -18,51 +18,86 @@ package refresh
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/networkservicemesh/api/pkg/api/registry"
 	"google.golang.org/grpc"
 
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/scheduler"
+	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
+)
+
+const (
+	refreshOperation = "refresh.Register"
 )
 
 type refreshNSEClient struct {
 	chainContext          context.Context
+	clock                 clock.Clock
+	scheduler             *scheduler.Scheduler
 	nseCancels            cancelsMap
 	retryDelay            time.Duration
 	defaultExpiryDuration time.Duration
 }
 
-func (c *refreshNSEClient) startRefresh(ctx context.Context, client registry.NetworkServiceEndpointRegistryClient, nse *registry.NetworkServiceEndpoint) {
-	t := time.Unix(nse.ExpirationTime.Seconds, int64(nse.ExpirationTime.Nanos))
-	delta := time.Until(t)
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-time.After(2 * time.Until(t) / 3):
-				t1 := time.Now().Add(delta)
-				nse.ExpirationTime.Seconds = t1.Unix()
-				nse.ExpirationTime.Nanos = int32(t1.Nanosecond())
-				var err error
-				nse, err = client.Register(ctx, nse)
-				if err != nil {
-					<-time.After(c.retryDelay)
-					continue
-				}
-				t = t1
-			}
+// startRefresh - schedules the nse refresh Register in 2/3 of its expiration duration until ctx is done, returns
+// a function stopping the refresh
+func (c *refreshNSEClient) startRefresh(ctx context.Context, link tracing.Link, client registry.NetworkServiceEndpointRegistryClient, nse *registry.NetworkServiceEndpoint) context.CancelFunc {
+	ctx, cancel := context.WithCancel(ctx)
+
+	t := nse.ExpirationTime.AsTime()
+	delta := c.clock.Until(t)
+
+	var mu sync.Mutex
+	var task *scheduler.Task
+	var refresh func()
+	schedule := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		if ctx.Err() == nil {
+			task = c.scheduler.AfterFunc(d, refresh)
+		}
+	}
+	refresh = func() {
+		if ctx.Err() != nil {
+			return
+		}
+		t1 := c.clock.Now().Add(delta)
+		nse.ExpirationTime = timestamppb.New(t1)
+		spanCtx, span := tracing.StartLinkedSpan(ctx, refreshOperation, link)
+		resp, err := client.Register(spanCtx, nse)
+		span.Finish()
+		if err != nil {
+			schedule(c.retryDelay)
+			return
+		}
+		// Each next refresh span is linked to the previous one
+		nse, link = resp, tracing.LinkFromContext(spanCtx)
+		t = t1
+		schedule(2 * c.clock.Until(t) / 3)
+	}
+	schedule(2 * delta / 3)
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		cancel()
+		if task != nil {
+			task.Stop()
 		}
-	}()
+	}
 }
 
 func (c *refreshNSEClient) Register(ctx context.Context, in *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*registry.NetworkServiceEndpoint, error) {
 	if in.ExpirationTime == nil {
-		expirationTime := time.Now().Add(c.defaultExpiryDuration)
+		expirationTime := c.clock.Now().Add(c.defaultExpiryDuration)
 		in.ExpirationTime = &timestamp.Timestamp{
 			Seconds: expirationTime.Unix(),
 			Nanos:   int32(expirationTime.Nanosecond()),
@@ -77,10 +112,9 @@ func (c *refreshNSEClient) Register(ctx context.Context, in *registry.NetworkSer
 	if cancel, ok := c.nseCancels.Load(resp.Name); ok {
 		cancel()
 	}
-	ctx, cancel := context.WithCancel(c.chainContext)
-	c.nseCancels.Store(resp.Name, cancel)
+	link := tracing.LinkFromContext(ctx)
 	nse.ExpirationTime = resp.ExpirationTime
-	c.startRefresh(ctx, nextClient, nse)
+	c.nseCancels.Store(resp.Name, c.startRefresh(c.chainContext, link, nextClient, nse))
 	return resp, err
 }
 
@@ -111,6 +145,8 @@ func NewNetworkServiceEndpointRegistryClient(options ...Option) registry.Network
 	for _, o := range options {
 		o.apply(c)
 	}
+	c.clock = clock.FromContext(c.chainContext)
+	c.scheduler = scheduler.FromContext(c.chainContext)
 
 	return c
 }
@@ -28,11 +28,17 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/networkservicemesh/api/pkg/api/registry"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/export/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/goleak"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/networkservicemesh/sdk/pkg/registry/common/refresh"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/opentelemetry"
+	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
 )
 
 const testExpiryDuration = time.Millisecond * 100
@@ -119,6 +125,42 @@ func TestNewNetworkServiceEndpointRegistryClient(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestNewNetworkServiceEndpointRegistryClient_Clock(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	testClient := &testNSEClient{}
+	refreshClient := next.NewNetworkServiceEndpointRegistryClient(
+		refresh.NewNetworkServiceEndpointRegistryClient(
+			refresh.WithDefaultExpiryDuration(time.Hour),
+			refresh.WithChainContext(ctx),
+		),
+		testClient)
+
+	resp, err := refreshClient.Register(context.Background(), &registry.NetworkServiceEndpoint{
+		Name: "nse-1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, clockMock.Until(resp.ExpirationTime.AsTime()))
+
+	// NSE is refreshed in 2/3 of the expiration duration by the clockMock
+	require.Eventually(t, func() bool {
+		clockMock.Add(10 * time.Minute)
+
+		testClient.Lock()
+		defer testClient.Unlock()
+		return testClient.requestCount > 1
+	}, time.Second, testExpiryDuration/10)
+
+	_, err = refreshClient.Unregister(context.Background(), &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+}
+
 func TestRefreshNSEClient_ShouldSetExpirationTime_BeforeCallNext(t *testing.T) {
 	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
 
@@ -186,3 +228,45 @@ func Test_RefreshNSEClient_CalledRegisterTwice(t *testing.T) {
 
 	require.Nil(t, err)
 }
+
+func Test_RefreshNSEClient_LinksRefreshSpans(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	exporter := tracetest.NewInMemoryExporter()
+	closer := opentelemetry.InitOpenTelemetry(context.Background(), "test", opentelemetry.WithExporter(exporter))
+	defer func() {
+		tracing.SetProvider(nil)
+		_ = closer.Close()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	refreshClient := next.NewNetworkServiceEndpointRegistryClient(
+		refresh.NewNetworkServiceEndpointRegistryClient(
+			refresh.WithDefaultExpiryDuration(testExpiryDuration),
+			refresh.WithChainContext(ctx)),
+		&testNSEClient{})
+
+	registerCtx, span := tracing.StartSpan(context.Background(), "register")
+	_, err := refreshClient.Register(registerCtx, &registry.NetworkServiceEndpoint{
+		Name: "nse-1",
+	})
+	require.NoError(t, err)
+	span.Finish()
+
+	require.Eventually(t, func() bool {
+		return len(exporter.GetSpans()) > 2
+	}, time.Second, testExpiryDuration/4)
+
+	_, err = refreshClient.Unregister(context.Background(), &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	for i := 1; i < 3; i++ {
+		require.Equal(t, trace.SpanID{}, spans[i].ParentSpanID)
+		require.NotEqual(t, spans[0].SpanContext.TraceID, spans[i].SpanContext.TraceID)
+		require.Len(t, spans[i].Links, 1)
+		require.Equal(t, spans[i-1].SpanContext, spans[i].Links[0].SpanContext)
+	}
+}
@@ -46,7 +46,8 @@ func WithDefaultExpiryDuration(duration time.Duration) Option {
 	})
 }
 
-// WithChainContext sets a chain context
+// WithChainContext sets a chain context, refreshes are scheduled with the scheduler.Scheduler from it and are driven
+// by the clock.Clock from it
 func WithChainContext(ctx context.Context) Option {
 	return applierFunc(func(c *refreshNSEClient) {
 		c.chainContext = ctx
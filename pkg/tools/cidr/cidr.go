@@ -1,4 +1,4 @@
-// Copyright (c) 2020 Doc.ai and/or its affiliates.
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -18,7 +18,6 @@
 package cidr
 
 import (
-	"encoding/binary"
 	"net"
 )
 
@@ -28,15 +27,12 @@ func NetworkAddress(ipNet *net.IPNet) net.IP {
 	return prefixNetwork
 }
 
-// BroadcastAddress returns the last IP address of an IP network
+// BroadcastAddress returns the last IP address of an IP network, works for both IPv4 and IPv6 networks
 func BroadcastAddress(ipNet *net.IPNet) net.IP {
 	first := NetworkAddress(ipNet)
-	ones, bits := ipNet.Mask.Size()
-	var shift uint32 = 1
-	shift <<= bits - ones
-	intip := binary.BigEndian.Uint32(first.To4())
-	intip = intip + shift - 1
-	last := make(net.IP, 4)
-	binary.BigEndian.PutUint32(last, intip)
+	last := make(net.IP, len(first))
+	for i := range first {
+		last[i] = first[i] | ^ipNet.Mask[i]
+	}
 	return last
 }
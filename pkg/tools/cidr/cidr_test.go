@@ -1,4 +1,4 @@
-// Copyright (c) 2020 Doc.ai and/or its affiliates.
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -44,3 +44,13 @@ func Test32(t *testing.T) {
 	assert.Equal(t, "192.168.1.1", NetworkAddress(ipnet).String())
 	assert.Equal(t, "192.168.1.1", BroadcastAddress(ipnet).String())
 }
+
+func TestIPv6(t *testing.T) {
+	_, ipnet, _ := net.ParseCIDR("fe80::1:2/64")
+	assert.Equal(t, "fe80::", NetworkAddress(ipnet).String())
+	assert.Equal(t, "fe80::ffff:ffff:ffff:ffff", BroadcastAddress(ipnet).String())
+
+	_, ipnet, _ = net.ParseCIDR("fe80::1:2/120")
+	assert.Equal(t, "fe80::1:0", NetworkAddress(ipnet).String())
+	assert.Equal(t, "fe80::1:ff", BroadcastAddress(ipnet).String())
+}
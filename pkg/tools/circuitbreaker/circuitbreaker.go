@@ -0,0 +1,240 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package circuitbreaker provides per key circuit breakers to fail fast on the broken remote peers (e.g. NSE or
+// forwarder URLs) instead of dialing and requesting them on every attempt
+package circuitbreaker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+const (
+	defaultFailureThreshold = 5
+	defaultCooldown         = 10 * time.Second
+)
+
+// ErrOpen is returned for the calls rejected by an open circuit
+var ErrOpen = errors.New("circuit is open")
+
+// State is a circuit state
+type State int
+
+const (
+	// Closed - calls are allowed, consecutive failures are counted
+	Closed State = iota
+	// Open - calls are rejected until the cooldown passes
+	Open
+	// HalfOpen - the cooldown has passed, a single probe call is allowed to decide if the circuit should be closed
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Breakers is a set of circuit breakers, one per key
+type Breakers struct {
+	failureThreshold int
+	cooldown         time.Duration
+	clock            clock.Clock
+	breakers         sync.Map // key == string, value == *breaker
+}
+
+// NewBreakers creates a new set of circuit breakers
+func NewBreakers(options ...Option) *Breakers {
+	b := &Breakers{
+		failureThreshold: defaultFailureThreshold,
+		cooldown:         defaultCooldown,
+		clock:            clock.FromContext(context.Background()),
+	}
+	for _, opt := range options {
+		opt(b)
+	}
+	return b
+}
+
+// State returns the key circuit state
+func (b *Breakers) State(key string) State {
+	value, ok := b.breakers.Load(key)
+	if !ok {
+		return Closed
+	}
+	return value.(*breaker).state(b.clock, b.cooldown)
+}
+
+// Allow returns nil if the key call is allowed, ErrOpen otherwise. Each allowed call should be followed with the
+// Done, Success, Failure or Release call with the same key.
+func (b *Breakers) Allow(key string) error {
+	if !b.get(key).allow(b.clock, b.cooldown) {
+		return errors.Wrapf(ErrOpen, "%s", key)
+	}
+	return nil
+}
+
+// Done records the result of the key call with the ctx:
+//   - successful calls are recorded as Success
+//   - errors meaning that the key cannot be reached (Unavailable, DeadlineExceeded) are recorded as Failure
+//   - canceled calls, calls failed because ctx is done and other errors are neutral, they are only released. Other
+//     errors can be passed from the further peers, so they don't tell anything about the key.
+func (b *Breakers) Done(ctx context.Context, key string, err error) {
+	switch {
+	case err == nil:
+		b.Success(key)
+	case ctx.Err() == nil && isFailure(err):
+		b.Failure(key)
+	default:
+		b.Release(key)
+	}
+}
+
+// Success records the key call success, it closes the circuit. Closed circuits without failures are not stored.
+func (b *Breakers) Success(key string) {
+	if value, ok := b.breakers.Load(key); ok {
+		value.(*breaker).success()
+		b.breakers.Delete(key)
+	}
+}
+
+// Release releases the allowed key call without recording its result, e.g. if the call has been canceled
+func (b *Breakers) Release(key string) {
+	if value, ok := b.breakers.Load(key); ok {
+		value.(*breaker).release()
+	}
+}
+
+// Delete removes the key circuit unless it is open, e.g. when the key peer is not used anymore
+func (b *Breakers) Delete(key string) {
+	if b.State(key) != Open {
+		b.breakers.Delete(key)
+	}
+}
+
+// Failure records the key call failure, it opens the circuit after the failure threshold consecutive failures or
+// after a failed half-open probe
+func (b *Breakers) Failure(key string) {
+	b.get(key).failure(b.clock, b.failureThreshold)
+}
+
+func (b *Breakers) get(key string) *breaker {
+	if value, ok := b.breakers.Load(key); ok {
+		return value.(*breaker)
+	}
+	value, _ := b.breakers.LoadOrStore(key, new(breaker))
+	return value.(*breaker)
+}
+
+type breaker struct {
+	failures int
+	openedAt time.Time
+	open     bool
+	probing  bool
+	mutex    sync.Mutex
+}
+
+func (b *breaker) state(clk clock.Clock, cooldown time.Duration) State {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.stateLocked(clk, cooldown)
+}
+
+func (b *breaker) stateLocked(clk clock.Clock, cooldown time.Duration) State {
+	switch {
+	case !b.open:
+		return Closed
+	case b.probing || clk.Since(b.openedAt) >= cooldown:
+		return HalfOpen
+	default:
+		return Open
+	}
+}
+
+func (b *breaker) allow(clk clock.Clock, cooldown time.Duration) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.stateLocked(clk, cooldown) {
+	case Closed:
+		return true
+	case HalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *breaker) success() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures = 0
+	b.open = false
+	b.probing = false
+}
+
+func (b *breaker) release() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.probing = false
+}
+
+func (b *breaker) failure(clk clock.Clock, threshold int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures++
+	if b.probing || b.failures >= threshold {
+		b.open = true
+		b.openedAt = clk.Now()
+	}
+	b.probing = false
+}
+
+func isFailure(err error) bool {
+	cause := errors.Cause(err)
+	if cause == context.DeadlineExceeded {
+		return true
+	}
+	switch status.Code(cause) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circuitbreaker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/circuitbreaker"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+)
+
+const (
+	key      = "tcp://127.0.0.1:5000"
+	cooldown = 50 * time.Millisecond
+)
+
+func TestBreakers_OpenHalfOpenClosed(t *testing.T) {
+	clk := clockmock.NewMock()
+	b := circuitbreaker.NewBreakers(
+		circuitbreaker.WithClock(clk),
+		circuitbreaker.WithFailureThreshold(3),
+		circuitbreaker.WithCooldown(cooldown),
+	)
+	require.Equal(t, circuitbreaker.Closed, b.State(key))
+
+	// Success resets the consecutive failures
+	for i := 0; i < 2; i++ {
+		require.NoError(t, b.Allow(key))
+		b.Failure(key)
+	}
+	require.NoError(t, b.Allow(key))
+	b.Success(key)
+	require.Equal(t, circuitbreaker.Closed, b.State(key))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, b.Allow(key))
+		b.Failure(key)
+	}
+	require.Equal(t, circuitbreaker.Open, b.State(key))
+	require.True(t, errors.Is(b.Allow(key), circuitbreaker.ErrOpen))
+	require.Equal(t, circuitbreaker.Closed, b.State("another-key"))
+
+	clk.Add(cooldown - time.Millisecond)
+	require.Equal(t, circuitbreaker.Open, b.State(key))
+
+	// Failed half-open probe opens the circuit again
+	clk.Add(time.Millisecond)
+	require.Equal(t, circuitbreaker.HalfOpen, b.State(key))
+	require.NoError(t, b.Allow(key))
+	require.Error(t, b.Allow(key), "only a single probe is allowed")
+	b.Failure(key)
+	require.Equal(t, circuitbreaker.Open, b.State(key))
+
+	// Successful half-open probe closes the circuit
+	clk.Add(cooldown)
+	require.Equal(t, circuitbreaker.HalfOpen, b.State(key))
+	require.NoError(t, b.Allow(key))
+	b.Success(key)
+	require.Equal(t, circuitbreaker.Closed, b.State(key))
+	require.NoError(t, b.Allow(key))
+}
+
+func TestBreakers_Done(t *testing.T) {
+	clk := clockmock.NewMock()
+	b := circuitbreaker.NewBreakers(
+		circuitbreaker.WithClock(clk),
+		circuitbreaker.WithFailureThreshold(1),
+		circuitbreaker.WithCooldown(cooldown),
+	)
+
+	// Canceled calls and calls failed because of the caller context are neutral
+	require.NoError(t, b.Allow(key))
+	b.Done(context.Background(), key, status.Error(codes.Canceled, "canceled"))
+	require.Equal(t, circuitbreaker.Closed, b.State(key))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, b.Allow(key))
+	b.Done(ctx, key, status.Error(codes.DeadlineExceeded, "timeout"))
+	require.Equal(t, circuitbreaker.Closed, b.State(key))
+
+	// Errors returned by the reachable remote peer are neutral
+	require.NoError(t, b.Allow(key))
+	b.Done(context.Background(), key, status.Error(codes.PermissionDenied, "denied"))
+	require.Equal(t, circuitbreaker.Closed, b.State(key))
+
+	require.NoError(t, b.Allow(key))
+	b.Done(context.Background(), key, errors.Wrap(status.Error(codes.Unavailable, "unavailable"), "dial"))
+	require.Equal(t, circuitbreaker.Open, b.State(key))
+
+	// Canceled half-open probe releases the probe without closing the circuit
+	clk.Add(cooldown)
+	require.Equal(t, circuitbreaker.HalfOpen, b.State(key))
+	require.NoError(t, b.Allow(key))
+	b.Done(context.Background(), key, context.Canceled)
+	require.Equal(t, circuitbreaker.HalfOpen, b.State(key))
+	require.NoError(t, b.Allow(key))
+	b.Done(context.Background(), key, nil)
+	require.Equal(t, circuitbreaker.Closed, b.State(key))
+}
+
+func TestBreakers_Delete(t *testing.T) {
+	b := circuitbreaker.NewBreakers(
+		circuitbreaker.WithFailureThreshold(1),
+		circuitbreaker.WithCooldown(time.Hour),
+	)
+
+	require.NoError(t, b.Allow(key))
+	b.Failure(key)
+	b.Delete(key)
+	require.Equal(t, circuitbreaker.Open, b.State(key), "open circuit should not be deleted")
+
+	b = circuitbreaker.NewBreakers(circuitbreaker.WithFailureThreshold(2))
+	require.NoError(t, b.Allow(key))
+	b.Failure(key)
+	b.Delete(key)
+	require.NoError(t, b.Allow(key))
+	b.Failure(key)
+	require.Equal(t, circuitbreaker.Closed, b.State(key), "failures should be forgotten on delete")
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circuitbreaker
+
+import (
+	"context"
+)
+
+type keyKey struct{}
+
+// WithKey returns a new context with the circuit breaker key for the calls made with it. It is used when the call goes
+// to a peer other than the one the caller keys its circuits with, e.g. to a cross connect NSE instead of the endpoint.
+func WithKey(parent context.Context, key string) context.Context {
+	if parent == nil {
+		panic("cannot create context from nil parent")
+	}
+	return context.WithValue(parent, keyKey{}, key)
+}
+
+// Key returns the circuit breaker key from the context, or "" if there is no one
+func Key(ctx context.Context) string {
+	if key, ok := ctx.Value(keyKey{}).(string); ok {
+		return key
+	}
+	return ""
+}
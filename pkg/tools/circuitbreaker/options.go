@@ -0,0 +1,50 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circuitbreaker
+
+import (
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+// Option is an option pattern for NewBreakers
+type Option func(b *Breakers)
+
+// WithFailureThreshold sets the number of the consecutive failures opening the circuit. Default is 5.
+func WithFailureThreshold(failureThreshold int) Option {
+	return func(b *Breakers) {
+		if failureThreshold > 0 {
+			b.failureThreshold = failureThreshold
+		}
+	}
+}
+
+// WithCooldown sets the time the circuit stays open before allowing a half-open probe call. Default is 10s.
+func WithCooldown(cooldown time.Duration) Option {
+	return func(b *Breakers) {
+		b.cooldown = cooldown
+	}
+}
+
+// WithClock sets the clock.Clock measuring the cooldown, e.g. clock.FromContext(ctx) of the chain using the circuit
+// breakers. Default is the real time clock.
+func WithClock(clk clock.Clock) Option {
+	return func(b *Breakers) {
+		b.clock = clk
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package clock provides the time abstraction for the timer-driven chain elements, so they can be driven by a fake
+// clock in tests
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+type clockKey struct{}
+
+// Clock is the time.* functions abstraction
+type Clock interface {
+	// Now returns the current time, the same as time.Now()
+	Now() time.Time
+	// Since returns the time elapsed since t, the same as time.Since(t)
+	Since(t time.Time) time.Duration
+	// Until returns the duration until t, the same as time.Until(t)
+	Until(t time.Time) time.Duration
+
+	// Sleep pauses the current goroutine for at least the duration d, the same as time.Sleep(d)
+	Sleep(d time.Duration)
+
+	// Timer creates a new Timer sending the current time to its channel after at least duration d, the same as
+	// time.NewTimer(d)
+	Timer(d time.Duration) Timer
+	// After waits for the duration to elapse and then sends the current time on the returned channel, the same as
+	// time.After(d)
+	After(d time.Duration) <-chan time.Time
+	// AfterFunc waits for the duration to elapse and then calls f in its own goroutine, the same as
+	// time.AfterFunc(d, f)
+	AfterFunc(d time.Duration, f func()) Timer
+
+	// Ticker returns a new Ticker sending the current time to its channel every d, the same as time.NewTicker(d)
+	Ticker(d time.Duration) Ticker
+
+	// WithDeadline wraps parent in a new context canceled at the deadline, the same as
+	// context.WithDeadline(parent, deadline)
+	WithDeadline(parent context.Context, deadline time.Time) (context.Context, context.CancelFunc)
+	// WithTimeout wraps parent in a new context canceled after the timeout, the same as
+	// context.WithTimeout(parent, timeout)
+	WithTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc)
+}
+
+// Timer is the time.Timer abstraction
+type Timer interface {
+	// C returns the channel on which the time is delivered, nil for the AfterFunc timers
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing, the same as (*time.Timer).Stop()
+	Stop() bool
+	// Reset changes the timer to expire after duration d, the same as (*time.Timer).Reset(d)
+	Reset(d time.Duration) bool
+}
+
+// Ticker is the time.Ticker abstraction
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered
+	C() <-chan time.Time
+	// Stop turns off the Ticker, the same as (*time.Ticker).Stop()
+	Stop()
+}
+
+// WithClock returns a new context with the Clock
+func WithClock(parent context.Context, clock Clock) context.Context {
+	if parent == nil {
+		panic("cannot create context from nil parent")
+	}
+	return context.WithValue(parent, clockKey{}, clock)
+}
+
+// FromContext returns the Clock from the context, or the real time Clock if there is no Clock in the context
+func FromContext(ctx context.Context) Clock {
+	if clock, ok := ctx.Value(clockKey{}).(Clock); ok {
+		return clock
+	}
+	return realClock{}
+}
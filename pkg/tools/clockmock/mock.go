@@ -0,0 +1,278 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package clockmock provides the fake clock.Clock implementation advanced manually by the tests
+package clockmock
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+var _ clock.Clock = (*Mock)(nil)
+
+// Mock is a fake clock.Clock. Time doesn't pass for the Mock by itself, it only changes with Set or Add, firing all
+// the timers, tickers and context deadlines expired on the way.
+type Mock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*mockTimer
+	seq    uint64
+}
+
+// NewMock returns a new Mock set to the current time
+func NewMock() *Mock {
+	return &Mock{
+		now: time.Now(),
+	}
+}
+
+// Set sets the Mock time to t. If t is after the current Mock time, all the timers expiring before t are fired in
+// the expiration order.
+func (m *Mock) Set(t time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for {
+		timer := m.nextTimer(t)
+		if timer == nil {
+			break
+		}
+		if timer.deadline.After(m.now) {
+			m.now = timer.deadline
+		}
+		m.fire(timer)
+	}
+	m.now = t
+}
+
+// Add adds d to the Mock time, see Set
+func (m *Mock) Add(d time.Duration) {
+	m.Set(m.Now().Add(d))
+}
+
+// Now returns the Mock time
+func (m *Mock) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.now
+}
+
+// Since returns the Mock time elapsed since t
+func (m *Mock) Since(t time.Time) time.Duration {
+	return m.Now().Sub(t)
+}
+
+// Until returns the Mock time duration until t
+func (m *Mock) Until(t time.Time) time.Duration {
+	return t.Sub(m.Now())
+}
+
+// Sleep blocks until the Mock time is advanced by d
+func (m *Mock) Sleep(d time.Duration) {
+	<-m.After(d)
+}
+
+// Timer returns a new Timer firing when the Mock time is advanced by d
+func (m *Mock) Timer(d time.Duration) clock.Timer {
+	return m.newTimer(d, 0, make(chan time.Time, 1), nil)
+}
+
+// After returns a channel receiving the Mock time when it is advanced by d
+func (m *Mock) After(d time.Duration) <-chan time.Time {
+	return m.Timer(d).C()
+}
+
+// AfterFunc returns a new Timer calling f in its own goroutine when the Mock time is advanced by d
+func (m *Mock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	return m.newTimer(d, 0, nil, f)
+}
+
+// Ticker returns a new Ticker ticking every time the Mock time is advanced by d
+func (m *Mock) Ticker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("non-positive interval for Ticker")
+	}
+	return &mockTicker{
+		mockTimer: m.newTimer(d, d, make(chan time.Time, 1), nil),
+	}
+}
+
+// WithDeadline returns a new context canceled when the Mock time reaches the deadline
+func (m *Mock) WithDeadline(parent context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	cancelCtx, cancel := context.WithCancel(parent)
+	ctx := &deadlineCtx{
+		Context:  cancelCtx,
+		deadline: deadline,
+	}
+	timer := m.AfterFunc(m.Until(deadline), func() {
+		ctx.setErr(context.DeadlineExceeded)
+		cancel()
+	})
+	return ctx, func() {
+		timer.Stop()
+		ctx.setErr(context.Canceled)
+		cancel()
+	}
+}
+
+// WithTimeout returns a new context canceled when the Mock time is advanced by timeout
+func (m *Mock) WithTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return m.WithDeadline(parent, m.Now().Add(timeout))
+}
+
+func (m *Mock) newTimer(d, period time.Duration, ch chan time.Time, f func()) *mockTimer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	timer := &mockTimer{
+		mock:   m,
+		ch:     ch,
+		f:      f,
+		period: period,
+	}
+	m.schedule(timer, d)
+	return timer
+}
+
+// schedule adds timer to fire after d, timers with non-positive d are fired at once
+func (m *Mock) schedule(timer *mockTimer, d time.Duration) {
+	m.seq++
+	timer.seq = m.seq
+	timer.deadline = m.now.Add(d)
+	if d <= 0 {
+		m.fire(timer)
+		return
+	}
+	m.timers = append(m.timers, timer)
+}
+
+// unschedule removes timer, returns false if it is not scheduled
+func (m *Mock) unschedule(timer *mockTimer) bool {
+	for i := range m.timers {
+		if m.timers[i] == timer {
+			m.timers = append(m.timers[:i], m.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// nextTimer returns the first timer expiring not after t
+func (m *Mock) nextTimer(t time.Time) *mockTimer {
+	sort.Slice(m.timers, func(i, k int) bool {
+		if m.timers[i].deadline.Equal(m.timers[k].deadline) {
+			return m.timers[i].seq < m.timers[k].seq
+		}
+		return m.timers[i].deadline.Before(m.timers[k].deadline)
+	})
+	if len(m.timers) == 0 || m.timers[0].deadline.After(t) {
+		return nil
+	}
+	return m.timers[0]
+}
+
+// fire fires timer and schedules the next tick if it is a ticker
+func (m *Mock) fire(timer *mockTimer) {
+	m.unschedule(timer)
+	if timer.f != nil {
+		go timer.f()
+	} else {
+		// Same as time.Timer and time.Ticker, drop the value if the previous one is not received yet
+		select {
+		case timer.ch <- m.now:
+		default:
+		}
+	}
+	if timer.period > 0 {
+		m.seq++
+		timer.seq = m.seq
+		timer.deadline = timer.deadline.Add(timer.period)
+		m.timers = append(m.timers, timer)
+	}
+}
+
+type mockTimer struct {
+	mock     *Mock
+	ch       chan time.Time
+	f        func()
+	deadline time.Time
+	period   time.Duration
+	seq      uint64
+}
+
+func (t *mockTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *mockTimer) Stop() bool {
+	t.mock.mu.Lock()
+	defer t.mock.mu.Unlock()
+
+	return t.mock.unschedule(t)
+}
+
+func (t *mockTimer) Reset(d time.Duration) bool {
+	t.mock.mu.Lock()
+	defer t.mock.mu.Unlock()
+
+	active := t.mock.unschedule(t)
+	t.mock.schedule(t, d)
+	return active
+}
+
+type mockTicker struct {
+	*mockTimer
+}
+
+func (t *mockTicker) Stop() {
+	t.mockTimer.Stop()
+}
+
+type deadlineCtx struct {
+	context.Context
+	deadline time.Time
+	mu       sync.Mutex
+	err      error
+}
+
+func (ctx *deadlineCtx) Deadline() (time.Time, bool) {
+	return ctx.deadline, true
+}
+
+func (ctx *deadlineCtx) Err() error {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	if ctx.err != nil {
+		return ctx.err
+	}
+	return ctx.Context.Err()
+}
+
+func (ctx *deadlineCtx) setErr(err error) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	if ctx.err == nil && ctx.Context.Err() == nil {
+		ctx.err = err
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clockmock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+)
+
+const (
+	timeout = time.Second
+	hour    = time.Hour
+)
+
+func TestMock_SetAdd(t *testing.T) {
+	m := clockmock.NewMock()
+
+	start := m.Now()
+	m.Add(hour)
+	require.Equal(t, start.Add(hour), m.Now())
+	require.Equal(t, hour, m.Since(start))
+	require.Equal(t, hour, m.Until(start.Add(2*hour)))
+
+	m.Set(start)
+	require.Equal(t, start, m.Now())
+}
+
+func TestMock_Timer(t *testing.T) {
+	m := clockmock.NewMock()
+
+	timer := m.Timer(hour)
+	m.Add(hour - 1)
+	select {
+	case <-timer.C():
+		require.FailNow(t, "too early")
+	default:
+	}
+
+	m.Add(1)
+	select {
+	case now := <-timer.C():
+		require.Equal(t, m.Now(), now)
+	default:
+		require.FailNow(t, "not fired")
+	}
+	require.False(t, timer.Stop())
+
+	require.False(t, timer.Reset(hour))
+	require.True(t, timer.Stop())
+	m.Add(hour)
+	select {
+	case <-timer.C():
+		require.FailNow(t, "fired after Stop")
+	default:
+	}
+}
+
+func TestMock_AfterFunc(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	m := clockmock.NewMock()
+
+	var fired []int
+	firedCh := make(chan int)
+	for i := 3; i > 0; i-- {
+		i := i
+		m.AfterFunc(time.Duration(i)*hour, func() {
+			firedCh <- i
+		})
+	}
+
+	go m.Add(3 * hour)
+	for i := 0; i < 3; i++ {
+		select {
+		case n := <-firedCh:
+			fired = append(fired, n)
+		case <-time.After(timeout):
+			require.FailNow(t, "timeout waiting for AfterFunc")
+		}
+	}
+	require.ElementsMatch(t, []int{1, 2, 3}, fired)
+}
+
+func TestMock_Ticker(t *testing.T) {
+	m := clockmock.NewMock()
+
+	ticker := m.Ticker(hour)
+	defer ticker.Stop()
+
+	for i := 0; i < 3; i++ {
+		m.Add(hour)
+		select {
+		case <-ticker.C():
+		default:
+			require.FailNow(t, "no tick")
+		}
+	}
+}
+
+func TestMock_WithTimeout(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	m := clockmock.NewMock()
+
+	ctx, cancel := m.WithTimeout(context.Background(), hour)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	require.Equal(t, m.Now().Add(hour), deadline)
+
+	m.Add(hour - 1)
+	require.NoError(t, ctx.Err())
+
+	m.Add(1)
+	select {
+	case <-ctx.Done():
+	case <-time.After(timeout):
+		require.FailNow(t, "timeout waiting for the deadline")
+	}
+	require.Equal(t, context.DeadlineExceeded, ctx.Err())
+
+	cancelCtx, cancel := m.WithTimeout(context.Background(), hour)
+	cancel()
+	require.Equal(t, context.Canceled, cancelCtx.Err())
+}
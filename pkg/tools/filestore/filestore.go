@@ -0,0 +1,122 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filestore provides a directory of the files keyed by the string IDs. Files are written atomically, so the
+// interrupted write never leaves a partially written file.
+package filestore
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+)
+
+const (
+	fileExt    = ".json"
+	tmpFileExt = ".tmp"
+)
+
+// Store keeps the data for each ID in a separate file in the directory
+type Store struct {
+	dir string
+}
+
+// New - creates a new Store keeping the files in dir
+//       dir - directory to store files in, is created if it doesn't exist
+func New(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, errors.Wrapf(err, "failed to create store directory: %s", dir)
+	}
+	return &Store{
+		dir: dir,
+	}, nil
+}
+
+// Save stores the data for the id, replacing the previously stored one
+func (s *Store) Save(id string, data []byte) error {
+	// Write to the temporary file and rename it to the target one to never leave partially written files
+	filePath := s.filePath(id)
+	tmpFilePath := filePath + tmpFileExt
+	if err := ioutil.WriteFile(tmpFilePath, data, 0o600); err != nil {
+		return errors.Wrapf(err, "failed to write file: %s", tmpFilePath)
+	}
+	if err := os.Rename(tmpFilePath, filePath); err != nil {
+		_ = os.Remove(tmpFilePath)
+		return errors.Wrapf(err, "failed to rename file: %s", tmpFilePath)
+	}
+	return nil
+}
+
+// Delete removes the data for the id, does nothing if there is no such data
+func (s *Store) Delete(id string) error {
+	filePath := s.filePath(id)
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove file: %s", filePath)
+	}
+	return nil
+}
+
+// Load calls decode for the data of each stored file. Files failed to decode are logged and removed, the same as the
+// temporary files left by the interrupted Save. Files failed to read are logged and kept, so they can be loaded later.
+func (s *Store) Load(ctx context.Context, decode func(data []byte) error) error {
+	logEntry := logger.Log(ctx).WithField("filestore", "Load")
+
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read store directory: %s", s.dir)
+	}
+
+	for _, file := range files {
+		filePath := filepath.Join(s.dir, file.Name())
+		switch {
+		case file.IsDir():
+			continue
+		case strings.HasSuffix(file.Name(), tmpFileExt):
+			// Temporary files are left only by the interrupted Save, so they are never complete
+			logEntry.Warnf("removing temporary file: %s", filePath)
+			removeFile(ctx, filePath)
+		case strings.HasSuffix(file.Name(), fileExt):
+			data, readErr := ioutil.ReadFile(filePath) // #nosec
+			if readErr != nil {
+				logEntry.Errorf("skipping file failed to read: %s %+v", filePath, readErr)
+				continue
+			}
+			if decodeErr := decode(data); decodeErr != nil {
+				logEntry.Errorf("removing file failed to decode: %s %+v", filePath, decodeErr)
+				removeFile(ctx, filePath)
+			}
+		}
+	}
+
+	return nil
+}
+
+func removeFile(ctx context.Context, filePath string) {
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		logger.Log(ctx).WithField("filestore", "removeFile").Errorf("failed to remove file: %s %+v", filePath, err)
+	}
+}
+
+func (s *Store) filePath(id string) string {
+	return filepath.Join(s.dir, base64.RawURLEncoding.EncodeToString([]byte(id))+fileExt)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestore_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/filestore"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+)
+
+type entry struct {
+	ID string `json:"id"`
+}
+
+func load(ctx context.Context, t *testing.T, store *filestore.Store) []string {
+	var ids []string
+	require.NoError(t, store.Load(ctx, func(data []byte) error {
+		e := new(entry)
+		if err := json.Unmarshal(data, e); err != nil {
+			return err
+		}
+		ids = append(ids, e.ID)
+		return nil
+	}))
+	return ids
+}
+
+func TestStore(t *testing.T) {
+	ctx := logger.WithLog(context.Background())
+
+	store, err := filestore.New(filepath.Join(t.TempDir(), "store"))
+	require.NoError(t, err)
+
+	for _, id := range []string{"id-1", "id/2"} {
+		data, marshalErr := json.Marshal(&entry{ID: id})
+		require.NoError(t, marshalErr)
+		require.NoError(t, store.Save(id, data))
+	}
+	require.ElementsMatch(t, []string{"id-1", "id/2"}, load(ctx, t, store))
+
+	require.NoError(t, store.Delete("id-1"))
+	require.NoError(t, store.Delete("not-stored"))
+	require.Equal(t, []string{"id/2"}, load(ctx, t, store))
+}
+
+func TestStore_BadFiles(t *testing.T) {
+	ctx := logger.WithLog(context.Background())
+	dir := t.TempDir()
+
+	store, err := filestore.New(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Save("id", []byte(`{"id":"id"}`)))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "bad.json"), []byte("{"), 0o600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "interrupted.json.tmp"), []byte("{}"), 0o600))
+	require.NoError(t, os.Symlink(filepath.Join(dir, "missing"), filepath.Join(dir, "unreadable.json")))
+
+	require.Equal(t, []string{"id"}, load(ctx, t, store))
+
+	// Bad and temporary files are removed, unreadable files are kept
+	var names []string
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	require.Len(t, names, 2)
+	require.Contains(t, names, "unreadable.json")
+}
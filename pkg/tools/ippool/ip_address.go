@@ -0,0 +1,102 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ippool
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+// ipAddress is a 128-bit IP address, IPv4 addresses are stored in the IPv4-mapped IPv6 form
+type ipAddress struct {
+	high, low uint64
+}
+
+func ipAddressFromIP(ip net.IP) ipAddress {
+	ip = ip.To16()
+	return ipAddress{
+		high: binary.BigEndian.Uint64(ip[:8]),
+		low:  binary.BigEndian.Uint64(ip[8:]),
+	}
+}
+
+func (a ipAddress) toIP() net.IP {
+	ip := make(net.IP, net.IPv6len)
+	binary.BigEndian.PutUint64(ip[:8], a.high)
+	binary.BigEndian.PutUint64(ip[8:], a.low)
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4
+	}
+	return ip
+}
+
+func (a ipAddress) less(b ipAddress) bool {
+	return a.high < b.high || a.high == b.high && a.low < b.low
+}
+
+func (a ipAddress) lessOrEqual(b ipAddress) bool {
+	return !b.less(a)
+}
+
+// next returns a + 1, ok is false on the overflow
+func (a ipAddress) next() (rv ipAddress, ok bool) {
+	if a.low == ^uint64(0) {
+		if a.high == ^uint64(0) {
+			return a, false
+		}
+		return ipAddress{high: a.high + 1}, true
+	}
+	return ipAddress{high: a.high, low: a.low + 1}, true
+}
+
+// prev returns a - 1, ok is false on the underflow
+func (a ipAddress) prev() (rv ipAddress, ok bool) {
+	if a.low == 0 {
+		if a.high == 0 {
+			return a, false
+		}
+		return ipAddress{high: a.high - 1, low: ^uint64(0)}, true
+	}
+	return ipAddress{high: a.high, low: a.low - 1}, true
+}
+
+// ipRange is a range of the IP addresses [start, end]
+type ipRange struct {
+	start, end ipAddress
+}
+
+func ipRangeFromIPNet(ipNet *net.IPNet) ipRange {
+	start := ipAddressFromIP(ipNet.IP.Mask(ipNet.Mask))
+
+	ones, bits := ipNet.Mask.Size()
+	hostBits := uint(bits - ones)
+
+	end := start
+	switch {
+	case hostBits >= 128:
+		end = ipAddress{high: ^uint64(0), low: ^uint64(0)}
+	case hostBits > 64:
+		end.high |= 1<<(hostBits-64) - 1
+		end.low = ^uint64(0)
+	case hostBits == 64:
+		end.low = ^uint64(0)
+	default:
+		end.low |= 1<<hostBits - 1
+	}
+
+	return ipRange{start: start, end: end}
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ippool provides a thread safe pool of the IPv4 and IPv6 addresses. Addresses are stored as the sorted
+// ranges of the 128-bit integers, so the pool scales to the large IPv6 networks.
+package ippool
+
+import (
+	"net"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// IPPool is a pool of the IP addresses
+type IPPool struct {
+	// ranges are sorted, non overlapping and non adjacent
+	ranges []ipRange
+	mu     sync.Mutex
+}
+
+// New - creates a new empty IPPool
+func New() *IPPool {
+	return new(IPPool)
+}
+
+// NewWithNet - creates a new IPPool with all the addresses of ipNet
+func NewWithNet(ipNet *net.IPNet) *IPPool {
+	p := New()
+	p.AddNet(ipNet)
+	return p
+}
+
+// NewWithNetString - creates a new IPPool with all the addresses of the networks in CIDR notation
+func NewWithNetString(ipNets ...string) (*IPPool, error) {
+	p := New()
+	for _, s := range ipNets {
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s as CIDR", s)
+		}
+		p.AddNet(ipNet)
+	}
+	return p, nil
+}
+
+// AddNet - adds all the addresses of ipNet to the pool
+func (p *IPPool) AddNet(ipNet *net.IPNet) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.ranges = addRange(p.ranges, ipRangeFromIPNet(ipNet))
+}
+
+// Add - adds ip to the pool
+func (p *IPPool) Add(ip net.IP) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	addr := ipAddressFromIP(ip)
+	p.ranges = addRange(p.ranges, ipRange{start: addr, end: addr})
+}
+
+// RemoveNet - removes all the addresses of ipNet from the pool
+func (p *IPPool) RemoveNet(ipNet *net.IPNet) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.ranges = removeRange(p.ranges, ipRangeFromIPNet(ipNet))
+}
+
+// Contains - returns true if ip is in the pool
+func (p *IPPool) Contains(ip net.IP) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.contains(ipAddressFromIP(ip))
+}
+
+// Empty - returns true if there are no addresses in the pool
+func (p *IPPool) Empty() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.ranges) == 0
+}
+
+// Pull - removes the lowest address not in exclude from the pool and returns it. exclude could be nil.
+func (p *IPPool) Pull(exclude *IPPool) (net.IP, error) {
+	excludeRanges := exclude.snapshot()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	addr, err := p.find(excludeRanges)
+	if err != nil {
+		return nil, err
+	}
+	p.ranges = removeRange(p.ranges, ipRange{start: addr, end: addr})
+
+	return addr.toIP(), nil
+}
+
+// PullIP - removes ip from the pool, returns an error if ip is not in the pool
+func (p *IPPool) PullIP(ip net.IP) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	addr := ipAddressFromIP(ip)
+	if !p.contains(addr) {
+		return errors.Errorf("IP %v is not available in the pool", ip)
+	}
+	p.ranges = removeRange(p.ranges, ipRange{start: addr, end: addr})
+
+	return nil
+}
+
+// PullP2PAddrs - removes two lowest addresses not in exclude from the pool and returns them. exclude could be nil.
+func (p *IPPool) PullP2PAddrs(exclude *IPPool) (dstIP, srcIP net.IP, err error) {
+	excludeRanges := exclude.snapshot()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	dstAddr, err := p.find(excludeRanges)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	excludeRanges = addRange(excludeRanges, ipRange{start: dstAddr, end: dstAddr})
+	srcAddr, err := p.find(excludeRanges)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p.ranges = removeRange(p.ranges, ipRange{start: dstAddr, end: dstAddr})
+	p.ranges = removeRange(p.ranges, ipRange{start: srcAddr, end: srcAddr})
+
+	return dstAddr.toIP(), srcAddr.toIP(), nil
+}
+
+func (p *IPPool) snapshot() []ipRange {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]ipRange(nil), p.ranges...)
+}
+
+// contains should be called under the p.mu lock
+func (p *IPPool) contains(addr ipAddress) bool {
+	for _, r := range p.ranges {
+		if r.start.lessOrEqual(addr) && addr.lessOrEqual(r.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// find returns the lowest address in the pool not in exclude, it should be called under the p.mu lock
+func (p *IPPool) find(exclude []ipRange) (ipAddress, error) {
+	if len(p.ranges) == 0 {
+		return ipAddress{}, errors.New("IP pool is empty")
+	}
+
+	j := 0
+	for _, r := range p.ranges {
+		candidate := r.start
+		for {
+			for j < len(exclude) && exclude[j].end.less(candidate) {
+				j++
+			}
+			if j == len(exclude) || candidate.less(exclude[j].start) {
+				return candidate, nil
+			}
+			// candidate is excluded, so try the first address after the exclude range
+			next, ok := exclude[j].end.next()
+			if !ok || r.end.less(next) {
+				break
+			}
+			candidate = next
+		}
+	}
+
+	return ipAddress{}, errors.New("all free IP addresses are excluded")
+}
+
+// addRange adds r to the sorted ranges merging the overlapping and adjacent ones
+func addRange(ranges []ipRange, r ipRange) []ipRange {
+	rv := make([]ipRange, 0, len(ranges)+1)
+
+	i := 0
+	for ; i < len(ranges) && isBefore(ranges[i], r); i++ {
+		rv = append(rv, ranges[i])
+	}
+	for ; i < len(ranges) && !isBefore(r, ranges[i]); i++ {
+		if ranges[i].start.less(r.start) {
+			r.start = ranges[i].start
+		}
+		if r.end.less(ranges[i].end) {
+			r.end = ranges[i].end
+		}
+	}
+	rv = append(rv, r)
+
+	return append(rv, ranges[i:]...)
+}
+
+// removeRange removes r from the sorted ranges splitting the partially overlapping ones
+func removeRange(ranges []ipRange, r ipRange) []ipRange {
+	rv := make([]ipRange, 0, len(ranges)+1)
+
+	for _, x := range ranges {
+		if x.end.less(r.start) || r.end.less(x.start) {
+			rv = append(rv, x)
+			continue
+		}
+		if x.start.less(r.start) {
+			end, _ := r.start.prev()
+			rv = append(rv, ipRange{start: x.start, end: end})
+		}
+		if r.end.less(x.end) {
+			start, _ := r.end.next()
+			rv = append(rv, ipRange{start: start, end: x.end})
+		}
+	}
+
+	return rv
+}
+
+// isBefore returns true if a is before b and they are not adjacent
+func isBefore(a, b ipRange) bool {
+	next, ok := a.end.next()
+	return ok && next.less(b.start)
+}
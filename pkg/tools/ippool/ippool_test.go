@@ -0,0 +1,121 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ippool_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/ippool"
+)
+
+func newPool(t *testing.T, ipNets ...string) *ippool.IPPool {
+	p, err := ippool.NewWithNetString(ipNets...)
+	require.NoError(t, err)
+	return p
+}
+
+func TestIPPool_Pull(t *testing.T) {
+	p := newPool(t, "192.168.0.0/31")
+
+	ip, err := p.Pull(nil)
+	require.NoError(t, err)
+	require.Equal(t, "192.168.0.0", ip.String())
+
+	ip, err = p.Pull(nil)
+	require.NoError(t, err)
+	require.Equal(t, "192.168.0.1", ip.String())
+
+	_, err = p.Pull(nil)
+	require.Error(t, err)
+	require.True(t, p.Empty())
+
+	p.Add(net.ParseIP("192.168.0.1"))
+	ip, err = p.Pull(nil)
+	require.NoError(t, err)
+	require.Equal(t, "192.168.0.1", ip.String())
+}
+
+func TestIPPool_PullP2PAddrs_IPv6(t *testing.T) {
+	p := newPool(t, "fe80::/64")
+	exclude := newPool(t, "fe80::/127", "fe80::3/128")
+
+	dstIP, srcIP, err := p.PullP2PAddrs(exclude)
+	require.NoError(t, err)
+	require.Equal(t, "fe80::2", dstIP.String())
+	require.Equal(t, "fe80::4", srcIP.String())
+
+	dstIP, srcIP, err = p.PullP2PAddrs(nil)
+	require.NoError(t, err)
+	require.Equal(t, "fe80::", dstIP.String())
+	require.Equal(t, "fe80::1", srcIP.String())
+
+	p.Add(dstIP)
+	require.True(t, p.Contains(net.ParseIP("fe80::")))
+	require.False(t, p.Contains(net.ParseIP("fe80::1")))
+	require.True(t, p.Contains(net.ParseIP("fe80::ffff:ffff:ffff:ffff")))
+	require.False(t, p.Contains(net.ParseIP("fe80:0:0:1::")))
+}
+
+func TestIPPool_Exclude(t *testing.T) {
+	p := newPool(t, "10.0.0.0/30", "10.0.1.0/30")
+
+	// IPv4 and IPv6 excludes don't intersect
+	exclude := newPool(t, "10.0.0.0/30", "10.0.1.0/32", "::a00:101/128")
+
+	ip, err := p.Pull(exclude)
+	require.NoError(t, err)
+	require.Equal(t, "10.0.1.1", ip.String())
+
+	exclude = newPool(t, "10.0.0.0/16")
+	_, _, err = p.PullP2PAddrs(exclude)
+	require.Error(t, err)
+	require.False(t, p.Empty())
+}
+
+func TestIPPool_RemoveNet(t *testing.T) {
+	p := newPool(t, "10.0.0.0/24")
+
+	_, ipNet, err := net.ParseCIDR("10.0.0.0/25")
+	require.NoError(t, err)
+	p.RemoveNet(ipNet)
+
+	ip, err := p.Pull(nil)
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.128", ip.String())
+
+	require.NoError(t, p.PullIP(net.ParseIP("10.0.0.200")))
+	require.Error(t, p.PullIP(net.ParseIP("10.0.0.200")))
+	require.Error(t, p.PullIP(net.ParseIP("10.0.0.1")))
+	require.True(t, p.Contains(net.ParseIP("10.0.0.199")))
+	require.True(t, p.Contains(net.ParseIP("10.0.0.201")))
+}
+
+func TestIPPool_WholeAddressSpace(t *testing.T) {
+	p := newPool(t, "::/0")
+	exclude := newPool(t, "::/1")
+
+	ip, err := p.Pull(exclude)
+	require.NoError(t, err)
+	require.Equal(t, "8000::", ip.String())
+
+	exclude = newPool(t, "::/0")
+	_, err = p.Pull(exclude)
+	require.Error(t, err)
+}
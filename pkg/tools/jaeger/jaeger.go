@@ -24,36 +24,21 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"strconv"
 
 	"github.com/networkservicemesh/sdk/pkg/tools/logger/logruslogger"
+	sdkopentracing "github.com/networkservicemesh/sdk/pkg/tools/opentracing"
+	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/uber/jaeger-client-go"
 	"github.com/uber/jaeger-client-go/config"
 )
 
-const (
-	opentracingEnv     = "TRACER_ENABLED"
-	opentracingDefault = true
-)
-
 // IsOpentracingEnabled returns true if opentracing enabled
+//
+// Deprecated: use tracing.IsEnabled instead
 func IsOpentracingEnabled() bool {
-	val, err := readEnvBool(opentracingEnv, opentracingDefault)
-	if err == nil {
-		return val
-	}
-	return opentracingDefault
-}
-
-func readEnvBool(env string, value bool) (bool, error) {
-	str := os.Getenv(env)
-	if str == "" {
-		return value, nil
-	}
-
-	return strconv.ParseBool(str)
+	return tracing.IsEnabled()
 }
 
 type emptyCloser struct {
@@ -65,8 +50,9 @@ func (*emptyCloser) Close() error {
 }
 
 // InitJaeger -  returns an instance of Jaeger Tracer that samples 100% of traces and logs all spans to stdout.
+// The tracer is set as the global opentracing tracer and as the global tracing.Provider.
 func InitJaeger(service string) io.Closer {
-	if !IsOpentracingEnabled() {
+	if !tracing.IsEnabled() {
 		return &emptyCloser{}
 	}
 	_, log := logruslogger.New(context.Background())
@@ -104,5 +90,6 @@ func InitJaeger(service string) io.Closer {
 		return &emptyCloser{}
 	}
 	opentracing.SetGlobalTracer(tracer)
+	tracing.SetProvider(sdkopentracing.NewProvider(tracer))
 	return closer
 }
@@ -0,0 +1,216 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package labelselector provides a parser and a matcher for the set-based label selectors used in the NetworkService
+// matches. Each selector entry is a requirement for the label with the entry key:
+//
+//	key: value                 - label is equal to the value (missing label is equal to the empty value)
+//	key: in(value1, value2)    - label is set and is equal to one of the values
+//	key: notin(value1, value2) - label is not set or is not equal to any of the values
+//	key: exists()              - label is set
+//	key: doesnotexist()        - label is not set
+//
+// Operator names are case insensitive. Values looking like an unknown operator (e.g. "v1(beta)") are equality
+// requirements. To require a label equal to a value looking like a known operator, escape it with "=" prefix:
+//
+//	key: =in(value1)           - label is equal to "in(value1)"
+package labelselector
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Operator is a label requirement operator
+type Operator string
+
+const (
+	// Equals - label is equal to the value
+	Equals Operator = "="
+	// In - label is set and is equal to one of the values
+	In Operator = "in"
+	// NotIn - label is not set or is not equal to any of the values
+	NotIn Operator = "notin"
+	// Exists - label is set
+	Exists Operator = "exists"
+	// DoesNotExist - label is not set
+	DoesNotExist Operator = "doesnotexist"
+)
+
+const escapePrefix = "="
+
+var operatorRegexp = regexp.MustCompile(`^\s*([[:alpha:]]+)\s*\((.*)\)\s*$`)
+
+// Requirement is a single label requirement
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+// Selector is a set of label requirements, all of them should be met for a match
+type Selector []*Requirement
+
+// Parse parses and validates the selector
+func Parse(selector map[string]string) (Selector, error) {
+	keys := make([]string, 0, len(selector))
+	for key := range selector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var rv Selector
+	for _, key := range keys {
+		requirement, err := ParseRequirement(key, selector[key])
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, requirement)
+	}
+	return rv, nil
+}
+
+// ParseRequirement parses and validates the requirement for the key label
+func ParseRequirement(key, value string) (*Requirement, error) {
+	if key == "" {
+		return nil, errors.Errorf("empty label key in requirement: %q", value)
+	}
+
+	equals := &Requirement{
+		Key:      key,
+		Operator: Equals,
+		Values:   []string{value},
+	}
+
+	if escaped := strings.TrimPrefix(value, escapePrefix); escaped != value && isOperator(escaped) {
+		equals.Values[0] = escaped
+		return equals, nil
+	}
+
+	submatches := operatorRegexp.FindStringSubmatch(value)
+	if submatches == nil {
+		return equals, nil
+	}
+
+	operator := Operator(strings.ToLower(submatches[1]))
+	args := strings.TrimSpace(submatches[2])
+	switch operator {
+	case In, NotIn:
+		if args == "" {
+			return nil, errors.Errorf("%s requires at least one value: %s: %q", operator, key, value)
+		}
+		var values []string
+		for _, arg := range strings.Split(args, ",") {
+			if arg = strings.TrimSpace(arg); arg == "" {
+				return nil, errors.Errorf("empty value in %s: %s: %q", operator, key, value)
+			}
+			values = append(values, arg)
+		}
+		return &Requirement{
+			Key:      key,
+			Operator: operator,
+			Values:   values,
+		}, nil
+	case Exists, DoesNotExist:
+		if args != "" {
+			return nil, errors.Errorf("%s requires no values: %s: %q", operator, key, value)
+		}
+		return &Requirement{
+			Key:      key,
+			Operator: operator,
+		}, nil
+	default:
+		return equals, nil
+	}
+}
+
+func isOperator(value string) bool {
+	submatches := operatorRegexp.FindStringSubmatch(value)
+	if submatches == nil {
+		return false
+	}
+	switch Operator(strings.ToLower(submatches[1])) {
+	case In, NotIn, Exists, DoesNotExist:
+		return true
+	default:
+		return false
+	}
+}
+
+// Matches returns true if labels meet the requirement
+func (r *Requirement) Matches(labels map[string]string) bool {
+	value, ok := labels[r.Key]
+	switch r.Operator {
+	case Equals:
+		return value == r.Values[0]
+	case In:
+		return ok && contains(r.Values, value)
+	case NotIn:
+		return !ok || !contains(r.Values, value)
+	case Exists:
+		return ok
+	case DoesNotExist:
+		return !ok
+	default:
+		return false
+	}
+}
+
+// Matches returns true if labels meet all the requirements
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, requirement := range s {
+		if !requirement.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Unmatched returns the requirements labels don't meet
+func (s Selector) Unmatched(labels map[string]string) Selector {
+	var rv Selector
+	for _, requirement := range s {
+		if !requirement.Matches(labels) {
+			rv = append(rv, requirement)
+		}
+	}
+	return rv
+}
+
+// String returns the requirement in the selector syntax
+func (r *Requirement) String() string {
+	switch r.Operator {
+	case Equals:
+		if isOperator(r.Values[0]) {
+			return r.Key + ": " + escapePrefix + r.Values[0]
+		}
+		return r.Key + ": " + r.Values[0]
+	default:
+		return r.Key + ": " + string(r.Operator) + "(" + strings.Join(r.Values, ", ") + ")"
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labelselector_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/labelselector"
+)
+
+func TestParseRequirement(t *testing.T) {
+	samples := []struct {
+		value    string
+		operator labelselector.Operator
+		values   []string
+		err      bool
+	}{
+		{value: "firewall", operator: labelselector.Equals, values: []string{"firewall"}},
+		{value: "", operator: labelselector.Equals, values: []string{""}},
+		{value: "in(a, b ,c)", operator: labelselector.In, values: []string{"a", "b", "c"}},
+		{value: " NotIn ( a ) ", operator: labelselector.NotIn, values: []string{"a"}},
+		{value: "exists()", operator: labelselector.Exists},
+		{value: "DoesNotExist()", operator: labelselector.DoesNotExist},
+		{value: "in()", err: true},
+		{value: "notin(a,,b)", err: true},
+		{value: "exists(a)", err: true},
+		{value: "v1(beta)", operator: labelselector.Equals, values: []string{"v1(beta)"}},
+		{value: "=in(a)", operator: labelselector.Equals, values: []string{"in(a)"}},
+		{value: "=exists()", operator: labelselector.Equals, values: []string{"exists()"}},
+		{value: "=v1", operator: labelselector.Equals, values: []string{"=v1"}},
+	}
+
+	for _, sample := range samples {
+		requirement, err := labelselector.ParseRequirement("key", sample.value)
+		if sample.err {
+			require.Error(t, err, sample.value)
+			continue
+		}
+		require.NoError(t, err, sample.value)
+		require.Equal(t, sample.operator, requirement.Operator, sample.value)
+		require.Equal(t, sample.values, requirement.Values, sample.value)
+	}
+
+	_, err := labelselector.ParseRequirement("", "value")
+	require.Error(t, err)
+}
+
+func TestRequirement_StringEscaped(t *testing.T) {
+	requirement, err := labelselector.ParseRequirement("key", "=in(a)")
+	require.NoError(t, err)
+	require.Equal(t, "key: =in(a)", requirement.String())
+
+	require.True(t, requirement.Matches(map[string]string{"key": "in(a)"}))
+	require.False(t, requirement.Matches(map[string]string{"key": "a"}))
+}
+
+func TestSelector_Matches(t *testing.T) {
+	selector, err := labelselector.Parse(map[string]string{
+		"app":     "in(firewall, vpn)",
+		"zone":    "notin(us-west)",
+		"gpu":     "exists()",
+		"debug":   "doesnotexist()",
+		"version": "v1",
+	})
+	require.NoError(t, err)
+	require.Len(t, selector, 5)
+
+	labels := map[string]string{
+		"app":     "vpn",
+		"gpu":     "",
+		"version": "v1",
+	}
+	require.True(t, selector.Matches(labels))
+	require.Empty(t, selector.Unmatched(labels))
+
+	labels = map[string]string{
+		"app":     "proxy",
+		"zone":    "us-west",
+		"debug":   "true",
+		"version": "v1",
+	}
+	require.False(t, selector.Matches(labels))
+
+	var unmatched []string
+	for _, requirement := range selector.Unmatched(labels) {
+		unmatched = append(unmatched, requirement.String())
+	}
+	require.Equal(t, []string{"app: in(firewall, vpn)", "debug: doesnotexist()", "gpu: exists()", "zone: notin(us-west)"}, unmatched)
+}
@@ -27,11 +27,11 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/opentracing/opentracing-go"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/metadata"
 
 	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
 )
 
 type loggerKeyType string
@@ -94,14 +94,17 @@ func fromContext(ctx context.Context) *traceCtxInfo {
 
 type logrusLogger struct {
 	entry     *logrus.Entry
-	span      opentracing.Span
+	span      tracing.Span
 	info      *traceCtxInfo
 	operation string
 }
 
 func (s *logrusLogger) getSpan() string {
-	spanStr := fmt.Sprintf("%v", s.span)
-	if len(spanStr) > 0 && spanStr != "{}" && s.span != nil {
+	if s.span == nil {
+		return ""
+	}
+	spanStr := s.span.String()
+	if len(spanStr) > 0 && spanStr != "{}" {
 		return fmt.Sprintf(" span=%v", spanStr)
 	}
 	return ""
@@ -204,7 +207,7 @@ func New(ctx context.Context) (context.Context, logger.Logger) {
 
 // FromSpan - creates a new logruslogger from context, operation and span
 // and returns context with it, logger, and a function to defer
-func FromSpan(ctx context.Context, span opentracing.Span, operation string) (context.Context, logger.Logger, func()) {
+func FromSpan(ctx context.Context, span tracing.Span, operation string) (context.Context, logger.Logger, func()) {
 	logrus.SetLevel(logrus.TraceLevel)
 	entry := logrus.WithTime(time.Now()).WithFields(logger.Fields(ctx))
 
@@ -23,11 +23,8 @@ import (
 	"runtime/debug"
 	"strings"
 
-	"github.com/opentracing/opentracing-go"
-	"github.com/opentracing/opentracing-go/log"
-
-	"github.com/networkservicemesh/sdk/pkg/tools/jaeger"
 	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
 )
 
 const (
@@ -38,7 +35,7 @@ const (
 // spanlogger - provides a way to log via opentracing spans
 type spanLogger struct {
 	operation string
-	span      opentracing.Span
+	span      tracing.Span
 	entries   map[interface{}]interface{}
 }
 
@@ -101,7 +98,7 @@ func (s *spanLogger) Object(k, v interface{}) {
 				msg = fmt.Sprint(v)
 			}
 
-			s.span.LogFields(log.Object(k.(string), limitString(msg)))
+			s.span.LogKV(k, limitString(msg))
 			for k, v := range s.entries {
 				s.span.LogKV(k, v)
 			}
@@ -130,7 +127,7 @@ func (s *spanLogger) log(level string, v ...interface{}) {
 func (s *spanLogger) logf(level, format string, v ...interface{}) {
 	if s.span != nil {
 		if v != nil {
-			s.span.LogFields(log.String("event", level), log.String("message", fmt.Sprintf(format, v...)))
+			s.span.LogKV("event", level, "message", fmt.Sprintf(format, v...))
 			for k, v := range s.entries {
 				s.span.LogKV(k, v)
 			}
@@ -139,10 +136,10 @@ func (s *spanLogger) logf(level, format string, v ...interface{}) {
 }
 
 // newSpanLogger - creates a new spanLogger from context and operation
-func newSpanLogger(ctx context.Context, operation string) (context.Context, logger.Logger, opentracing.Span, func()) {
-	var span opentracing.Span
-	if jaeger.IsOpentracingEnabled() {
-		span, ctx = opentracing.StartSpanFromContext(ctx, operation)
+func newSpanLogger(ctx context.Context, operation string) (context.Context, logger.Logger, tracing.Span, func()) {
+	var span tracing.Span
+	if tracing.IsEnabled() {
+		ctx, span = tracing.StartSpan(ctx, operation)
 	}
 	newLog := &spanLogger{
 		span:      span,
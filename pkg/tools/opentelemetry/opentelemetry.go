@@ -0,0 +1,102 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package opentelemetry provides an OpenTelemetry tracing backend exporting the spans over OTLP and propagating the
+// W3C trace context through the gRPC metadata
+package opentelemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/logger/logruslogger"
+	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
+)
+
+const (
+	endpointEnv     = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	endpointDefault = "localhost:4317"
+)
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+// InitOpenTelemetry - creates an OpenTelemetry tracer provider sampling 100% of traces and sets it as the global
+// OpenTelemetry tracer provider and as the global tracing.Provider. By default the spans are exported over OTLP to
+// the OTEL_EXPORTER_OTLP_ENDPOINT (localhost:4317 if not set) collector, use WithExporter to export them somewhere else (e.g. in-memory exporter
+// from go.opentelemetry.io/otel/sdk/export/trace/tracetest for the tests).
+func InitOpenTelemetry(ctx context.Context, service string, options ...Option) io.Closer {
+	if !tracing.IsEnabled() {
+		return closerFunc(func() error { return nil })
+	}
+
+	o := &initOptions{
+		endpoint: os.Getenv(endpointEnv),
+	}
+	if o.endpoint == "" {
+		o.endpoint = endpointDefault
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+
+	_, log := logruslogger.New(ctx)
+
+	exporterOption := sdktrace.WithSyncer(o.exporter)
+	if o.exporter == nil {
+		driver := otlpgrpc.NewDriver(
+			otlpgrpc.WithEndpoint(o.endpoint),
+			otlpgrpc.WithInsecure(),
+		)
+
+		exporter, err := otlp.NewExporter(ctx, driver)
+		if err != nil {
+			log.Errorf("ERROR: cannot init OTLP exporter: %v\n", err)
+			return closerFunc(func() error { return nil })
+		}
+		exporterOption = sdktrace.WithBatcher(exporter)
+	}
+
+	if hostname, err := os.Hostname(); err == nil {
+		service = fmt.Sprintf("%s@%s", service, hostname)
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		exporterOption,
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.ServiceNameKey.String(service))),
+	)
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	tracing.SetProvider(NewProvider(tracerProvider))
+
+	return closerFunc(func() error {
+		return tracerProvider.Shutdown(context.Background())
+	})
+}
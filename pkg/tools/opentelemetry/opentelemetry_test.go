@@ -0,0 +1,191 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opentelemetry_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/stretchr/testify/require"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+	"go.opentelemetry.io/otel/sdk/export/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/null"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	registrychain "github.com/networkservicemesh/sdk/pkg/registry/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/tools/logger"
+	"github.com/networkservicemesh/sdk/pkg/tools/opentelemetry"
+	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
+)
+
+func initTracing(t *testing.T) (exporter *tracetest.InMemoryExporter, cleanup func()) {
+	exporter = tracetest.NewInMemoryExporter()
+	closer := opentelemetry.InitOpenTelemetry(context.Background(), "test", opentelemetry.WithExporter(exporter))
+
+	tracingEnabled := logger.IsTracingEnabled()
+	logger.EnableTracing(true)
+
+	return exporter, func() {
+		logger.EnableTracing(tracingEnabled)
+		tracing.SetProvider(nil)
+		require.NoError(t, closer.Close())
+	}
+}
+
+func startServer(t *testing.T, register func(s *grpc.Server)) (cc *grpc.ClientConn, cleanup func()) {
+	s := grpc.NewServer(tracing.WithTracing()...)
+	register(s)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = s.Serve(l)
+	}()
+
+	cc, err = grpc.Dial(l.Addr().String(), append(tracing.WithTracingDial(), grpc.WithInsecure(), grpc.WithBlock())...)
+	require.NoError(t, err)
+
+	return cc, func() {
+		_ = cc.Close()
+		s.Stop()
+	}
+}
+
+func findSpan(spans []*export.SpanSnapshot, kind trace.SpanKind, parent trace.SpanID) *export.SpanSnapshot {
+	for _, span := range spans {
+		if span.SpanKind == kind && span.ParentSpanID == parent {
+			return span
+		}
+	}
+	return nil
+}
+
+func TestOpenTelemetry_UnaryPropagation(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	exporter, cleanup := initTracing(t)
+	defer cleanup()
+
+	cc, closeServer := startServer(t, func(s *grpc.Server) {
+		networkservice.RegisterNetworkServiceServer(s, chain.NewNetworkServiceServer(null.NewServer()))
+	})
+	defer closeServer()
+
+	ctx, span := tracing.StartSpan(context.Background(), "test")
+	_, err := networkservice.NewNetworkServiceClient(cc).Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "id"},
+	})
+	require.NoError(t, err)
+	span.Finish()
+
+	spans := exporter.GetSpans()
+	root := findSpan(spans, trace.SpanKindInternal, trace.SpanID{})
+	require.NotNil(t, root)
+
+	client := findSpan(spans, trace.SpanKindClient, root.SpanContext.SpanID)
+	require.NotNil(t, client)
+
+	server := findSpan(spans, trace.SpanKindServer, client.SpanContext.SpanID)
+	require.NotNil(t, server)
+	require.Equal(t, root.SpanContext.TraceID, server.SpanContext.TraceID)
+
+	element := findSpan(spans, trace.SpanKindInternal, server.SpanContext.SpanID)
+	require.NotNil(t, element)
+	require.NotEmpty(t, element.MessageEvents)
+}
+
+func TestOpenTelemetry_StreamPropagation(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	exporter, cleanup := initTracing(t)
+	defer cleanup()
+
+	cc, closeServer := startServer(t, func(s *grpc.Server) {
+		registry.RegisterNetworkServiceEndpointRegistryServer(s, registrychain.NewNetworkServiceEndpointRegistryServer(
+			memory.NewNetworkServiceEndpointRegistryServer(),
+		))
+	})
+	defer closeServer()
+
+	c := registry.NewNetworkServiceEndpointRegistryClient(cc)
+
+	_, err := c.Register(context.Background(), &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+
+	exporter.Reset()
+
+	ctx, span := tracing.StartSpan(context.Background(), "test")
+	stream, err := c.Find(ctx, &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+	})
+	require.NoError(t, err)
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	require.Equal(t, io.EOF, err)
+	span.Finish()
+
+	spans := exporter.GetSpans()
+	root := findSpan(spans, trace.SpanKindInternal, trace.SpanID{})
+	require.NotNil(t, root)
+
+	client := findSpan(spans, trace.SpanKindClient, root.SpanContext.SpanID)
+	require.NotNil(t, client)
+
+	server := findSpan(spans, trace.SpanKindServer, client.SpanContext.SpanID)
+	require.NotNil(t, server)
+	require.Equal(t, root.SpanContext.TraceID, server.SpanContext.TraceID)
+
+	require.NotNil(t, findSpan(spans, trace.SpanKindInternal, server.SpanContext.SpanID))
+}
+
+func TestOpenTelemetry_StreamCanceled(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	exporter, cleanup := initTracing(t)
+	defer cleanup()
+
+	cc, closeServer := startServer(t, func(s *grpc.Server) {
+		registry.RegisterNetworkServiceEndpointRegistryServer(s, registrychain.NewNetworkServiceEndpointRegistryServer(
+			memory.NewNetworkServiceEndpointRegistryServer(),
+		))
+	})
+	defer closeServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := registry.NewNetworkServiceEndpointRegistryClient(cc).Find(ctx, &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+		Watch:                  true,
+	})
+	require.NoError(t, err)
+
+	// The stream is never received from, the client span should still end on the stream context done
+	cancel()
+
+	require.Eventually(t, func() bool {
+		return findSpan(exporter.GetSpans(), trace.SpanKindClient, trace.SpanID{}) != nil
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opentelemetry
+
+import (
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+)
+
+type initOptions struct {
+	endpoint string
+	exporter export.SpanExporter
+}
+
+// Option is an option pattern for InitOpenTelemetry
+type Option func(o *initOptions)
+
+// WithEndpoint sets the OTLP collector endpoint. Default is OTEL_EXPORTER_OTLP_ENDPOINT env or localhost:4317.
+func WithEndpoint(endpoint string) Option {
+	return func(o *initOptions) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithExporter sets the exporter to synchronously export the spans to instead of the OTLP one
+func WithExporter(exporter export.SpanExporter) Option {
+	return func(o *initOptions) {
+		o.exporter = exporter
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opentelemetry
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/label"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
+)
+
+const (
+	instrumentationName = "github.com/networkservicemesh/sdk"
+	logEventName        = "log"
+)
+
+type openTelemetryProvider struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// NewProvider creates a new tracing.Provider backed by the OpenTelemetry tracer provider, the trace context is
+// propagated through the gRPC metadata in the W3C Trace Context format
+func NewProvider(tracerProvider trace.TracerProvider) tracing.Provider {
+	return &openTelemetryProvider{
+		tracer:     tracerProvider.Tracer(instrumentationName),
+		propagator: propagation.TraceContext{},
+	}
+}
+
+func (p *openTelemetryProvider) StartSpan(ctx context.Context, operation string) (context.Context, tracing.Span) {
+	ctx, span := p.tracer.Start(ctx, operation)
+	return ctx, &openTelemetrySpan{span: span}
+}
+
+func (p *openTelemetryProvider) StartLinkedSpan(ctx context.Context, operation string, links ...tracing.Link) (context.Context, tracing.Span) {
+	var spanLinks []trace.Link
+	for _, link := range links {
+		if spanContext, ok := link.(trace.SpanContext); ok {
+			spanLinks = append(spanLinks, trace.Link{SpanContext: spanContext})
+		}
+	}
+	ctx, span := p.tracer.Start(ctx, operation, trace.WithNewRoot(), trace.WithLinks(spanLinks...))
+	return ctx, &openTelemetrySpan{span: span}
+}
+
+func (p *openTelemetryProvider) LinkFromContext(ctx context.Context) tracing.Link {
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		return spanContext
+	}
+	return nil
+}
+
+func (p *openTelemetryProvider) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(p.unaryServerInterceptor),
+		grpc.StreamInterceptor(p.streamServerInterceptor),
+	}
+}
+
+func (p *openTelemetryProvider) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithUnaryInterceptor(p.unaryClientInterceptor),
+		grpc.WithStreamInterceptor(p.streamClientInterceptor),
+	}
+}
+
+type openTelemetrySpan struct {
+	span trace.Span
+}
+
+func (s *openTelemetrySpan) LogKV(alternatingKeyValues ...interface{}) {
+	attributes := make([]label.KeyValue, 0, len(alternatingKeyValues)/2)
+	for i := 0; i+1 < len(alternatingKeyValues); i += 2 {
+		attributes = append(attributes, label.Any(fmt.Sprint(alternatingKeyValues[i]), alternatingKeyValues[i+1]))
+	}
+	s.span.AddEvent(logEventName, trace.WithAttributes(attributes...))
+}
+
+func (s *openTelemetrySpan) Finish() {
+	s.span.End()
+}
+
+func (s *openTelemetrySpan) String() string {
+	spanContext := s.span.SpanContext()
+	if !spanContext.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s", spanContext.TraceID, spanContext.SpanID)
+}
@@ -20,33 +20,16 @@
 package opentracing
 
 import (
-	"context"
-
-	"github.com/grpc-ecosystem/grpc-opentracing/go/otgrpc"
 	"github.com/opentracing/opentracing-go"
 	"google.golang.org/grpc"
-	"google.golang.org/protobuf/proto"
 
-	"github.com/networkservicemesh/sdk/pkg/tools/jaeger"
+	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
 )
 
 // WithTracing - returns array of grpc.ServerOption that should be passed to grpc.Dial to enable opentracing
 func WithTracing() []grpc.ServerOption {
-	if jaeger.IsOpentracingEnabled() {
-		interceptor := func(
-			ctx context.Context,
-			req interface{},
-			info *grpc.UnaryServerInfo,
-			handler grpc.UnaryHandler,
-		) (resp interface{}, err error) {
-			return otgrpc.OpenTracingServerInterceptor(opentracing.GlobalTracer())(ctx, proto.Clone(req.(proto.Message)), info, handler)
-		}
-		return []grpc.ServerOption{
-			grpc.UnaryInterceptor(
-				interceptor),
-			grpc.StreamInterceptor(
-				otgrpc.OpenTracingStreamServerInterceptor(opentracing.GlobalTracer())),
-		}
+	if tracing.IsEnabled() {
+		return NewProvider(opentracing.GlobalTracer()).ServerOptions()
 	}
 	return []grpc.ServerOption{
 		grpc.EmptyServerOption{},
@@ -55,23 +38,8 @@ func WithTracing() []grpc.ServerOption {
 
 // WithTracingDial returns array of grpc.DialOption that should be passed to grpc.Dial to enable opentracing
 func WithTracingDial() []grpc.DialOption {
-	if jaeger.IsOpentracingEnabled() {
-		interceptor := func(
-			ctx context.Context,
-			method string,
-			req, reply interface{},
-			cc *grpc.ClientConn,
-			invoker grpc.UnaryInvoker,
-			opts ...grpc.CallOption,
-		) error {
-			return otgrpc.OpenTracingClientInterceptor(opentracing.GlobalTracer())(ctx, method, proto.Clone(req.(proto.Message)), reply, cc, invoker, opts...)
-		}
-		return []grpc.DialOption{
-			grpc.WithUnaryInterceptor(
-				interceptor),
-			grpc.WithStreamInterceptor(
-				otgrpc.OpenTracingStreamClientInterceptor(opentracing.GlobalTracer())),
-		}
+	if tracing.IsEnabled() {
+		return NewProvider(opentracing.GlobalTracer()).DialOptions()
 	}
 	return []grpc.DialOption{
 		grpc.EmptyDialOption{},
@@ -0,0 +1,28 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opentracing
+
+import (
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
+)
+
+// NewProvider creates a new tracing.Provider backed by the opentracing tracer
+func NewProvider(tracer opentracing.Tracer) tracing.Provider {
+	return tracing.NewOpenTracingProvider(tracer)
+}
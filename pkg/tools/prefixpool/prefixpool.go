@@ -235,6 +235,38 @@ func (impl *PrefixPool) Extract(connectionID string, family networkservice.IpFam
 	return &net.IPNet{IP: src, Mask: ipNet.Mask}, &net.IPNet{IP: dst, Mask: ipNet.Mask}, requested, nil
 }
 
+// ExtractExtraPrefixes extracts prefixes for the requests from the available ones not intersecting with the
+// excludedPrefixes and keeps them for the connection until Release
+func (impl *PrefixPool) ExtractExtraPrefixes(connectionID string, excludedPrefixes []string, requests ...*networkservice.ExtraPrefixRequest) (requested []string, err error) {
+	impl.mutex.Lock()
+	defer impl.mutex.Unlock()
+
+	if _, ok := impl.connections[connectionID]; ok {
+		return nil, errors.Errorf("connection already has extracted prefixes: %s", connectionID)
+	}
+
+	available, excluded, err := excludePrefixes(impl.prefixes, excludedPrefixes...)
+	if err != nil {
+		return nil, err
+	}
+
+	requested, remaining, err := ExtractPrefixes(available, requests...)
+	if err != nil {
+		return nil, err
+	}
+
+	if remaining, err = releasePrefixes(remaining, excluded...); err != nil {
+		return nil, err
+	}
+
+	impl.prefixes = remaining
+
+	impl.connections[connectionID] = &connectionRecord{
+		prefixes: requested,
+	}
+	return requested, nil
+}
+
 // Release releases prefixes from the connection
 func (impl *PrefixPool) Release(connectionID string) error {
 	impl.mutex.Lock()
@@ -251,9 +283,11 @@ func (impl *PrefixPool) Release(connectionID string) error {
 		return err
 	}
 
-	remaining, err = releasePrefixes(remaining, conn.ipNet.String())
-	if err != nil {
-		return err
+	if conn.ipNet != nil {
+		remaining, err = releasePrefixes(remaining, conn.ipNet.String())
+		if err != nil {
+			return err
+		}
 	}
 
 	impl.prefixes = remaining
@@ -268,6 +302,9 @@ func (impl *PrefixPool) GetConnectionInformation(connectionID string) (ipNet str
 	if conn == nil {
 		return "", nil, errors.Errorf("No connection with id: %s is found", connectionID)
 	}
+	if conn.ipNet == nil {
+		return "", conn.prefixes, nil
+	}
 	return conn.ipNet.String(), conn.prefixes, nil
 }
 
@@ -323,7 +360,7 @@ func ExtractPrefixes(prefixes []string, requests ...*networkservice.ExtraPrefixR
 	// We need to firstly find required prefixes available.
 	for _, request := range requests {
 		for i := uint32(0); i < request.RequiredNumber; i++ {
-			prefix, leftPrefixes, err := extractPrefix(newPrefixes, request.PrefixLen)
+			prefix, leftPrefixes, err := extractPrefix(newPrefixes, request.PrefixLen, request.GetAddrFamily().GetFamily())
 			if err != nil {
 				return nil, prefixes, err
 			}
@@ -334,7 +371,7 @@ func ExtractPrefixes(prefixes []string, requests ...*networkservice.ExtraPrefixR
 	// We need to fit some more prefixes up to Requested ones
 	for _, request := range requests {
 		for i := request.RequiredNumber; i < request.RequestedNumber; i++ {
-			prefix, leftPrefixes, err := extractPrefix(newPrefixes, request.PrefixLen)
+			prefix, leftPrefixes, err := extractPrefix(newPrefixes, request.PrefixLen, request.GetAddrFamily().GetFamily())
 			if err != nil {
 				// It seems there is no more prefixes available, but since we have all Required already we could go.
 				break
@@ -349,7 +386,7 @@ func ExtractPrefixes(prefixes []string, requests ...*networkservice.ExtraPrefixR
 	return result, newPrefixes, nil
 }
 
-func extractPrefix(prefixes []string, prefixLen uint32) (retPrefix string, retLeftPrefixes []string, retError error) {
+func extractPrefix(prefixes []string, prefixLen uint32, family networkservice.IpFamily_Family) (retPrefix string, retLeftPrefixes []string, retError error) {
 	// Check if we already have required CIDR
 	maxPrefix := 0
 	maxPrefixIdx := -1
@@ -357,7 +394,7 @@ func extractPrefix(prefixes []string, prefixLen uint32) (retPrefix string, retLe
 	// Check if we already have required prefix,
 	for idx, prefix := range prefixes {
 		_, netip, err := net.ParseCIDR(prefix)
-		if err != nil {
+		if err != nil || !isFamily(netip, family) {
 			continue
 		}
 		parentLen, _ := netip.Mask.Size()
@@ -411,6 +448,45 @@ func extractPrefix(prefixes []string, prefixLen uint32) (retPrefix string, retLe
 	return rootCIDRNet.String(), resultPrefixes, nil
 }
 
+func isFamily(ipNet *net.IPNet, family networkservice.IpFamily_Family) bool {
+	if family == networkservice.IpFamily_IPV6 {
+		return ipNet.IP.To4() == nil
+	}
+	return ipNet.IP.To4() != nil
+}
+
+// excludePrefixes removes the excluded ranges from the prefixes, the removed parts are returned as excluded
+func excludePrefixes(prefixes []string, excludedPrefixes ...string) (remaining, excluded []string, err error) {
+	remaining = append([]string{}, prefixes...)
+	for _, excludedPrefix := range excludedPrefixes {
+		_, subnetExclude, parseErr := net.ParseCIDR(excludedPrefix)
+		if parseErr != nil {
+			return nil, nil, errors.Wrapf(parseErr, "Wrong CIDR: %v", excludedPrefix)
+		}
+
+		var left []string
+		for _, prefix := range remaining {
+			_, subnetPrefix, _ := net.ParseCIDR(prefix)
+			intersecting, excludedIsBigger := intersect(subnetExclude, subnetPrefix)
+			switch {
+			case !intersecting:
+				left = append(left, prefix)
+			case excludedIsBigger || subnetExclude.String() == subnetPrefix.String():
+				excluded = append(excluded, prefix)
+			default:
+				parts, splitErr := extractSubnet(subnetPrefix, subnetExclude)
+				if splitErr != nil {
+					return nil, nil, splitErr
+				}
+				left = append(left, parts...)
+				excluded = append(excluded, subnetExclude.String())
+			}
+		}
+		remaining = left
+	}
+	return remaining, excluded, nil
+}
+
 func reverse(values []string) []string {
 	newValues := make([]string, len(values))
 
@@ -241,3 +241,72 @@ func TestPrefixPoolValidation(t *testing.T) {
 		require.Equal(t, &net.ParseError{Type: "CIDR address", Text: "10.20.0.0/56"}, err)
 	}
 }
+
+func TestExtractPrefixes_MixedFamilies(t *testing.T) {
+	newPrefixes, prefixes, err := prefixpool.ExtractPrefixes([]string{"10.10.1.0/24", "100::/64"},
+		&networkservice.ExtraPrefixRequest{
+			AddrFamily:      &networkservice.IpFamily{Family: networkservice.IpFamily_IPV6},
+			RequiredNumber:  1,
+			RequestedNumber: 1,
+			PrefixLen:       120,
+		},
+		&networkservice.ExtraPrefixRequest{
+			AddrFamily:      &networkservice.IpFamily{Family: networkservice.IpFamily_IPV4},
+			RequiredNumber:  1,
+			RequestedNumber: 1,
+			PrefixLen:       28,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"100::/120", "10.10.1.0/28"}, newPrefixes)
+	require.Len(t, prefixes, 4+56)
+
+	_, _, err = prefixpool.ExtractPrefixes([]string{"100::/64"},
+		&networkservice.ExtraPrefixRequest{
+			AddrFamily:      &networkservice.IpFamily{Family: networkservice.IpFamily_IPV4},
+			RequiredNumber:  1,
+			RequestedNumber: 1,
+			PrefixLen:       28,
+		},
+	)
+	require.Error(t, err)
+}
+
+func TestExtractExtraPrefixes(t *testing.T) {
+	pool, err := prefixpool.New("10.10.1.0/24", "100::/64")
+	require.NoError(t, err)
+
+	requests := []*networkservice.ExtraPrefixRequest{
+		{
+			AddrFamily:      &networkservice.IpFamily{Family: networkservice.IpFamily_IPV4},
+			RequiredNumber:  1,
+			RequestedNumber: 2,
+			PrefixLen:       26,
+		},
+		{
+			AddrFamily:      &networkservice.IpFamily{Family: networkservice.IpFamily_IPV6},
+			RequiredNumber:  1,
+			RequestedNumber: 1,
+			PrefixLen:       65,
+		},
+	}
+
+	prefixes, err := pool.ExtractExtraPrefixes("c1", []string{"10.10.1.0/26", "100::/65"}, requests...)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"10.10.1.64/26", "10.10.1.128/26", "100::8000:0:0:0/65"}, prefixes)
+
+	_, err = pool.ExtractExtraPrefixes("c1", nil, requests...)
+	require.Error(t, err)
+
+	// Excluded prefixes are still available
+	prefixes, err = pool.ExtractExtraPrefixes("c2", nil, requests...)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"10.10.1.0/26", "10.10.1.192/26", "100::/65"}, prefixes)
+
+	_, err = pool.ExtractExtraPrefixes("c3", nil, requests...)
+	require.Error(t, err)
+
+	require.NoError(t, pool.Release("c1"))
+	require.NoError(t, pool.Release("c2"))
+	require.ElementsMatch(t, []string{"10.10.1.0/24", "100::/64"}, pool.GetPrefixes())
+}
@@ -47,8 +47,8 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/registry/core/chain"
 	"github.com/networkservicemesh/sdk/pkg/tools/addressof"
 	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
-	"github.com/networkservicemesh/sdk/pkg/tools/opentracing"
 	"github.com/networkservicemesh/sdk/pkg/tools/token"
+	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
 )
 
 const defaultContextTimeout = time.Second * 15
@@ -107,15 +107,7 @@ func (b *Builder) Build() *Domain {
 		domain.Registry = b.newRegistry(ctx, domain.RegistryProxy.URL)
 	}
 	for i := 0; i < b.nodesCount; i++ {
-		var node = new(Node)
-		node.NSMgr = b.newNSMgr(ctx, domain.Registry.URL)
-		forwarderName := "cross-nse-" + uuid.New().String()
-		forwarderRegistrationClient := chain.NewNetworkServiceEndpointRegistryClient(
-			interpose_reg.NewNetworkServiceEndpointRegistryClient(),
-			adapter_registry.NetworkServiceEndpointServerToClient(node.NSMgr.NetworkServiceEndpointRegistryServer()),
-		)
-		node.Forwarder = b.newCrossConnectNSE(ctx, forwarderName, node.NSMgr.URL, forwarderRegistrationClient)
-		domain.Nodes = append(domain.Nodes, node)
+		domain.Nodes = append(domain.Nodes, b.newNode(ctx, domain.Registry.URL))
 	}
 	domain.resources, b.resources = b.resources, nil
 	return domain
@@ -208,7 +200,7 @@ func (b *Builder) newNSMgrProxy(ctx context.Context) *EndpointEntry {
 	}
 }
 
-func (b *Builder) newNSMgr(ctx context.Context, registryURL *url.URL) *NSMgrEntry {
+func (b *Builder) newNode(ctx context.Context, registryURL *url.URL) *Node {
 	if b.supplyNSMgr == nil {
 		panic("nodes without managers are not supported")
 	}
@@ -226,24 +218,62 @@ func (b *Builder) newNSMgr(ctx context.Context, registryURL *url.URL) *NSMgrEntr
 		Url:  serveURL.String(),
 	}
 
+	node := new(Node)
+	nsmgrCtx, cancelNSMgr := context.WithCancel(ctx)
+	var nsmgrDone <-chan struct{}
+	node.NSMgr, nsmgrDone = b.newNSMgr(nsmgrCtx, nsmgrReg, serveURL, registryCC)
+
+	forwarderName := "cross-nse-" + uuid.New().String()
+	node.Forwarder = b.newCrossConnectNSE(ctx, forwarderName, node.NSMgr.URL, newForwarderRegistrationClient(node.NSMgr))
+
+	node.restartNSMgr = func() {
+		cancelNSMgr()
+		<-nsmgrDone
+
+		nsmgrCtx, cancelNSMgr = context.WithCancel(ctx)
+		node.NSMgr, nsmgrDone = b.newNSMgr(nsmgrCtx, nsmgrReg.Clone(), serveURL, registryCC)
+
+		_, err := newForwarderRegistrationClient(node.NSMgr).Register(context.Background(), &registryapi.NetworkServiceEndpoint{
+			Url:  node.Forwarder.URL.String(),
+			Name: forwarderName,
+		})
+		b.require.NoError(err)
+	}
+
+	return node
+}
+
+func (b *Builder) newNSMgr(ctx context.Context, nsmgrReg *registryapi.NetworkServiceEndpoint, serveURL *url.URL, registryCC *grpc.ClientConn) (*NSMgrEntry, <-chan struct{}) {
 	mgr := b.supplyNSMgr(ctx, nsmgrReg, authorize.NewServer(), b.generateTokenFunc, registryCC, grpc.WithInsecure(), grpc.WithBlock(), grpc.WithDefaultCallOptions(grpc.WaitForReady(true)))
 
-	serve(ctx, serveURL, mgr.Register)
+	doneCh := serve(ctx, serveURL, mgr.Register, grpc.StatsHandler(mgr))
 	logger.Log(ctx).Infof("%v listen on: %v", nsmgrReg.Name, serveURL)
 	return &NSMgrEntry{
 		URL:   serveURL,
 		Nsmgr: mgr,
-	}
+	}, doneCh
+}
+
+func newForwarderRegistrationClient(mgr *NSMgrEntry) registryapi.NetworkServiceEndpointRegistryClient {
+	return chain.NewNetworkServiceEndpointRegistryClient(
+		interpose_reg.NewNetworkServiceEndpointRegistryClient(),
+		adapter_registry.NetworkServiceEndpointServerToClient(mgr.NetworkServiceEndpointRegistryServer()),
+	)
 }
 
-func serve(ctx context.Context, u *url.URL, register func(server *grpc.Server)) {
-	server := grpc.NewServer(opentracing.WithTracing()...)
+// serve serves the server on u until ctx is done, returns a channel closed when the server is stopped
+func serve(ctx context.Context, u *url.URL, register func(server *grpc.Server), options ...grpc.ServerOption) <-chan struct{} {
+	server := grpc.NewServer(append(tracing.WithTracing(), options...)...)
 	register(server)
 	errCh := grpcutils.ListenAndServe(ctx, u, server)
+	doneCh := make(chan struct{})
 	go func() {
+		defer close(doneCh)
 		select {
 		case <-ctx.Done():
 			logger.Log(ctx).Infof("Stop serve: %v", u.String())
+			for range errCh {
+			}
 			return
 		case err := <-errCh:
 			if err != nil {
@@ -251,6 +281,7 @@ func serve(ctx context.Context, u *url.URL, register func(server *grpc.Server))
 			}
 		}
 	}()
+	return doneCh
 }
 
 func (b *Builder) newCrossConnectNSE(ctx context.Context, name string, connectTo *url.URL, forwarderRegistrationClient registryapi.NetworkServiceEndpointRegistryClient) *EndpointEntry {
@@ -305,9 +336,14 @@ func (b *Builder) newRegistry(ctx context.Context, proxyRegistryURL *url.URL) *R
 	}
 }
 
+// dummyForwarder is needed to pass the Endpoint to the heal before it is created
+type dummyForwarder struct {
+	endpoint.Endpoint
+}
+
 func supplyDummyForwarder(ctx context.Context, name string, generateToken token.GeneratorFunc, connectTo *url.URL, dialOptions ...grpc.DialOption) endpoint.Endpoint {
-	var result endpoint.Endpoint
-	result = endpoint.NewServer(ctx,
+	result := new(dummyForwarder)
+	result.Endpoint = endpoint.NewServer(ctx,
 		name,
 		authorize.NewServer(),
 		generateToken,
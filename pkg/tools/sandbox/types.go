@@ -48,8 +48,15 @@ type SupplyRegistryProxyFunc func(ctx context.Context, dnsResolver dnsresolve.Re
 
 // Node is pair of Forwarder and NSMgr
 type Node struct {
-	Forwarder *EndpointEntry
-	NSMgr     *NSMgrEntry
+	Forwarder    *EndpointEntry
+	NSMgr        *NSMgrEntry
+	restartNSMgr func()
+}
+
+// RestartNSMgr stops the node NSMgr and starts a new one with the same registration and URL, the node Forwarder is
+// registered in the new NSMgr. Endpoints registered with the stopped NSMgr should be registered with the new one.
+func (n *Node) RestartNSMgr() {
+	n.restartNSMgr()
 }
 
 // RegistryEntry is pair of registry.Registry and url.URL
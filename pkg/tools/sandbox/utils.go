@@ -38,8 +38,9 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/authorize"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/clienturl"
 	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
-	"github.com/networkservicemesh/sdk/pkg/tools/opentracing"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
 	"github.com/networkservicemesh/sdk/pkg/tools/token"
+	"github.com/networkservicemesh/sdk/pkg/tools/tracing"
 )
 
 // GenerateTestToken generates test token
@@ -47,6 +48,15 @@ func GenerateTestToken(_ credentials.AuthInfo) (tokenValue string, expireTime ti
 	return "TestToken", time.Date(3000, 1, 1, 1, 1, 1, 1, time.UTC), nil
 }
 
+// GenerateExpiringToken returns a token generator generating test tokens expiring in duration by the clock.Clock from
+// ctx, so the token expiration can be driven by a fake clock
+func GenerateExpiringToken(ctx context.Context, duration time.Duration) token.GeneratorFunc {
+	clk := clock.FromContext(ctx)
+	return func(_ credentials.AuthInfo) (string, time.Time, error) {
+		return "TestToken", clk.Now().Add(duration), nil
+	}
+}
+
 // NewEndpoint creates endpoint and registers it into passed NSMgr.
 func NewEndpoint(ctx context.Context, nse *registry.NetworkServiceEndpoint, generatorFunc token.GeneratorFunc, mgr nsmgr.Nsmgr, additionalFunctionality ...networkservice.NetworkServiceServer) (*EndpointEntry, error) {
 	ep := endpoint.NewServer(ctx, nse.Name, authorize.NewServer(), generatorFunc, additionalFunctionality...)
@@ -64,7 +74,7 @@ func NewEndpoint(ctx context.Context, nse *registry.NetworkServiceEndpoint, gene
 		nse.Url = u.String()
 	}
 	if nse.ExpirationTime == nil {
-		deadline := time.Now().Add(time.Hour)
+		deadline := clock.FromContext(ctx).Now().Add(time.Hour)
 		expirationTime, err := ptypes.TimestampProto(deadline)
 		if err != nil {
 			return nil, err
@@ -92,7 +102,7 @@ func NewClient(ctx context.Context, generatorFunc token.GeneratorFunc, connectTo
 			nil,
 			generatorFunc,
 			additionalFunctionality...),
-		append(opentracing.WithTracingDial(), grpc.WithBlock(), grpc.WithInsecure())...)
+		append(tracing.WithTracingDial(), grpc.WithBlock(), grpc.WithInsecure())...)
 }
 
 // NewCrossConnectClientFactory is a client.NewCrossConnectClientFactory with some fields preset for testing
@@ -0,0 +1,139 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler_test
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/scheduler"
+)
+
+// The benchmarks compare the memory and CPU used for b.N pending connection timers by the separate runtime timers
+// (timeout, refresh, expire) and goroutines (registry refresh) with the shared Scheduler. Besides the Go allocations
+// they report "mem-B/op" - the heap and stack memory kept per pending timer.
+
+const benchmarkTimeout = time.Hour
+
+func BenchmarkTimeAfterFunc(b *testing.B) {
+	timers := make([]*time.Timer, b.N)
+	benchmarkPending(b, func(i int) {
+		timers[i] = time.AfterFunc(benchmarkTimeout, func() {})
+	}, func(i int) {
+		timers[i].Stop()
+	})
+}
+
+func BenchmarkSchedulerAfterFunc(b *testing.B) {
+	s := scheduler.New(clock.FromContext(context.Background()))
+	tasks := make([]*scheduler.Task, b.N)
+	benchmarkPending(b, func(i int) {
+		tasks[i] = s.AfterFunc(benchmarkTimeout, func() {})
+	}, func(i int) {
+		tasks[i].Stop()
+	})
+}
+
+func BenchmarkTimeAfterFunc_Reset(b *testing.B) {
+	timers := make([]*time.Timer, b.N)
+	for i := range timers {
+		timers[i] = time.AfterFunc(benchmarkTimeout, func() {})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := range timers {
+		timers[i].Stop()
+		timers[i].Reset(benchmarkTimeout)
+	}
+
+	b.StopTimer()
+
+	for i := range timers {
+		timers[i].Stop()
+	}
+}
+
+func BenchmarkSchedulerAfterFunc_Reset(b *testing.B) {
+	s := scheduler.New(clock.FromContext(context.Background()))
+	tasks := make([]*scheduler.Task, b.N)
+	for i := range tasks {
+		tasks[i] = s.AfterFunc(benchmarkTimeout, func() {})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := range tasks {
+		tasks[i].Reset(benchmarkTimeout)
+	}
+
+	b.StopTimer()
+
+	for i := range tasks {
+		tasks[i].Stop()
+	}
+}
+
+func BenchmarkGoroutineAfter(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(b.N)
+	benchmarkPending(b, func(int) {
+		go func() {
+			defer wg.Done()
+			select {
+			case <-ctx.Done():
+			case <-time.After(benchmarkTimeout):
+			}
+		}()
+	}, func(int) {})
+	cancel()
+	wg.Wait()
+}
+
+// benchmarkPending runs schedule for b.N timers, measures the memory kept by them and then runs stop for all of them
+func benchmarkPending(b *testing.B, schedule, stop func(i int)) {
+	b.ReportAllocs()
+
+	runtime.GC()
+	before := memInUse()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		schedule(i)
+	}
+	b.StopTimer()
+
+	runtime.GC()
+	b.ReportMetric(float64(memInUse()-before)/float64(b.N), "mem-B/op")
+
+	for i := 0; i < b.N; i++ {
+		stop(i)
+	}
+}
+
+func memInUse() int64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return int64(stats.HeapInuse + stats.StackInuse)
+}
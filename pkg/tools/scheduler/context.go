@@ -0,0 +1,43 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+type schedulerKey struct{}
+
+// WithScheduler returns a new context with the Scheduler, all the chain elements created with this context share it
+func WithScheduler(parent context.Context, scheduler *Scheduler) context.Context {
+	if parent == nil {
+		panic("cannot create context from nil parent")
+	}
+	return context.WithValue(parent, schedulerKey{}, scheduler)
+}
+
+// FromContext returns the Scheduler from the context, or a new Scheduler driven by the clock.Clock from the context
+// if there is no Scheduler in the context. The new Scheduler is not stored in the context, so the chains (endpoint,
+// client, nsmgr, registry) inject a single Scheduler with WithScheduler to be shared by all their elements.
+func FromContext(ctx context.Context) *Scheduler {
+	if scheduler, ok := ctx.Value(schedulerKey{}).(*Scheduler); ok {
+		return scheduler
+	}
+	return New(clock.FromContext(ctx))
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scheduler provides the shared timer scheduler for the chain elements having a timer per connection or per
+// registration
+package scheduler
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+// Scheduler runs the scheduled functions at their deadlines. All the tasks are kept in a single deadline ordered heap
+// served by a single clock.Timer armed for the earliest deadline, so a Scheduler doesn't have any goroutines and
+// runtime timers per task.
+type Scheduler struct {
+	clock clock.Clock
+
+	mu       sync.Mutex
+	tasks    taskHeap
+	timer    clock.Timer
+	deadline time.Time // the timer deadline, zero if the timer is not armed
+	seq      uint64
+}
+
+// New returns a new Scheduler driven by clk
+func New(clk clock.Clock) *Scheduler {
+	return &Scheduler{
+		clock: clk,
+	}
+}
+
+// AfterFunc schedules f to be called in its own goroutine after the duration d, the same as clock.Clock.AfterFunc.
+// The returned Task can be used to cancel or reschedule the call.
+func (s *Scheduler) AfterFunc(d time.Duration, f func()) *Task {
+	task := &Task{
+		scheduler: s,
+		f:         f,
+		index:     -1,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.schedule(task, d)
+	return task
+}
+
+// Len returns the number of scheduled tasks
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.tasks)
+}
+
+func (s *Scheduler) schedule(task *Task, d time.Duration) {
+	s.seq++
+	task.seq = s.seq
+	task.deadline = s.clock.Now().Add(d)
+	heap.Push(&s.tasks, task)
+	s.arm()
+}
+
+func (s *Scheduler) unschedule(task *Task) bool {
+	if task.index < 0 {
+		return false
+	}
+	heap.Remove(&s.tasks, task.index)
+	return true
+}
+
+// arm arms the timer for the earliest task deadline if it is not armed for it or for an earlier deadline yet
+func (s *Scheduler) arm() {
+	if len(s.tasks) == 0 {
+		return
+	}
+	deadline := s.tasks[0].deadline
+	if !s.deadline.IsZero() && !deadline.Before(s.deadline) {
+		return
+	}
+	s.deadline = deadline
+
+	if s.timer == nil {
+		s.timer = s.clock.AfterFunc(s.clock.Until(deadline), s.run)
+		return
+	}
+	s.timer.Stop()
+	s.timer.Reset(s.clock.Until(deadline))
+}
+
+// run runs all the expired tasks and arms the timer for the next ones
+func (s *Scheduler) run() {
+	s.mu.Lock()
+
+	s.deadline = time.Time{}
+	now := s.clock.Now()
+
+	var expired []*Task
+	for len(s.tasks) > 0 && !s.tasks[0].deadline.After(now) {
+		expired = append(expired, heap.Pop(&s.tasks).(*Task))
+	}
+	s.arm()
+
+	s.mu.Unlock()
+
+	for _, task := range expired {
+		go task.f()
+	}
+}
+
+// Task is a scheduled function call handle
+type Task struct {
+	scheduler *Scheduler
+	f         func()
+	deadline  time.Time
+	seq       uint64
+	index     int // index in the scheduler heap, -1 if the task is not scheduled
+}
+
+var _ clock.Timer = (*Task)(nil)
+
+// C returns nil, Task has no channel the same as the clock.Clock.AfterFunc timers
+func (t *Task) C() <-chan time.Time {
+	return nil
+}
+
+// Stop cancels the call, returns false if the call has been already started or the Task has been already stopped
+func (t *Task) Stop() bool {
+	t.scheduler.mu.Lock()
+	defer t.scheduler.mu.Unlock()
+
+	return t.scheduler.unschedule(t)
+}
+
+// Reset reschedules the call to be done after the duration d, returns true if the Task had been scheduled before
+func (t *Task) Reset(d time.Duration) bool {
+	t.scheduler.mu.Lock()
+	defer t.scheduler.mu.Unlock()
+
+	active := t.scheduler.unschedule(t)
+	t.scheduler.schedule(t, d)
+	return active
+}
+
+type taskHeap []*Task
+
+func (h taskHeap) Len() int {
+	return len(h)
+}
+
+func (h taskHeap) Less(i, j int) bool {
+	if h[i].deadline.Equal(h[j].deadline) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].deadline.Before(h[j].deadline)
+}
+
+func (h taskHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *taskHeap) Push(x interface{}) {
+	task := x.(*Task)
+	task.index = len(*h)
+	*h = append(*h, task)
+}
+
+func (h *taskHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	task := old[n-1]
+	old[n-1] = nil
+	task.index = -1
+	*h = old[:n-1]
+	return task
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/scheduler"
+)
+
+const (
+	timeout = time.Second
+	tick    = 10 * time.Millisecond
+)
+
+func TestScheduler_AfterFunc(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	clockMock := clockmock.NewMock()
+	s := scheduler.New(clockMock)
+
+	var counts [3]int32
+	for i := len(counts) - 1; i >= 0; i-- {
+		i := i
+		s.AfterFunc(time.Duration(i+1)*time.Hour, func() {
+			atomic.AddInt32(&counts[i], 1)
+		})
+	}
+	require.Equal(t, 3, s.Len())
+
+	for i := range counts {
+		clockMock.Add(time.Hour)
+		i := i
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt32(&counts[i]) == 1
+		}, timeout, tick)
+		require.Equal(t, len(counts)-i-1, s.Len())
+	}
+	for i := range counts {
+		require.Equal(t, int32(1), atomic.LoadInt32(&counts[i]))
+	}
+}
+
+func TestScheduler_EarlierTask(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	clockMock := clockmock.NewMock()
+	s := scheduler.New(clockMock)
+
+	var count int32
+	s.AfterFunc(2*time.Hour, func() {
+		atomic.AddInt32(&count, 1)
+	})
+	// Task scheduled earlier than the current earliest one should not wait for it
+	s.AfterFunc(time.Hour, func() {
+		atomic.AddInt32(&count, 1)
+	})
+
+	clockMock.Add(time.Hour)
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&count) == 1
+	}, timeout, tick)
+	require.Equal(t, 1, s.Len())
+}
+
+func TestTask_StopReset(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	clockMock := clockmock.NewMock()
+	s := scheduler.New(clockMock)
+
+	var count int32
+	task := s.AfterFunc(time.Hour, func() {
+		atomic.AddInt32(&count, 1)
+	})
+
+	require.True(t, task.Stop())
+	require.False(t, task.Stop())
+	require.Equal(t, 0, s.Len())
+
+	clockMock.Add(time.Hour)
+	require.False(t, task.Reset(time.Hour))
+	require.True(t, task.Reset(2*time.Hour))
+
+	clockMock.Add(time.Hour)
+	require.Never(t, func() bool {
+		return atomic.LoadInt32(&count) > 0
+	}, 10*tick, tick)
+
+	clockMock.Add(time.Hour)
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&count) == 1
+	}, timeout, tick)
+	require.False(t, task.Stop())
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grpc-ecosystem/grpc-opentracing/go/otgrpc"
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+type openTracingProvider struct {
+	tracer opentracing.Tracer
+}
+
+// NewOpenTracingProvider creates a new Provider backed by the opentracing tracer. If tracer is nil, the global
+// opentracing tracer at the moment of the call is used, so the Provider follows opentracing.SetGlobalTracer.
+func NewOpenTracingProvider(tracer opentracing.Tracer) Provider {
+	return &openTracingProvider{
+		tracer: tracer,
+	}
+}
+
+func (p *openTracingProvider) getTracer() opentracing.Tracer {
+	if p.tracer == nil {
+		return opentracing.GlobalTracer()
+	}
+	return p.tracer
+}
+
+func (p *openTracingProvider) StartSpan(ctx context.Context, operation string) (context.Context, Span) {
+	span, ctx := opentracing.StartSpanFromContextWithTracer(ctx, p.getTracer(), operation)
+	return ctx, &openTracingSpan{span: span}
+}
+
+func (p *openTracingProvider) StartLinkedSpan(ctx context.Context, operation string, links ...Link) (context.Context, Span) {
+	var options []opentracing.StartSpanOption
+	for _, link := range links {
+		if spanContext, ok := link.(opentracing.SpanContext); ok {
+			options = append(options, opentracing.FollowsFrom(spanContext))
+		}
+	}
+	span := p.getTracer().StartSpan(operation, options...)
+	return opentracing.ContextWithSpan(ctx, span), &openTracingSpan{span: span}
+}
+
+func (p *openTracingProvider) LinkFromContext(ctx context.Context) Link {
+	if span := opentracing.SpanFromContext(ctx); span != nil {
+		return span.Context()
+	}
+	return nil
+}
+
+func (p *openTracingProvider) ServerOptions() []grpc.ServerOption {
+	tracer := p.getTracer()
+	interceptor := func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp interface{}, err error) {
+		return otgrpc.OpenTracingServerInterceptor(tracer)(ctx, proto.Clone(req.(proto.Message)), info, handler)
+	}
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(
+			interceptor),
+		grpc.StreamInterceptor(
+			otgrpc.OpenTracingStreamServerInterceptor(tracer)),
+	}
+}
+
+func (p *openTracingProvider) DialOptions() []grpc.DialOption {
+	tracer := p.getTracer()
+	interceptor := func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		return otgrpc.OpenTracingClientInterceptor(tracer)(ctx, method, proto.Clone(req.(proto.Message)), reply, cc, invoker, opts...)
+	}
+	return []grpc.DialOption{
+		grpc.WithUnaryInterceptor(
+			interceptor),
+		grpc.WithStreamInterceptor(
+			otgrpc.OpenTracingStreamClientInterceptor(tracer)),
+	}
+}
+
+type openTracingSpan struct {
+	span opentracing.Span
+}
+
+func (s *openTracingSpan) LogKV(alternatingKeyValues ...interface{}) {
+	s.span.LogKV(alternatingKeyValues...)
+}
+
+func (s *openTracingSpan) Finish() {
+	s.span.Finish()
+}
+
+func (s *openTracingSpan) String() string {
+	return fmt.Sprintf("%v", s.span)
+}
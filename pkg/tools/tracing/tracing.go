@@ -0,0 +1,177 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing provides a tracing backend agnostic API for the spans used by the trace chain elements and for the
+// gRPC trace context propagation. The backend is pluggable with SetProvider, see jaeger and opentelemetry packages.
+package tracing
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	tracingEnv     = "TRACER_ENABLED"
+	tracingDefault = true
+)
+
+// Span is a tracing backend agnostic span
+type Span interface {
+	// LogKV logs the alternating key-value pairs to the span
+	LogKV(alternatingKeyValues ...interface{})
+	// Finish finishes the span
+	Finish()
+	// String returns a short span description printed by the loggers
+	String() string
+}
+
+// Link is a backend specific reference to a span, it is used to link the spans of the background work (heal, refresh,
+// watches) to the span caused it
+type Link interface{}
+
+// Provider is a tracing backend
+type Provider interface {
+	// StartSpan starts a new span as a child of the span stored in ctx
+	StartSpan(ctx context.Context, operation string) (context.Context, Span)
+	// StartLinkedSpan starts a new root span linked to the links, even if there is a span stored in ctx
+	StartLinkedSpan(ctx context.Context, operation string, links ...Link) (context.Context, Span)
+	// LinkFromContext returns a Link to the span stored in ctx or nil if there is no such span
+	LinkFromContext(ctx context.Context) Link
+	// ServerOptions returns gRPC server options extracting the trace context from the incoming calls
+	ServerOptions() []grpc.ServerOption
+	// DialOptions returns gRPC dial options injecting the trace context into the outgoing calls
+	DialOptions() []grpc.DialOption
+}
+
+type providerHolder struct {
+	Provider
+}
+
+var (
+	provider        atomic.Value
+	defaultProvider = NewOpenTracingProvider(nil)
+)
+
+// IsEnabled returns false if tracing is disabled with the TRACER_ENABLED env variable
+func IsEnabled() bool {
+	str := os.Getenv(tracingEnv)
+	if str == "" {
+		return tracingDefault
+	}
+	val, err := strconv.ParseBool(str)
+	if err != nil {
+		return tracingDefault
+	}
+	return val
+}
+
+// SetProvider sets the global tracing Provider, nil resets it to the default one
+func SetProvider(p Provider) {
+	if p == nil {
+		p = defaultProvider
+	}
+	provider.Store(providerHolder{Provider: p})
+}
+
+// GetProvider returns the global tracing Provider. By default it is the opentracing Provider backed by the global
+// opentracing tracer.
+func GetProvider() Provider {
+	if holder, ok := provider.Load().(providerHolder); ok {
+		return holder.Provider
+	}
+	return defaultProvider
+}
+
+// StartSpan starts a new span with the global Provider
+func StartSpan(ctx context.Context, operation string) (context.Context, Span) {
+	return GetProvider().StartSpan(ctx, operation)
+}
+
+// StartLinkedSpan starts a new root span for the background work with the global Provider. The span is linked to the
+// spans caused the work, nil links are ignored.
+func StartLinkedSpan(ctx context.Context, operation string, links ...Link) (context.Context, Span) {
+	if !IsEnabled() {
+		return ctx, noopSpan{}
+	}
+	var nonNilLinks []Link
+	for _, link := range links {
+		if link != nil {
+			nonNilLinks = append(nonNilLinks, link)
+		}
+	}
+	return GetProvider().StartLinkedSpan(ctx, operation, nonNilLinks...)
+}
+
+// LinkFromContext returns a Link to the span stored in ctx with the global Provider
+func LinkFromContext(ctx context.Context) Link {
+	return GetProvider().LinkFromContext(ctx)
+}
+
+// WithTracing returns gRPC server options for the global Provider
+func WithTracing() []grpc.ServerOption {
+	if !IsEnabled() {
+		return noopProvider{}.ServerOptions()
+	}
+	return GetProvider().ServerOptions()
+}
+
+// WithTracingDial returns gRPC dial options for the global Provider
+func WithTracingDial() []grpc.DialOption {
+	if !IsEnabled() {
+		return noopProvider{}.DialOptions()
+	}
+	return GetProvider().DialOptions()
+}
+
+type noopProvider struct{}
+
+func (noopProvider) StartSpan(ctx context.Context, _ string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+func (noopProvider) StartLinkedSpan(ctx context.Context, _ string, _ ...Link) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+func (noopProvider) LinkFromContext(context.Context) Link {
+	return nil
+}
+
+func (noopProvider) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.EmptyServerOption{},
+	}
+}
+
+func (noopProvider) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.EmptyDialOption{},
+	}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) LogKV(...interface{}) {}
+
+func (noopSpan) Finish() {}
+
+func (noopSpan) String() string {
+	return ""
+}